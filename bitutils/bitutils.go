@@ -1,7 +1,9 @@
 package bitutils
 
 import (
+	"github.com/thedonutfactory/go-tfhe/csprng"
 	"github.com/thedonutfactory/go-tfhe/params"
+	"github.com/thedonutfactory/go-tfhe/proxyreenc"
 	"github.com/thedonutfactory/go-tfhe/tlwe"
 )
 
@@ -81,15 +83,85 @@ func U64ToBits(val uint64) []bool {
 	return ToBits(val, 64)
 }
 
+// ConvertI8 converts a slice of two's complement bits (LSB first) to an
+// int8. Slices shorter than 8 bits are sign extended from their last bit.
+func ConvertI8(bits []bool) int8 {
+	return int8(ConvertI64(bits))
+}
+
+// ConvertI16 converts a slice of two's complement bits to an int16
+func ConvertI16(bits []bool) int16 {
+	return int16(ConvertI64(bits))
+}
+
+// ConvertI32 converts a slice of two's complement bits to an int32
+func ConvertI32(bits []bool) int32 {
+	return int32(ConvertI64(bits))
+}
+
+// ConvertI64 converts a slice of two's complement bits to an int64
+func ConvertI64(bits []bool) int64 {
+	return int64(ConvertU64(SignExtend(bits, 64)))
+}
+
+// I8ToBits converts an int8 to its two's complement bits
+func I8ToBits(val int8) []bool {
+	return ToBits(uint64(val), 8)
+}
+
+// I16ToBits converts an int16 to its two's complement bits
+func I16ToBits(val int16) []bool {
+	return ToBits(uint64(val), 16)
+}
+
+// I32ToBits converts an int32 to its two's complement bits
+func I32ToBits(val int32) []bool {
+	return ToBits(uint64(val), 32)
+}
+
+// I64ToBits converts an int64 to its two's complement bits
+func I64ToBits(val int64) []bool {
+	return ToBits(uint64(val), 64)
+}
+
+// SignExtend returns bits widened to size by repeating the last (sign) bit,
+// or its size least significant bits if it is longer
+func SignExtend(bits []bool, size int) []bool {
+	result := make([]bool, size)
+	n := copy(result, bits)
+	if len(bits) > 0 && bits[len(bits)-1] {
+		for i := n; i < size; i++ {
+			result[i] = true
+		}
+	}
+	return result
+}
+
 // EncryptBits encrypts a slice of bits using the given secret key
 func EncryptBits(bits []bool, alpha float64, key []params.Torus) []*tlwe.TLWELv0 {
+	return EncryptBitsWithSource(bits, alpha, key, csprng.Fork())
+}
+
+// EncryptBitsWithSource encrypts a slice of bits using the given secret key,
+// drawing the masks and noise of all of them from src
+func EncryptBitsWithSource(bits []bool, alpha float64, key []params.Torus, src csprng.Source) []*tlwe.TLWELv0 {
 	result := make([]*tlwe.TLWELv0, len(bits))
 	for i, bit := range bits {
-		result[i] = tlwe.NewTLWELv0().EncryptBool(bit, alpha, key)
+		result[i] = tlwe.NewTLWELv0().EncryptBoolWithSource(bit, alpha, key, src)
 	}
 	return result
 }
 
+// EncryptBitsPublic encrypts a slice of bits with a public key, so the
+// secret key is not needed (see proxyreenc.PublicKey)
+func EncryptBitsPublic(bits []bool, pk proxyreenc.PublicKey) []*tlwe.TLWELv0 {
+	values := make([]params.Torus, len(bits))
+	for i, bit := range bits {
+		values[i] = proxyreenc.EncodeBool(bit)
+	}
+	return pk.EncryptTorusLv0(values)
+}
+
 // DecryptBits decrypts a slice of ciphertexts to bits
 func DecryptBits(ctxts []*tlwe.TLWELv0, key []params.Torus) []bool {
 	result := make([]bool, len(ctxts))
@@ -1,9 +1,13 @@
 package bitutils_test
 
 import (
+	"slices"
 	"testing"
 
 	"github.com/thedonutfactory/go-tfhe/bitutils"
+	"github.com/thedonutfactory/go-tfhe/csprng"
+	"github.com/thedonutfactory/go-tfhe/key"
+	"github.com/thedonutfactory/go-tfhe/proxyreenc"
 )
 
 func TestU8ToBitsAndBack(t *testing.T) {
@@ -75,3 +79,81 @@ func TestToBitsLSBFirst(t *testing.T) {
 		}
 	}
 }
+
+func TestSignedToBitsAndBack(t *testing.T) {
+	for _, val := range []int8{0, 1, -1, 42, -42, 127, -128} {
+		if result := bitutils.ConvertI8(bitutils.I8ToBits(val)); result != val {
+			t.Errorf("I8: %d -> bits -> %d", val, result)
+		}
+	}
+	for _, val := range []int16{0, -1, 402, -706, 32767, -32768} {
+		if result := bitutils.ConvertI16(bitutils.I16ToBits(val)); result != val {
+			t.Errorf("I16: %d -> bits -> %d", val, result)
+		}
+	}
+	for _, val := range []int32{0, -1, 1000000, -1000000, -2147483648} {
+		if result := bitutils.ConvertI32(bitutils.I32ToBits(val)); result != val {
+			t.Errorf("I32: %d -> bits -> %d", val, result)
+		}
+	}
+	for _, val := range []int64{0, -1, 42, -9223372036854775808, 9223372036854775807} {
+		if result := bitutils.ConvertI64(bitutils.I64ToBits(val)); result != val {
+			t.Errorf("I64: %d -> bits -> %d", val, result)
+		}
+	}
+}
+
+func TestSignExtend(t *testing.T) {
+	// -3 in 3 bits is 0b101
+	bits := []bool{true, false, true}
+	if got := bitutils.ConvertI8(bits); got != -3 {
+		t.Errorf("ConvertI8(101) = %d, expected -3", got)
+	}
+	if got := bitutils.ConvertU8(bitutils.SignExtend(bits, 8)); got != 0b11111101 {
+		t.Errorf("SignExtend(101, 8) = %08b, expected 11111101", got)
+	}
+	if got := bitutils.ConvertI8([]bool{true, false, false}); got != 1 {
+		t.Errorf("ConvertI8(001) = %d, expected 1", got)
+	}
+	if got := bitutils.SignExtend(bits, 2); len(got) != 2 || !got[0] || got[1] {
+		t.Errorf("SignExtend(101, 2) = %v, expected [true false]", got)
+	}
+}
+
+func TestEncryptBitsPublic(t *testing.T) {
+	sk := key.NewSecretKey()
+	keys := []struct {
+		name string
+		pk   proxyreenc.PublicKey
+	}{
+		{"PublicKeyLv0", proxyreenc.NewPublicKeyLv0ForKey(sk)},
+		{"CompactPublicKey", proxyreenc.NewCompactPublicKey(sk)},
+	}
+
+	for _, k := range keys {
+		bits := bitutils.EncryptBitsPublic(bitutils.U8ToBits(0xA5), k.pk)
+		if got := bitutils.ConvertU8(bitutils.DecryptBits(bits, sk.KeyLv0)); got != 0xA5 {
+			t.Errorf("%s: EncryptBitsPublic(0xA5) decrypted to %#x", k.name, got)
+		}
+	}
+}
+
+func TestEncryptBitsWithSource(t *testing.T) {
+	sk := key.NewSecretKey()
+	alpha := sk.Params.TLWELv0.ALPHA
+	seed := []byte("bitutils")
+
+	first := bitutils.EncryptBitsWithSource(bitutils.U8ToBits(0x5C), alpha, sk.KeyLv0, csprng.NewSeededSource(seed))
+	second := bitutils.EncryptBitsWithSource(bitutils.U8ToBits(0x5C), alpha, sk.KeyLv0, csprng.NewSeededSource(seed))
+	if got := bitutils.ConvertU8(bitutils.DecryptBits(first, sk.KeyLv0)); got != 0x5C {
+		t.Fatalf("EncryptBitsWithSource(0x5C) decrypted to %#x", got)
+	}
+	for i := range first {
+		if !slices.Equal(first[i].P, second[i].P) {
+			t.Errorf("bit %d: same seed gave different ciphertexts", i)
+		}
+		if i > 0 && first[i].P[0] == first[i-1].P[0] {
+			t.Errorf("bit %d: mask repeats the previous bit's", i)
+		}
+	}
+}
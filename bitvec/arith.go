@@ -0,0 +1,179 @@
+package bitvec
+
+import (
+	"math/bits"
+
+	"github.com/thedonutfactory/go-tfhe/cloudkey"
+	"github.com/thedonutfactory/go-tfhe/gates"
+)
+
+// Add returns a + b (mod 2^width) with a ripple-carry adder.
+//
+// The carries are a chain of MAJ gates (one bootstrap per bit, one after
+// the other) and the sums are computed afterwards with one batch of XOR3
+// gates: 2*width bootstraps and a depth of width+1.
+func (a BitVec) Add(b BitVec, ck *cloudkey.CloudKey) BitVec {
+	checkWidths(a, b)
+	sums, _ := rippleAdd([][2]BitVec{{a, b}}, gates.ConstantWithParams(false, ck.Params), false, ck)
+	return sums[0]
+}
+
+// AddKoggeStone returns a + b (mod 2^width) with a Kogge-Stone
+// carry-lookahead adder.
+//
+// It takes about 2*width*log2(width) bootstraps instead of 2*width, but its
+// depth is log2(width)+2 instead of width+1, so it is faster than Add when
+// there are enough cores to run each layer in parallel.
+func (a BitVec) AddKoggeStone(b BitVec, ck *cloudkey.CloudKey) BitVec {
+	in := pairs(a, b)
+	n := len(in)
+	if n == 0 {
+		return BitVec{}
+	}
+
+	// Generate and transmit of every bit: whether it carries out with a
+	// carry in of 0 and of 1. The transmit is a OR b rather than the usual
+	// propagate a XOR b, so generate implies transmit, for single bits and
+	// for every group merged from them.
+	g := gates.BatchAND(in, ck)
+	p := gates.BatchOR(in, ck)
+	g = koggeStoneCarries(g, p, func(in [][3]*gates.Ciphertext) []*gates.Ciphertext {
+		return gates.BatchMAJ(in, ck)
+	})
+
+	// The carry into bit i is the generate of bits i-1..0
+	sumIn := make([][3]*gates.Ciphertext, n)
+	sumIn[0] = [3]*gates.Ciphertext{a[0], b[0], gates.ConstantWithParams(false, ck.Params)}
+	for i := 1; i < n; i++ {
+		sumIn[i] = [3]*gates.Ciphertext{a[i], b[i], g[i-1]}
+	}
+	return gates.BatchXOR3(sumIn, ck)
+}
+
+// koggeStoneCarries merges the generate and transmit bits of single bits
+// into those of the groups of bits i..0, in log2(len(g)) rounds of one batch
+// of majority gates each, and returns the group generates: g[i] is the
+// carry out of bit i. It overwrites g and p.
+//
+// A group carries out if its high half does, or if the high half transmits
+// the carry out of the low half. Since generate implies transmit, both
+// merges are a single majority:
+//
+//	G = g_hi OR (p_hi AND g_lo) = MAJ(g_hi, p_hi, g_lo)
+//	P = g_hi OR (p_hi AND p_lo) = MAJ(g_hi, p_hi, p_lo)
+func koggeStoneCarries[T any](g, p []T, maj func([][3]T) []T) []T {
+	n := len(g)
+	// After the round with distance d, g[i] and p[i] cover the 2d bits
+	// ending at bit i (or all bits down to 0)
+	for d := 1; d < n; d *= 2 {
+		var in [][3]T
+		for i := d; i < n; i++ {
+			in = append(in, [3]T{g[i], p[i], g[i-d]})
+		}
+		// Only the transmits read by the next round are needed
+		for i := 2 * d; i < n; i++ {
+			in = append(in, [3]T{g[i], p[i], p[i-d]})
+		}
+
+		out := maj(in)
+		copy(g[d:], out[:n-d])
+		if 2*d < n {
+			copy(p[2*d:], out[n-d:])
+		}
+	}
+	return g
+}
+
+// Sub returns a - b (mod 2^width), as a + NOT(b) + 1 with a ripple-carry adder
+func (a BitVec) Sub(b BitVec, ck *cloudkey.CloudKey) BitVec {
+	checkWidths(a, b)
+	sums, _ := rippleAdd([][2]BitVec{{a, b.Not()}}, gates.ConstantWithParams(true, ck.Params), false, ck)
+	return sums[0]
+}
+
+// Neg returns -a (mod 2^width)
+func (a BitVec) Neg(ck *cloudkey.CloudKey) BitVec {
+	return Trivial(0, len(a), ck.Params).Sub(a, ck)
+}
+
+// Popcount returns the number of true bits of a, as a vector of
+// bits.Len(width) bits.
+//
+// The bits are summed by a tree of ripple-carry adders; the adders of each
+// level of the tree run side by side, sharing their batches.
+func (a BitVec) Popcount(ck *cloudkey.CloudKey) BitVec {
+	width := bits.Len(uint(len(a)))
+	counts := make([]BitVec, len(a))
+	for i, ct := range a {
+		counts[i] = BitVec{ct}
+	}
+	if len(counts) == 0 {
+		return BitVec{}
+	}
+
+	for len(counts) > 1 {
+		var in [][2]BitVec
+		for i := 0; i+1 < len(counts); i += 2 {
+			n := max(len(counts[i]), len(counts[i+1]))
+			in = append(in, [2]BitVec{counts[i].ZeroExtend(n), counts[i+1].ZeroExtend(n)})
+		}
+		sums, carries := rippleAdd(in, gates.ConstantWithParams(false, ck.Params), true, ck)
+
+		next := make([]BitVec, 0, (len(counts)+1)/2)
+		for i := range sums {
+			next = append(next, append(sums[i], carries[i]))
+		}
+		if len(counts)%2 == 1 {
+			next = append(next, counts[len(counts)-1])
+		}
+		counts = next
+	}
+	return counts[0].ZeroExtend(width)
+}
+
+// rippleAdd adds every pair of equal-width vectors with ripple-carry adders
+// run side by side: one BatchMAJ per bit position for the carries, then one
+// BatchXOR3 for all the sums. carryIn is the carry into bit 0 of every pair.
+// The carries out are only computed if carryOut is set.
+func rippleAdd(in [][2]BitVec, carryIn *gates.Ciphertext, carryOut bool, ck *cloudkey.CloudKey) (sums []BitVec, carries []*gates.Ciphertext) {
+	width := 0
+	carry := make([][]*gates.Ciphertext, len(in))
+	for k, pair := range in {
+		width = max(width, len(pair[0]))
+		carry[k] = make([]*gates.Ciphertext, len(pair[0])+1)
+		carry[k][0] = carryIn
+	}
+
+	// carry[k][i+1] = MAJ(a_i, b_i, carry[k][i])
+	for i := 0; i < width; i++ {
+		var majIn [][3]*gates.Ciphertext
+		var owners []int
+		for k, pair := range in {
+			n := len(pair[0])
+			if i < n-1 || (i == n-1 && carryOut) {
+				majIn = append(majIn, [3]*gates.Ciphertext{pair[0][i], pair[1][i], carry[k][i]})
+				owners = append(owners, k)
+			}
+		}
+		for j, ct := range gates.BatchMAJ(majIn, ck) {
+			carry[owners[j]][i+1] = ct
+		}
+	}
+
+	var xorIn [][3]*gates.Ciphertext
+	for k, pair := range in {
+		for i := range pair[0] {
+			xorIn = append(xorIn, [3]*gates.Ciphertext{pair[0][i], pair[1][i], carry[k][i]})
+		}
+	}
+	out := gates.BatchXOR3(xorIn, ck)
+
+	sums = make([]BitVec, len(in))
+	carries = make([]*gates.Ciphertext, len(in))
+	for k, pair := range in {
+		n := len(pair[0])
+		sums[k], out = out[:n:n], out[n:]
+		carries[k] = carry[k][n]
+	}
+	return sums, carries
+}
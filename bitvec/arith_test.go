@@ -0,0 +1,45 @@
+package bitvec
+
+import "testing"
+
+// TestKoggeStoneCarriesModel runs the prefix network of AddKoggeStone on
+// plaintext bits for every pair of values of up to 8 bits
+func TestKoggeStoneCarriesModel(t *testing.T) {
+	maj := func(in [][3]bool) []bool {
+		out := make([]bool, len(in))
+		for i, x := range in {
+			out[i] = x[0] && x[1] || x[0] && x[2] || x[1] && x[2]
+		}
+		return out
+	}
+
+	for width := 1; width <= 8; width++ {
+		testKoggeStoneCarries(t, width, maj)
+	}
+}
+
+func testKoggeStoneCarries(t *testing.T, width int, maj func([][3]bool) []bool) {
+	for a := uint64(0); a < 1<<width; a++ {
+		for b := uint64(0); b < 1<<width; b++ {
+			g := make([]bool, width)
+			p := make([]bool, width)
+			for i := range g {
+				ai, bi := a>>i&1 == 1, b>>i&1 == 1
+				g[i], p[i] = ai && bi, ai || bi
+			}
+			carries := koggeStoneCarries(g, p, maj)
+
+			sum := (a ^ b) & 1
+			for i := 1; i < width; i++ {
+				c := uint64(0)
+				if carries[i-1] {
+					c = 1
+				}
+				sum |= ((a>>i ^ b>>i ^ c) & 1) << i
+			}
+			if want := (a + b) & (1<<width - 1); sum != want {
+				t.Fatalf("width %d: %d + %d = %d, want %d", width, a, b, sum, want)
+			}
+		}
+	}
+}
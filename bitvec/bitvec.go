@@ -0,0 +1,183 @@
+// Package bitvec provides arithmetic on encrypted bit vectors built from
+// boolean gates.
+//
+// A BitVec is a slice of gate-encoded ciphertexts, least significant bit
+// first (the order of bitutils.ToBits). Every operation is a sequence of
+// layers of independent gates, and each layer is evaluated with one call to
+// the batch gate functions (gates.BatchAND, gates.BatchMAJ, ...), so the
+// gates of a layer bootstrap in parallel.
+//
+// Clear values can be mixed in with Trivial: gates short-circuit on trivial
+// ciphertexts, so a constant operand costs fewer bootstraps than an
+// encrypted one.
+//
+// # Example
+//
+//	sk := key.NewSecretKey()
+//	ck := cloudkey.NewCloudKey(sk)
+//
+//	a := bitvec.Encrypt(200, 8, sk)
+//	b := bitvec.Encrypt(100, 8, sk)
+//	sum := a.Add(b, ck)
+//	fmt.Println(sum.Decrypt(sk)) // 44 (mod 256)
+package bitvec
+
+import (
+	"fmt"
+
+	"github.com/thedonutfactory/go-tfhe/bitutils"
+	"github.com/thedonutfactory/go-tfhe/cloudkey"
+	"github.com/thedonutfactory/go-tfhe/csprng"
+	"github.com/thedonutfactory/go-tfhe/gates"
+	"github.com/thedonutfactory/go-tfhe/key"
+	"github.com/thedonutfactory/go-tfhe/params"
+	"github.com/thedonutfactory/go-tfhe/proxyreenc"
+	"github.com/thedonutfactory/go-tfhe/tlwe"
+)
+
+// BitVec is an encrypted bit vector, least significant bit first
+type BitVec []*gates.Ciphertext
+
+// Encrypt encrypts the width least significant bits of value under sk
+func Encrypt(value uint64, width int, sk *key.SecretKey) BitVec {
+	src := csprng.Fork()
+	result := make(BitVec, width)
+	for i, bit := range bitutils.ToBits(value, width) {
+		result[i] = tlwe.NewTLWELv0WithParams(sk.Params).EncryptBoolWithSource(bit, sk.Params.TLWELv0.ALPHA, sk.KeyLv0, src)
+	}
+	return result
+}
+
+// EncryptPublic encrypts the width least significant bits of value with a
+// public key
+func EncryptPublic(value uint64, width int, pk proxyreenc.PublicKey) BitVec {
+	return bitutils.EncryptBitsPublic(bitutils.ToBits(value, width), pk)
+}
+
+// Trivial returns the width least significant bits of value as trivial
+// ciphertexts (see gates.TrivialValue), which need no key
+func Trivial(value uint64, width int, p params.Parameters) BitVec {
+	result := make(BitVec, width)
+	for i, bit := range bitutils.ToBits(value, width) {
+		result[i] = gates.ConstantWithParams(bit, p)
+	}
+	return result
+}
+
+// Decrypt decrypts a with sk. Bits above the 64th are ignored.
+func (a BitVec) Decrypt(sk *key.SecretKey) uint64 {
+	bits := bitutils.DecryptBits(a, sk.KeyLv0)
+	return bitutils.ConvertU64(bits[:min(len(bits), 64)])
+}
+
+// Len returns the width of a in bits
+func (a BitVec) Len() int {
+	return len(a)
+}
+
+// Copy returns a deep copy of a
+func (a BitVec) Copy() BitVec {
+	result := make(BitVec, len(a))
+	for i, ct := range a {
+		result[i] = gates.Copy(ct)
+	}
+	return result
+}
+
+// ZeroExtend returns a widened to width bits with zeros above its most
+// significant bit, or its width least significant bits if a is wider. An
+// empty a is extended under the current parameter set; use
+// ZeroExtendWithParams for others.
+func (a BitVec) ZeroExtend(width int) BitVec {
+	return a.ZeroExtendWithParams(width, a.params())
+}
+
+// ZeroExtendWithParams is ZeroExtend with the zeros encoded under parameter
+// set p, which must be that of a
+func (a BitVec) ZeroExtendWithParams(width int, p params.Parameters) BitVec {
+	if width <= len(a) {
+		return a[:width].Copy()
+	}
+	return append(a.Copy(), Trivial(0, width-len(a), p)...)
+}
+
+// SignExtend returns a widened to width bits with copies of its most
+// significant bit, or its width least significant bits if a is wider. An
+// empty a is extended with zeros under the current parameter set; use
+// SignExtendWithParams for others.
+func (a BitVec) SignExtend(width int) BitVec {
+	return a.SignExtendWithParams(width, a.params())
+}
+
+// SignExtendWithParams is SignExtend with the zeros of an empty a encoded
+// under parameter set p, which must be that of a
+func (a BitVec) SignExtendWithParams(width int, p params.Parameters) BitVec {
+	if width <= len(a) || len(a) == 0 {
+		return a.ZeroExtendWithParams(width, p)
+	}
+	result := a.Copy()
+	for len(result) < width {
+		result = append(result, gates.Copy(a[len(a)-1]))
+	}
+	return result
+}
+
+// Not returns the bitwise complement of a (no bootstrapping)
+func (a BitVec) Not() BitVec {
+	result := make(BitVec, len(a))
+	for i, ct := range a {
+		result[i] = gates.NOT(ct)
+	}
+	return result
+}
+
+// And returns the bitwise AND of a and b
+func (a BitVec) And(b BitVec, ck *cloudkey.CloudKey) BitVec {
+	return gates.BatchAND(pairs(a, b), ck)
+}
+
+// Or returns the bitwise OR of a and b
+func (a BitVec) Or(b BitVec, ck *cloudkey.CloudKey) BitVec {
+	return gates.BatchOR(pairs(a, b), ck)
+}
+
+// Xor returns the bitwise XOR of a and b
+func (a BitVec) Xor(b BitVec, ck *cloudkey.CloudKey) BitVec {
+	return gates.BatchXOR(pairs(a, b), ck)
+}
+
+// Select returns a where cond is true and b where it is false, with one
+// batch of MUX gates
+func Select(cond *gates.Ciphertext, a, b BitVec, ck *cloudkey.CloudKey) BitVec {
+	checkWidths(a, b)
+	inputs := make([][3]*gates.Ciphertext, len(a))
+	for i := range a {
+		inputs[i] = [3]*gates.Ciphertext{cond, a[i], b[i]}
+	}
+	return gates.BatchMUX(inputs, ck)
+}
+
+// params returns the parameter set of a (the current one if a is empty)
+func (a BitVec) params() params.Parameters {
+	if len(a) == 0 {
+		return params.Current()
+	}
+	return a[0].Params
+}
+
+// pairs zips the bits of a and b for the two-input batch gates
+func pairs(a, b BitVec) [][2]*gates.Ciphertext {
+	checkWidths(a, b)
+	result := make([][2]*gates.Ciphertext, len(a))
+	for i := range a {
+		result[i] = [2]*gates.Ciphertext{a[i], b[i]}
+	}
+	return result
+}
+
+// checkWidths panics unless a and b have the same width
+func checkWidths(a, b BitVec) {
+	if len(a) != len(b) {
+		panic(fmt.Sprintf("bitvec: operands have widths %d and %d", len(a), len(b)))
+	}
+}
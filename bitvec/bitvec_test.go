@@ -0,0 +1,245 @@
+package bitvec_test
+
+import (
+	"math/bits"
+	"testing"
+
+	"github.com/thedonutfactory/go-tfhe/bitvec"
+	"github.com/thedonutfactory/go-tfhe/cloudkey"
+	"github.com/thedonutfactory/go-tfhe/key"
+	"github.com/thedonutfactory/go-tfhe/params"
+	"github.com/thedonutfactory/go-tfhe/proxyreenc"
+)
+
+func TestBitwise(t *testing.T) {
+	sk := key.NewSecretKey()
+	ck := cloudkey.NewCloudKey(sk)
+
+	a := bitvec.Encrypt(0b1010, 4, sk)
+	b := bitvec.Encrypt(0b0110, 4, sk)
+	testCases := []struct {
+		name string
+		got  bitvec.BitVec
+		want uint64
+	}{
+		{"And", a.And(b, ck), 0b0010},
+		{"Or", a.Or(b, ck), 0b1110},
+		{"Xor", a.Xor(b, ck), 0b1100},
+		{"Not", a.Not(), 0b0101},
+		{"Select true", bitvec.Select(bitvec.Encrypt(1, 1, sk)[0], a, b, ck), 0b1010},
+		{"Select false", bitvec.Select(bitvec.Encrypt(0, 1, sk)[0], a, b, ck), 0b0110},
+		{"ZeroExtend", a.ZeroExtend(6), 0b001010},
+		{"SignExtend", a.SignExtend(6), 0b111010},
+		{"truncate", a.SignExtend(3), 0b010},
+		{"Trivial", bitvec.Trivial(0b1001, 4, ck.Params), 0b1001},
+		{"EncryptPublic", bitvec.EncryptPublic(0b1100, 4, proxyreenc.NewCompactPublicKey(sk)).Xor(a, ck), 0b0110},
+	}
+	for _, tc := range testCases {
+		if got := tc.got.Decrypt(sk); got != tc.want {
+			t.Errorf("%s = %04b, want %04b", tc.name, got, tc.want)
+		}
+	}
+}
+
+func TestArithmetic(t *testing.T) {
+	sk := key.NewSecretKey()
+	ck := cloudkey.NewCloudKey(sk)
+
+	const width = 5
+	const mask = 1<<width - 1
+	for _, tc := range []struct{ a, b uint64 }{{13, 22}, {31, 1}} {
+		a := bitvec.Encrypt(tc.a, width, sk)
+		b := bitvec.Encrypt(tc.b, width, sk)
+
+		if got := a.Add(b, ck).Decrypt(sk); got != (tc.a+tc.b)&mask {
+			t.Errorf("%d + %d = %d", tc.a, tc.b, got)
+		}
+		if got := a.AddKoggeStone(b, ck).Decrypt(sk); got != (tc.a+tc.b)&mask {
+			t.Errorf("%d + %d = %d with Kogge-Stone", tc.a, tc.b, got)
+		}
+		if got := a.Sub(b, ck).Decrypt(sk); got != (tc.a-tc.b)&mask {
+			t.Errorf("%d - %d = %d", tc.a, tc.b, got)
+		}
+	}
+
+	// Groups that generate without transmitting a carry in, at 8 bits
+	for _, tc := range []struct{ a, b uint64 }{{4, 4}, {200, 100}} {
+		a := bitvec.Encrypt(tc.a, 8, sk)
+		b := bitvec.Encrypt(tc.b, 8, sk)
+		if got := a.AddKoggeStone(b, ck).Decrypt(sk); got != (tc.a+tc.b)&0xff {
+			t.Errorf("%d + %d = %d with Kogge-Stone", tc.a, tc.b, got)
+		}
+	}
+
+	a := bitvec.Encrypt(9, width, sk)
+	if got := a.Neg(ck).Decrypt(sk); got != -9&mask {
+		t.Errorf("-9 = %d", got)
+	}
+	if got := a.Add(bitvec.Trivial(7, width, ck.Params), ck).Decrypt(sk); got != 16 {
+		t.Errorf("9 + trivial 7 = %d", got)
+	}
+}
+
+func TestCompare(t *testing.T) {
+	sk := key.NewSecretKey()
+	ck := cloudkey.NewCloudKey(sk)
+
+	for _, tc := range []struct{ a, b uint64 }{{3, 3}, {5, 9}, {12, 7}} {
+		a := bitvec.Encrypt(tc.a, 4, sk)
+		b := bitvec.Encrypt(tc.b, 4, sk)
+		results := []struct {
+			name string
+			got  bool
+			want bool
+		}{
+			{"Eq", a.Eq(b, ck).DecryptBool(sk.KeyLv0), tc.a == tc.b},
+			{"Ne", a.Ne(b, ck).DecryptBool(sk.KeyLv0), tc.a != tc.b},
+			{"Lt", a.Lt(b, ck).DecryptBool(sk.KeyLv0), tc.a < tc.b},
+			{"Le", a.Le(b, ck).DecryptBool(sk.KeyLv0), tc.a <= tc.b},
+			{"Gt", a.Gt(b, ck).DecryptBool(sk.KeyLv0), tc.a > tc.b},
+			{"Ge", a.Ge(b, ck).DecryptBool(sk.KeyLv0), tc.a >= tc.b},
+		}
+		for _, r := range results {
+			if r.got != r.want {
+				t.Errorf("%s(%d, %d) = %v, want %v", r.name, tc.a, tc.b, r.got, r.want)
+			}
+		}
+	}
+}
+
+func TestShifts(t *testing.T) {
+	sk := key.NewSecretKey()
+	ck := cloudkey.NewCloudKey(sk)
+
+	const width = 5
+	const mask = 1<<width - 1
+	const x uint64 = 0b10110 // -10 as a signed integer
+	a := bitvec.Encrypt(x, width, sk)
+	sar := func(k int) uint64 { return uint64((int64(x)-1<<width)>>k) & mask }
+	rotl := func(k int) uint64 {
+		k %= width
+		return (x<<k | x>>(width-k)) & mask
+	}
+
+	// Clear amounts only move ciphertexts
+	for k := 0; k <= width+1; k++ {
+		if got, want := a.Shl(k).Decrypt(sk), x<<k&mask; got != want {
+			t.Errorf("Shl(%d) = %05b, want %05b", k, got, want)
+		}
+		if got, want := a.Shr(k).Decrypt(sk), x>>k; got != want {
+			t.Errorf("Shr(%d) = %05b, want %05b", k, got, want)
+		}
+		if got, want := a.Sar(k).Decrypt(sk), sar(k); got != want {
+			t.Errorf("Sar(%d) = %05b, want %05b", k, got, want)
+		}
+		if got, want := a.RotL(k).Decrypt(sk), rotl(k); got != want {
+			t.Errorf("RotL(%d) = %05b, want %05b", k, got, want)
+		}
+		if got, want := a.RotR(k).Decrypt(sk), rotl(width-k%width); got != want {
+			t.Errorf("RotR(%d) = %05b, want %05b", k, got, want)
+		}
+	}
+
+	// Encrypted amounts go through a barrel shifter
+	for _, k := range []int{3, 6} {
+		amount := bitvec.Encrypt(uint64(k), 3, sk)
+		results := []struct {
+			name      string
+			got, want uint64
+		}{
+			{"ShlBy", a.ShlBy(amount, ck).Decrypt(sk), x << k & mask},
+			{"ShrBy", a.ShrBy(amount, ck).Decrypt(sk), x >> k},
+			{"SarBy", a.SarBy(amount, ck).Decrypt(sk), sar(k)},
+			{"RotLBy", a.RotLBy(amount, ck).Decrypt(sk), rotl(k)},
+			{"RotRBy", a.RotRBy(amount, ck).Decrypt(sk), rotl(width - k%width)},
+		}
+		for _, r := range results {
+			if r.got != r.want {
+				t.Errorf("%s(%d) = %05b, want %05b", r.name, k, r.got, r.want)
+			}
+		}
+	}
+}
+
+// TestShiftsWithParams shifts by the whole width under a parameter set that
+// is not the current one, which leaves nothing of a to take it from
+func TestShiftsWithParams(t *testing.T) {
+	p := params.GetParameters(params.Security80Bit)
+	sk := key.NewSecretKeyWithParams(p)
+	ck := cloudkey.NewCloudKey(sk)
+
+	const width = 8
+	a := bitvec.Encrypt(0xAB, width, sk)
+	results := []struct {
+		name string
+		got  bitvec.BitVec
+	}{
+		{"Shl(8)", a.Shl(width)},
+		{"Shr(8)", a.Shr(width)},
+		{"ShrBy(8)", a.ShrBy(bitvec.Encrypt(width, 4, sk), ck)},
+		{"empty ZeroExtendWithParams", bitvec.BitVec{}.ZeroExtendWithParams(width, p)},
+		{"empty SignExtendWithParams", bitvec.BitVec{}.SignExtendWithParams(width, p)},
+	}
+	for _, r := range results {
+		for i, ct := range r.got {
+			if ct.Params != p || len(ct.P) != p.TLWELv0.N+1 {
+				t.Fatalf("%s: bit %d has %d coefficients, want %d", r.name, i, len(ct.P), p.TLWELv0.N+1)
+			}
+		}
+		if got := r.got.Decrypt(sk); got != 0 {
+			t.Errorf("%s = %08b, want 0", r.name, got)
+		}
+	}
+}
+
+func TestPopcount(t *testing.T) {
+	sk := key.NewSecretKey()
+	ck := cloudkey.NewCloudKey(sk)
+
+	for _, x := range []uint64{0b1011011, 0b1111111} {
+		count := bitvec.Encrypt(x, 7, sk).Popcount(ck)
+		if count.Len() != 3 {
+			t.Errorf("Popcount of 7 bits has %d bits, want 3", count.Len())
+		}
+		if got := count.Decrypt(sk); got != uint64(bits.OnesCount64(x)) {
+			t.Errorf("Popcount(%07b) = %d", x, got)
+		}
+	}
+}
+
+func TestSigned(t *testing.T) {
+	sk := key.NewSecretKey()
+	ck := cloudkey.NewCloudKey(sk)
+
+	const width = 4
+	for _, tc := range []struct{ a, b int64 }{{-3, 2}, {-4, -4}} {
+		a := bitvec.Encrypt(uint64(tc.a), width, sk)
+		b := bitvec.Encrypt(uint64(tc.b), width, sk)
+		results := []struct {
+			name string
+			got  bool
+			want bool
+		}{
+			{"LtSigned", a.LtSigned(b, ck).DecryptBool(sk.KeyLv0), tc.a < tc.b},
+			{"LeSigned", a.LeSigned(b, ck).DecryptBool(sk.KeyLv0), tc.a <= tc.b},
+			{"GtSigned", a.GtSigned(b, ck).DecryptBool(sk.KeyLv0), tc.a > tc.b},
+			{"GeSigned", a.GeSigned(b, ck).DecryptBool(sk.KeyLv0), tc.a >= tc.b},
+			{"IsNegative", a.IsNegative().DecryptBool(sk.KeyLv0), tc.a < 0},
+		}
+		for _, r := range results {
+			if r.got != r.want {
+				t.Errorf("%s(%d, %d) = %v, want %v", r.name, tc.a, tc.b, r.got, r.want)
+			}
+		}
+	}
+
+	for _, tc := range []struct{ x, abs int64 }{{-5, 5}, {-8, -8}} {
+		a := bitvec.Encrypt(uint64(tc.x), width, sk)
+		if got := a.Abs(ck).DecryptSigned(sk); got != tc.abs {
+			t.Errorf("Abs(%d) = %d, want %d", tc.x, got, tc.abs)
+		}
+	}
+	if got := bitvec.Encrypt(uint64(3), width, sk).Neg(ck).SignExtend(8).DecryptSigned(sk); got != -3 {
+		t.Errorf("SignExtend(-3) = %d, want -3", got)
+	}
+}
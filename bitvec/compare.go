@@ -0,0 +1,97 @@
+package bitvec
+
+import (
+	"github.com/thedonutfactory/go-tfhe/cloudkey"
+	"github.com/thedonutfactory/go-tfhe/gates"
+)
+
+// Eq returns whether a == b: one batch of XNOR gates and a tree of AND
+// gates, log2(width)+1 layers in all
+func (a BitVec) Eq(b BitVec, ck *cloudkey.CloudKey) *gates.Ciphertext {
+	equal := gates.BatchXNOR(pairs(a, b), ck)
+	if len(equal) == 0 {
+		return gates.ConstantWithParams(true, ck.Params)
+	}
+	for len(equal) > 1 {
+		in := make([][2]*gates.Ciphertext, len(equal)/2)
+		for i := range in {
+			in[i] = [2]*gates.Ciphertext{equal[2*i], equal[2*i+1]}
+		}
+		next := gates.BatchAND(in, ck)
+		if len(equal)%2 == 1 {
+			next = append(next, equal[len(equal)-1])
+		}
+		equal = next
+	}
+	return equal[0]
+}
+
+// Ne returns whether a != b
+func (a BitVec) Ne(b BitVec, ck *cloudkey.CloudKey) *gates.Ciphertext {
+	return gates.NOT(a.Eq(b, ck))
+}
+
+// Lt returns whether a < b as unsigned integers
+func (a BitVec) Lt(b BitVec, ck *cloudkey.CloudKey) *gates.Ciphertext {
+	lt, _ := compare(a, b, ck)
+	return lt
+}
+
+// Le returns whether a <= b as unsigned integers
+func (a BitVec) Le(b BitVec, ck *cloudkey.CloudKey) *gates.Ciphertext {
+	_, le := compare(a, b, ck)
+	return le
+}
+
+// Gt returns whether a > b as unsigned integers
+func (a BitVec) Gt(b BitVec, ck *cloudkey.CloudKey) *gates.Ciphertext {
+	return b.Lt(a, ck)
+}
+
+// Ge returns whether a >= b as unsigned integers
+func (a BitVec) Ge(b BitVec, ck *cloudkey.CloudKey) *gates.Ciphertext {
+	return b.Le(a, ck)
+}
+
+// compare returns a < b and a <= b with a comparison tree.
+//
+// Every bit starts as lt = NOT(a) AND b and le = NOT(a) OR b. Two adjacent
+// groups merge as
+//
+//	lt = lt_hi OR (le_hi AND lt_lo) = MAJ(lt_hi, le_hi, lt_lo)
+//	le = lt_hi OR (le_hi AND le_lo) = MAJ(lt_hi, le_hi, le_lo)
+//
+// where the MAJ form holds because lt_hi implies le_hi. Each level of the
+// tree is one batch of MAJ gates, log2(width)+1 layers in all.
+func compare(a, b BitVec, ck *cloudkey.CloudKey) (lt, le *gates.Ciphertext) {
+	in := pairs(a, b)
+	if len(in) == 0 {
+		return gates.ConstantWithParams(false, ck.Params), gates.ConstantWithParams(true, ck.Params)
+	}
+	lts := gates.BatchANDNY(in, ck)
+	les := gates.BatchORNY(in, ck)
+
+	for len(lts) > 1 {
+		n := len(lts) / 2
+		merge := make([][3]*gates.Ciphertext, 0, 2*n)
+		for i := 0; i < n; i++ {
+			lo, hi := 2*i, 2*i+1
+			merge = append(merge,
+				[3]*gates.Ciphertext{lts[hi], les[hi], lts[lo]},
+				[3]*gates.Ciphertext{lts[hi], les[hi], les[lo]})
+		}
+		merged := gates.BatchMAJ(merge, ck)
+
+		nextLt := make([]*gates.Ciphertext, n, n+1)
+		nextLe := make([]*gates.Ciphertext, n, n+1)
+		for i := 0; i < n; i++ {
+			nextLt[i], nextLe[i] = merged[2*i], merged[2*i+1]
+		}
+		if len(lts)%2 == 1 {
+			nextLt = append(nextLt, lts[len(lts)-1])
+			nextLe = append(nextLe, les[len(les)-1])
+		}
+		lts, les = nextLt, nextLe
+	}
+	return lts[0], les[0]
+}
@@ -0,0 +1,134 @@
+package bitvec
+
+import (
+	"fmt"
+
+	"github.com/thedonutfactory/go-tfhe/cloudkey"
+)
+
+// Shifts and rotations by a clear amount only move ciphertexts around, so
+// they need no cloud key. Shifts by an encrypted amount are barrel
+// shifters: one batch of MUX gates per bit of the amount.
+
+// Shl returns a shifted left (towards the most significant bit) by k bits,
+// filling with zeros
+func (a BitVec) Shl(k int) BitVec {
+	checkShift(k)
+	zero := Trivial(0, min(k, len(a)), a.params())
+	return append(zero, a[:len(a)-len(zero)].Copy()...)
+}
+
+// Shr returns a shifted right (towards the least significant bit) by k
+// bits, filling with zeros
+func (a BitVec) Shr(k int) BitVec {
+	checkShift(k)
+	k = min(k, len(a))
+	// a[k:] is empty when shifting by the whole width
+	return a[k:].ZeroExtendWithParams(len(a), a.params())
+}
+
+// Sar returns a shifted right by k bits, filling with copies of the most
+// significant bit (an arithmetic shift of a signed integer)
+func (a BitVec) Sar(k int) BitVec {
+	checkShift(k)
+	if len(a) == 0 {
+		return BitVec{}
+	}
+	k = min(k, len(a)-1)
+	return a[k:].SignExtendWithParams(len(a), a.params())
+}
+
+// RotL returns a rotated left by k bits (k may be negative)
+func (a BitVec) RotL(k int) BitVec {
+	if len(a) == 0 {
+		return BitVec{}
+	}
+	k = ((k % len(a)) + len(a)) % len(a)
+	return append(a[len(a)-k:].Copy(), a[:len(a)-k].Copy()...)
+}
+
+// RotR returns a rotated right by k bits (k may be negative)
+func (a BitVec) RotR(k int) BitVec {
+	return a.RotL(-(k % max(len(a), 1)))
+}
+
+// ShlBy returns a shifted left by the encrypted unsigned amount k, filling
+// with zeros. Amounts of len(a) or more give zero.
+func (a BitVec) ShlBy(k BitVec, ck *cloudkey.CloudKey) BitVec {
+	return a.barrel(k, ck, func(v BitVec, j int) BitVec { return v.Shl(pow2Cap(j, len(a))) })
+}
+
+// ShrBy returns a shifted right by the encrypted unsigned amount k, filling
+// with zeros. Amounts of len(a) or more give zero.
+func (a BitVec) ShrBy(k BitVec, ck *cloudkey.CloudKey) BitVec {
+	return a.barrel(k, ck, func(v BitVec, j int) BitVec { return v.Shr(pow2Cap(j, len(a))) })
+}
+
+// SarBy returns a shifted right by the encrypted unsigned amount k, filling
+// with copies of the most significant bit
+func (a BitVec) SarBy(k BitVec, ck *cloudkey.CloudKey) BitVec {
+	return a.barrel(k, ck, func(v BitVec, j int) BitVec { return v.Sar(pow2Cap(j, len(a))) })
+}
+
+// RotLBy returns a rotated left by the encrypted unsigned amount k
+func (a BitVec) RotLBy(k BitVec, ck *cloudkey.CloudKey) BitVec {
+	return a.barrel(k, ck, func(v BitVec, j int) BitVec {
+		if r := pow2Mod(j, len(a)); r != 0 {
+			return v.RotL(r)
+		}
+		return nil
+	})
+}
+
+// RotRBy returns a rotated right by the encrypted unsigned amount k
+func (a BitVec) RotRBy(k BitVec, ck *cloudkey.CloudKey) BitVec {
+	return a.barrel(k, ck, func(v BitVec, j int) BitVec {
+		if r := pow2Mod(j, len(a)); r != 0 {
+			return v.RotR(r)
+		}
+		return nil
+	})
+}
+
+// barrel selects, for every bit j of k, between the current value and
+// stage(value, j), the value moved by 2^j bits. A nil stage leaves the
+// value unchanged (a rotation by a multiple of the width).
+//
+// Fill bits are trivial, so their MUX gates cost one bootstrap instead of
+// two.
+func (a BitVec) barrel(k BitVec, ck *cloudkey.CloudKey, stage func(v BitVec, j int) BitVec) BitVec {
+	if len(a) == 0 {
+		return BitVec{}
+	}
+	result := a.Copy()
+	for j, bit := range k {
+		if moved := stage(result, j); moved != nil {
+			result = Select(bit, moved, result, ck)
+		}
+	}
+	return result
+}
+
+// pow2Cap returns min(2^j, n)
+func pow2Cap(j, n int) int {
+	if j >= 62 || 1<<j > n {
+		return n
+	}
+	return 1 << j
+}
+
+// pow2Mod returns 2^j mod n
+func pow2Mod(j, n int) int {
+	r := 1 % n
+	for ; j > 0; j-- {
+		r = r * 2 % n
+	}
+	return r
+}
+
+// checkShift panics if k is negative
+func checkShift(k int) {
+	if k < 0 {
+		panic(fmt.Sprintf("bitvec: negative shift %d", k))
+	}
+}
@@ -0,0 +1,82 @@
+package bitvec
+
+import (
+	"github.com/thedonutfactory/go-tfhe/bitutils"
+	"github.com/thedonutfactory/go-tfhe/cloudkey"
+	"github.com/thedonutfactory/go-tfhe/gates"
+	"github.com/thedonutfactory/go-tfhe/key"
+)
+
+// A BitVec can also hold a two's complement signed integer. Add, Sub, Neg,
+// the bitwise operations, Shl, Eq and Ne are the same for both
+// interpretations; SignExtend, Sar, SarBy and the ...Signed functions below
+// treat the most significant bit as the sign.
+
+// DecryptSigned decrypts a with sk as a two's complement integer. Vectors
+// wider than 64 bits are truncated.
+func (a BitVec) DecryptSigned(sk *key.SecretKey) int64 {
+	bits := bitutils.DecryptBits(a, sk.KeyLv0)
+	return bitutils.ConvertI64(bits[:min(len(bits), 64)])
+}
+
+// IsNegative returns the sign bit of a (no bootstrapping)
+func (a BitVec) IsNegative() *gates.Ciphertext {
+	if len(a) == 0 {
+		return gates.ConstantWithParams(false, a.params())
+	}
+	return gates.Copy(a[len(a)-1])
+}
+
+// LtSigned returns whether a < b as signed integers
+func (a BitVec) LtSigned(b BitVec, ck *cloudkey.CloudKey) *gates.Ciphertext {
+	return flipSign(a).Lt(flipSign(b), ck)
+}
+
+// LeSigned returns whether a <= b as signed integers
+func (a BitVec) LeSigned(b BitVec, ck *cloudkey.CloudKey) *gates.Ciphertext {
+	return flipSign(a).Le(flipSign(b), ck)
+}
+
+// GtSigned returns whether a > b as signed integers
+func (a BitVec) GtSigned(b BitVec, ck *cloudkey.CloudKey) *gates.Ciphertext {
+	return b.LtSigned(a, ck)
+}
+
+// GeSigned returns whether a >= b as signed integers
+func (a BitVec) GeSigned(b BitVec, ck *cloudkey.CloudKey) *gates.Ciphertext {
+	return b.LeSigned(a, ck)
+}
+
+// Abs returns the absolute value of a as a signed integer. Like Go's
+// integers, the most negative value is its own absolute value.
+//
+// It computes (a XOR s) + s for the sign s: one batch of XOR gates and a
+// ripple-carry adder with one input fixed to zero, where every MAJ and XOR3
+// short-circuits to a two-input gate (about 3*width bootstraps).
+func (a BitVec) Abs(ck *cloudkey.CloudKey) BitVec {
+	n := len(a)
+	if n == 0 {
+		return BitVec{}
+	}
+	sign := a[n-1]
+
+	// The top bit of a XOR s is always zero
+	in := make([][2]*gates.Ciphertext, n-1)
+	for i := range in {
+		in[i] = [2]*gates.Ciphertext{a[i], sign}
+	}
+	flipped := append(BitVec(gates.BatchXOR(in, ck)), gates.ConstantWithParams(false, ck.Params))
+
+	sums, _ := rippleAdd([][2]BitVec{{flipped, Trivial(0, n, ck.Params)}}, sign, false, ck)
+	return sums[0]
+}
+
+// flipSign returns a with its most significant bit inverted, which maps
+// signed order onto unsigned order (no bootstrapping)
+func flipSign(a BitVec) BitVec {
+	result := a.Copy()
+	if n := len(result); n > 0 {
+		result[n-1] = gates.NOT(result[n-1])
+	}
+	return result
+}
@@ -0,0 +1,399 @@
+package circuit
+
+import (
+	"bufio"
+	"io"
+	"strings"
+)
+
+// blifDef is the driver of one BLIF signal
+type blifDef struct {
+	line   int
+	inputs []string
+
+	// .names: cover rows over the inputs, and whether they give the on-set
+	cubes []string
+	onSet bool
+
+	// .subckt / .gate: a Yosys internal gate cell, inputs in cellSpec order
+	cell *cellSpec
+}
+
+// blifParser collects the definitions of a BLIF model
+type blifParser struct {
+	inputs  []string
+	outputs []string
+	defs    map[string]*blifDef
+}
+
+// ParseBLIF reads the first model of a combinational BLIF file.
+//
+// Supported constructs are .model, .inputs, .outputs, .names with a
+// single-output cover, .conn, .subckt / .gate instances of the Yosys internal
+// gate cells ($_AND_, $_XOR_, $_MUX_, ...) and .end; .cname, .attr and .param
+// are ignored. Signals may be used before they are defined.
+//
+// A cover that depends on at most two inputs, or that is a multiplexer of
+// three, becomes a single gate. Larger covers are built as a sum of products.
+//
+// Every input and output is a port of width 1 named after its signal.
+func ParseBLIF(r io.Reader) (*Netlist, error) {
+	p := &blifParser{defs: make(map[string]*blifDef)}
+	if err := p.parse(r); err != nil {
+		return nil, err
+	}
+
+	n := &Netlist{Circuit: New()}
+	b := &blifBuilder{p: p, c: n.Circuit, wires: make(map[string]Wire), visiting: make(map[string]bool)}
+	for _, name := range p.inputs {
+		if _, ok := b.wires[name]; ok {
+			return nil, netlistError("blif", 0, "input %q is listed twice", name)
+		}
+		if def, ok := p.defs[name]; ok {
+			return nil, netlistError("blif", def.line, "input %q is also driven by the circuit", name)
+		}
+		b.wires[name] = b.c.Input()
+		n.Inputs = append(n.Inputs, Port{Name: name, Width: 1})
+	}
+	for _, name := range p.outputs {
+		w, err := b.signal(name, 0)
+		if err != nil {
+			return nil, err
+		}
+		n.Circuit.Output(w)
+		n.Outputs = append(n.Outputs, Port{Name: name, Width: 1})
+	}
+	return n, nil
+}
+
+// parse reads the definitions of the first model
+func (p *blifParser) parse(r io.Reader) error {
+	const format = "blif"
+	sc := bufio.NewScanner(r)
+	sc.Buffer(nil, 1<<20)
+
+	var (
+		lineNo  int
+		current *blifDef // .names whose cover is being read
+		models  int
+	)
+	define := func(name string, def *blifDef) error {
+		if _, ok := p.defs[name]; ok {
+			return netlistError(format, lineNo, "signal %q is driven twice", name)
+		}
+		p.defs[name] = def
+		return nil
+	}
+
+	for sc.Scan() {
+		lineNo++
+		line := sc.Text()
+		start := lineNo
+		// Join continued lines
+		for strings.HasSuffix(strings.TrimSpace(stripComment(line)), "\\") && sc.Scan() {
+			lineNo++
+			trimmed := strings.TrimSpace(stripComment(line))
+			line = trimmed[:len(trimmed)-1] + " " + sc.Text()
+		}
+		fields := strings.Fields(stripComment(line))
+		if len(fields) == 0 {
+			continue
+		}
+
+		if !strings.HasPrefix(fields[0], ".") {
+			if current == nil {
+				return netlistError(format, start, "cover row outside of .names")
+			}
+			if err := current.addRow(fields, format, start); err != nil {
+				return err
+			}
+			continue
+		}
+		current = nil
+
+		switch fields[0] {
+		case ".model":
+			models++
+			if models > 1 {
+				return netlistError(format, start, "hierarchical models are not supported")
+			}
+		case ".inputs":
+			p.inputs = append(p.inputs, fields[1:]...)
+		case ".outputs":
+			p.outputs = append(p.outputs, fields[1:]...)
+		case ".names":
+			if len(fields) < 2 {
+				return netlistError(format, start, ".names without an output")
+			}
+			current = &blifDef{line: start, inputs: fields[1 : len(fields)-1], onSet: true}
+			if err := define(fields[len(fields)-1], current); err != nil {
+				return err
+			}
+		case ".conn":
+			if len(fields) != 3 {
+				return netlistError(format, start, ".conn takes two signals")
+			}
+			if err := define(fields[2], &blifDef{line: start, inputs: fields[1:2], cubes: []string{"1"}, onSet: true}); err != nil {
+				return err
+			}
+		case ".subckt", ".gate":
+			if len(fields) < 2 {
+				return netlistError(format, start, "%s without a cell type", fields[0])
+			}
+			spec, ok := yosysCells[fields[1]]
+			if !ok {
+				return netlistError(format, start, "unsupported cell %q", fields[1])
+			}
+			conns := make(map[string]string)
+			for _, f := range fields[2:] {
+				formal, actual, ok := strings.Cut(f, "=")
+				if !ok {
+					return netlistError(format, start, "expected formal=actual, got %q", f)
+				}
+				conns[formal] = actual
+			}
+			def := &blifDef{line: start, cell: &spec}
+			for _, port := range spec.inputs {
+				actual, ok := conns[port]
+				if !ok {
+					return netlistError(format, start, "%s is missing port %s", fields[1], port)
+				}
+				def.inputs = append(def.inputs, actual)
+			}
+			out, ok := conns["Y"]
+			if !ok {
+				return netlistError(format, start, "%s is missing port Y", fields[1])
+			}
+			if err := define(out, def); err != nil {
+				return err
+			}
+		case ".cname", ".attr", ".param":
+		case ".end":
+			return sc.Err()
+		case ".latch":
+			return netlistError(format, start, "sequential circuits (.latch) are not supported")
+		default:
+			return netlistError(format, start, "unsupported directive %s", fields[0])
+		}
+	}
+	return sc.Err()
+}
+
+// stripComment removes a # comment from a BLIF line
+func stripComment(line string) string {
+	if i := strings.IndexByte(line, '#'); i >= 0 {
+		return line[:i]
+	}
+	return line
+}
+
+// addRow adds a cover row ("<input plane> <output>", or "<output>" for a
+// constant) to a .names definition
+func (d *blifDef) addRow(fields []string, format string, line int) error {
+	var plane, out string
+	switch {
+	case len(d.inputs) == 0 && len(fields) == 1:
+		out = fields[0]
+	case len(fields) == 2:
+		plane, out = fields[0], fields[1]
+	default:
+		return netlistError(format, line, "malformed cover row")
+	}
+	if len(plane) != len(d.inputs) || strings.Trim(plane, "01-") != "" {
+		return netlistError(format, line, "cover row %q does not match %d inputs", plane, len(d.inputs))
+	}
+	if out != "0" && out != "1" {
+		return netlistError(format, line, "cover output must be 0 or 1")
+	}
+	if len(d.cubes) > 0 && d.onSet != (out == "1") {
+		return netlistError(format, line, "cover mixes on-set and off-set rows")
+	}
+	d.onSet = out == "1"
+	d.cubes = append(d.cubes, plane)
+	return nil
+}
+
+// blifBuilder turns BLIF definitions into circuit nodes in dependency order
+type blifBuilder struct {
+	p        *blifParser
+	c        *Circuit
+	wires    map[string]Wire
+	visiting map[string]bool
+}
+
+// signal returns the wire of a named signal, building its driver first
+func (b *blifBuilder) signal(name string, line int) (Wire, error) {
+	if w, ok := b.wires[name]; ok {
+		return w, nil
+	}
+	def, ok := b.p.defs[name]
+	if !ok {
+		return 0, netlistError("blif", line, "signal %q is never driven", name)
+	}
+	if b.visiting[name] {
+		return 0, netlistError("blif", def.line, "combinational loop through %q", name)
+	}
+	b.visiting[name] = true
+
+	in := make([]Wire, len(def.inputs))
+	for i, input := range def.inputs {
+		w, err := b.signal(input, def.line)
+		if err != nil {
+			return 0, err
+		}
+		in[i] = w
+	}
+	var w Wire
+	if def.cell != nil {
+		w = def.cell.build(b.c, in)
+	} else {
+		w = b.cover(in, def.cubes, def.onSet)
+	}
+	b.wires[name] = w
+	delete(b.visiting, name)
+	return w, nil
+}
+
+// cover builds the function of a .names cover
+func (b *blifBuilder) cover(in []Wire, cubes []string, onSet bool) Wire {
+	if len(in) <= 3 {
+		if w, ok := b.singleGate(in, cubes, onSet); ok {
+			return w
+		}
+	}
+
+	// Sum of products
+	var terms []Wire
+	for _, cube := range cubes {
+		var literals []Wire
+		for i, ch := range cube {
+			switch ch {
+			case '1':
+				literals = append(literals, in[i])
+			case '0':
+				literals = append(literals, b.c.NOT(in[i]))
+			}
+		}
+		if len(literals) == 0 {
+			// A row of don't-cares covers everything
+			return b.c.Constant(onSet)
+		}
+		terms = append(terms, b.tree(AND, literals))
+	}
+	if len(terms) == 0 {
+		return b.c.Constant(!onSet)
+	}
+	sum := b.tree(OR, terms)
+	if !onSet {
+		return b.c.NOT(sum)
+	}
+	return sum
+}
+
+// tree combines ws with a balanced tree of op gates
+func (b *blifBuilder) tree(op Op, ws []Wire) Wire {
+	for len(ws) > 1 {
+		next := make([]Wire, 0, (len(ws)+1)/2)
+		for i := 0; i+1 < len(ws); i += 2 {
+			next = append(next, b.c.Gate(op, ws[i], ws[i+1]))
+		}
+		if len(ws)%2 == 1 {
+			next = append(next, ws[len(ws)-1])
+		}
+		ws = next
+	}
+	return ws[0]
+}
+
+// singleGate matches the truth table of a cover over at most three inputs
+// against constants, wires, NOT, the two-input gates, MAJ, XOR3 and MUX
+func (b *blifBuilder) singleGate(in []Wire, cubes []string, onSet bool) (Wire, bool) {
+	n := len(in)
+	truth := make([]bool, 1<<n)
+	for x := range truth {
+		truth[x] = !onSet
+		for _, cube := range cubes {
+			match := true
+			for i, ch := range cube {
+				if bit := x>>i&1 == 1; (ch == '1' && !bit) || (ch == '0' && bit) {
+					match = false
+					break
+				}
+			}
+			if match {
+				truth[x] = onSet
+				break
+			}
+		}
+	}
+	matches := func(f func(x int) bool) bool {
+		for x, v := range truth {
+			if f(x) != v {
+				return false
+			}
+		}
+		return true
+	}
+	bit := func(x, i int) bool { return x>>i&1 == 1 }
+
+	for _, v := range []bool{false, true} {
+		if matches(func(int) bool { return v }) {
+			return b.c.Constant(v), true
+		}
+	}
+	for i := 0; i < n; i++ {
+		if matches(func(x int) bool { return bit(x, i) }) {
+			return in[i], true
+		}
+		if matches(func(x int) bool { return !bit(x, i) }) {
+			return b.c.NOT(in[i]), true
+		}
+	}
+	for i := 0; i < n; i++ {
+		for j := 0; j < n; j++ {
+			if i == j {
+				continue
+			}
+			for op := AND; op < MUX; op++ {
+				if matches(func(x int) bool { return op.eval(bit(x, i), bit(x, j), false) }) {
+					return b.c.Gate(op, in[i], in[j]), true
+				}
+			}
+		}
+	}
+	if n == 3 {
+		// Majority with any input polarity; NOT is free
+		for neg := 0; neg < 8; neg++ {
+			lit := func(x, i int) bool { return bit(x, i) != (neg>>i&1 == 1) }
+			if matches(func(x int) bool { return MAJ.eval(lit(x, 0), lit(x, 1), lit(x, 2)) }) {
+				ws := make([]Wire, 3)
+				for i := range ws {
+					ws[i] = in[i]
+					if neg>>i&1 == 1 {
+						ws[i] = b.c.NOT(in[i])
+					}
+				}
+				return b.c.MAJ(ws[0], ws[1], ws[2]), true
+			}
+		}
+		for _, inv := range []bool{false, true} {
+			if matches(func(x int) bool { return XOR3.eval(bit(x, 0), bit(x, 1), bit(x, 2)) != inv }) {
+				w := b.c.XOR3(in[0], in[1], in[2])
+				if inv {
+					w = b.c.NOT(w)
+				}
+				return w, true
+			}
+		}
+		for s := 0; s < 3; s++ {
+			t, f := (s+1)%3, (s+2)%3
+			for k := 0; k < 2; k++ {
+				if matches(func(x int) bool { return MUX.eval(bit(x, s), bit(x, t), bit(x, f)) }) {
+					return b.c.MUX(in[s], in[t], in[f]), true
+				}
+				t, f = f, t
+			}
+		}
+	}
+	return 0, false
+}
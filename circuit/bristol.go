@@ -0,0 +1,202 @@
+package circuit
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// bristolArity holds the input and output counts of the fixed-size Bristol gates
+var bristolArity = map[string][2]int{"XOR": {2, 1}, "AND": {2, 1}, "INV": {1, 1}, "EQW": {1, 1}}
+
+// ParseBristol reads a circuit in Bristol Fashion.
+//
+// The header gives the gate and wire counts, then the number and bit width
+// of the input values and of the output values. Input values occupy the
+// first wires and output values the last ones, in file order. Each gate line
+// is
+//
+//	<#inputs> <#outputs> <input wires...> <output wires...> <type>
+//
+// with type XOR, AND, INV, EQ (constant: the input is the literal 0 or 1),
+// EQW (wire copy) or MAND (n ANDs of input i and input n+i). EQW and INV
+// cost nothing; XOR and AND cost one bootstrap each.
+//
+// Input ports are named in0, in1, ... and output ports out0, out1, ...
+func ParseBristol(r io.Reader) (*Netlist, error) {
+	const format = "bristol"
+	sc := bufio.NewScanner(r)
+	sc.Buffer(nil, 1<<20)
+
+	// Non-empty lines as fields, with their line numbers
+	lineNo := 0
+	next := func() ([]string, bool) {
+		for sc.Scan() {
+			lineNo++
+			if fields := strings.Fields(sc.Text()); len(fields) > 0 {
+				return fields, true
+			}
+		}
+		return nil, false
+	}
+	ints := func(fields []string) ([]int, error) {
+		result := make([]int, len(fields))
+		for i, f := range fields {
+			v, err := strconv.Atoi(f)
+			if err != nil || v < 0 {
+				return nil, netlistError(format, lineNo, "invalid number %q", f)
+			}
+			result[i] = v
+		}
+		return result, nil
+	}
+	// header reads "<count> <width...>" and returns the widths
+	header := func(what string) ([]int, error) {
+		fields, ok := next()
+		if !ok {
+			return nil, netlistError(format, lineNo, "missing %s header", what)
+		}
+		v, err := ints(fields)
+		if err != nil {
+			return nil, err
+		}
+		if len(v) == 0 || v[0] != len(v)-1 {
+			return nil, netlistError(format, lineNo, "%s header does not match its count", what)
+		}
+		return v[1:], nil
+	}
+
+	fields, ok := next()
+	if !ok {
+		return nil, netlistError(format, lineNo, "empty file")
+	}
+	counts, err := ints(fields)
+	if err != nil {
+		return nil, err
+	}
+	if len(counts) != 2 {
+		return nil, netlistError(format, lineNo, "expected \"<gates> <wires>\"")
+	}
+	numGates, numWires := counts[0], counts[1]
+
+	inWidths, err := header("input")
+	if err != nil {
+		return nil, err
+	}
+	outWidths, err := header("output")
+	if err != nil {
+		return nil, err
+	}
+
+	n := &Netlist{Circuit: New()}
+	c := n.Circuit
+	wires := make([]Wire, numWires)
+	defined := make([]bool, numWires)
+	w := 0
+	for i, width := range inWidths {
+		n.Inputs = append(n.Inputs, Port{Name: fmt.Sprintf("in%d", i), Width: width})
+		for j := 0; j < width; j++ {
+			if w >= numWires {
+				return nil, netlistError(format, lineNo, "inputs exceed %d wires", numWires)
+			}
+			wires[w], defined[w] = c.Input(), true
+			w++
+		}
+	}
+
+	for g := 0; g < numGates; g++ {
+		fields, ok := next()
+		if !ok {
+			return nil, netlistError(format, lineNo, "expected %d gates, found %d", numGates, g)
+		}
+		if len(fields) < 3 {
+			return nil, netlistError(format, lineNo, "truncated gate")
+		}
+		gate := fields[len(fields)-1]
+		v, err := ints(fields[:len(fields)-1])
+		if err != nil {
+			return nil, err
+		}
+		nin, nout := v[0], v[1]
+		if len(v) != 2+nin+nout {
+			return nil, netlistError(format, lineNo, "%s gate has %d wires, header says %d", gate, len(v)-2, nin+nout)
+		}
+		in, out := v[2:2+nin], v[2+nin:]
+
+		// EQ takes a literal instead of a wire
+		if gate == "EQ" {
+			if nin != 1 || nout != 1 || in[0] > 1 {
+				return nil, netlistError(format, lineNo, "EQ takes a constant 0 or 1 and one output")
+			}
+		} else {
+			for _, x := range in {
+				if x >= numWires || !defined[x] {
+					return nil, netlistError(format, lineNo, "wire %d is used before it is assigned", x)
+				}
+			}
+		}
+		for _, x := range out {
+			if x >= numWires {
+				return nil, netlistError(format, lineNo, "wire %d is out of range", x)
+			}
+			if defined[x] {
+				return nil, netlistError(format, lineNo, "wire %d is assigned twice", x)
+			}
+			defined[x] = true
+		}
+
+		if want, ok := bristolArity[gate]; ok && (nin != want[0] || nout != want[1]) {
+			return nil, netlistError(format, lineNo, "%s takes %d inputs and %d outputs", gate, want[0], want[1])
+		}
+		switch gate {
+		case "XOR":
+			wires[out[0]] = c.XOR(wires[in[0]], wires[in[1]])
+		case "AND":
+			wires[out[0]] = c.AND(wires[in[0]], wires[in[1]])
+		case "INV":
+			wires[out[0]] = c.NOT(wires[in[0]])
+		case "EQW":
+			wires[out[0]] = wires[in[0]]
+		case "EQ":
+			wires[out[0]] = c.Constant(in[0] == 1)
+		case "MAND":
+			if nin != 2*nout {
+				return nil, netlistError(format, lineNo, "MAND takes twice as many inputs as outputs")
+			}
+			for i := range out {
+				wires[out[i]] = c.AND(wires[in[i]], wires[in[nout+i]])
+			}
+		default:
+			return nil, netlistError(format, lineNo, "unsupported gate %q", gate)
+		}
+	}
+	if fields, ok := next(); ok {
+		return nil, netlistError(format, lineNo, "unexpected %q after %d gates", strings.Join(fields, " "), numGates)
+	}
+	if err := sc.Err(); err != nil {
+		return nil, err
+	}
+
+	// Outputs are the last wires
+	total := 0
+	for _, width := range outWidths {
+		total += width
+	}
+	if total > numWires {
+		return nil, netlistError(format, lineNo, "outputs exceed %d wires", numWires)
+	}
+	w = numWires - total
+	for i, width := range outWidths {
+		n.Outputs = append(n.Outputs, Port{Name: fmt.Sprintf("out%d", i), Width: width})
+		for j := 0; j < width; j++ {
+			if !defined[w] {
+				return nil, netlistError(format, lineNo, "output wire %d is never assigned", w)
+			}
+			c.Output(wires[w])
+			w++
+		}
+	}
+	return n, nil
+}
@@ -0,0 +1,434 @@
+// Package circuit builds boolean circuits over encrypted bits and evaluates
+// them level by level.
+//
+// A Circuit is a DAG of gate nodes. Nodes can only refer to nodes created
+// before them, so the order of construction is already a topological order.
+// An Executor groups the nodes into levels of independent gates and
+// bootstraps every level in parallel with a pool of gates.Evaluator
+// instances.
+//
+// # Example
+//
+//	c := circuit.New()
+//	a, b, cin := c.Input(), c.Input(), c.Input()
+//	c.Output(c.XOR3(a, b, cin), c.MAJ(a, b, cin))
+//
+//	outputs, err := circuit.NewExecutor(ck, 0).Run(c, []*gates.Ciphertext{ctA, ctB, ctCin})
+package circuit
+
+import (
+	"fmt"
+)
+
+// Op is the operation of a circuit node
+type Op int
+
+// Node operations. Input and Constant nodes have no operands, NOT has one,
+// MUX (selector first), MAJ and XOR3 have three and the others have two.
+const (
+	Input Op = iota
+	Constant
+	NOT
+	AND
+	OR
+	NAND
+	NOR
+	XOR
+	XNOR
+	ANDNY
+	ANDYN
+	ORNY
+	ORYN
+	MUX
+	MAJ
+	XOR3
+)
+
+var opNames = [...]string{"INPUT", "CONST", "NOT", "AND", "OR", "NAND", "NOR", "XOR", "XNOR", "ANDNY", "ANDYN", "ORNY", "ORYN", "MUX", "MAJ", "XOR3"}
+
+// String returns the gate name of op
+func (op Op) String() string {
+	if op < 0 || int(op) >= len(opNames) {
+		return fmt.Sprintf("Op(%d)", int(op))
+	}
+	return opNames[op]
+}
+
+// Bootstraps returns the number of blind rotations op costs
+func (op Op) Bootstraps() int {
+	switch op {
+	case Input, Constant, NOT:
+		return 0
+	case MUX:
+		return 2
+	default:
+		return 1
+	}
+}
+
+// arity returns the number of operands of op
+func (op Op) arity() int {
+	switch op {
+	case Input, Constant:
+		return 0
+	case NOT:
+		return 1
+	case MUX, MAJ, XOR3:
+		return 3
+	default:
+		return 2
+	}
+}
+
+// Wire identifies a node of a circuit (and the bit it computes)
+type Wire int
+
+// node is one gate of a circuit
+type node struct {
+	op    Op
+	in    [3]Wire
+	value bool // Constant nodes only
+}
+
+// Circuit is a boolean circuit under construction.
+// A Circuit is not safe for concurrent modification.
+type Circuit struct {
+	nodes   []node
+	inputs  []Wire
+	outputs []Wire
+}
+
+// New creates an empty circuit
+func New() *Circuit {
+	return &Circuit{}
+}
+
+// add appends a node after checking its operands
+func (c *Circuit) add(op Op, value bool, in ...Wire) Wire {
+	if len(in) != op.arity() {
+		panic(fmt.Sprintf("circuit: %v takes %d operands, got %d", op, op.arity(), len(in)))
+	}
+	n := node{op: op, value: value}
+	for i, w := range in {
+		if w < 0 || int(w) >= len(c.nodes) {
+			panic(fmt.Sprintf("circuit: wire %d does not belong to the circuit", w))
+		}
+		n.in[i] = w
+	}
+	if w, ok := c.fold(op, in); ok {
+		return w
+	}
+	c.nodes = append(c.nodes, n)
+	return Wire(len(c.nodes) - 1)
+}
+
+// constant returns the value of w if it is a Constant node
+func (c *Circuit) constant(w Wire) (value, ok bool) {
+	n := c.nodes[w]
+	return n.value, n.op == Constant
+}
+
+// fold simplifies a gate with constant operands: it returns a constant, an
+// existing wire, a NOT of one (which is free), or a cheaper gate for a MUX
+// with a constant data input. ok is false if no operand is constant.
+func (c *Circuit) fold(op Op, in []Wire) (Wire, bool) {
+	switch op.arity() {
+	case 1:
+		if v, ok := c.constant(in[0]); ok {
+			return c.Constant(op.eval(v, false, false)), true
+		}
+	case 2:
+		va, ca := c.constant(in[0])
+		vb, cb := c.constant(in[1])
+		switch {
+		case ca && cb:
+			return c.Constant(op.eval(va, vb, false)), true
+		case ca:
+			return c.unary(op.eval(va, false, false), op.eval(va, true, false), in[1]), true
+		case cb:
+			return c.unary(op.eval(false, vb, false), op.eval(true, vb, false), in[0]), true
+		}
+	case 3:
+		if op != MUX {
+			for i := range in {
+				if v, ok := c.constant(in[i]); ok {
+					x, y := in[(i+1)%3], in[(i+2)%3]
+					return c.gate2(func(p, q bool) bool {
+						var args [3]bool
+						args[i], args[(i+1)%3], args[(i+2)%3] = v, p, q
+						return op.eval(args[0], args[1], args[2])
+					}, x, y), true
+				}
+			}
+			break
+		}
+		if sel, ok := c.constant(in[0]); ok {
+			if sel {
+				return in[1], true
+			}
+			return in[2], true
+		}
+		va, ca := c.constant(in[1])
+		vb, cb := c.constant(in[2])
+		switch {
+		case ca && cb:
+			return c.unary(vb, va, in[0]), true
+		case ca && va:
+			return c.OR(in[0], in[2]), true
+		case ca:
+			return c.ANDNY(in[0], in[2]), true
+		case cb && vb:
+			return c.ORNY(in[0], in[1]), true
+		case cb:
+			return c.AND(in[0], in[1]), true
+		}
+	}
+	return 0, false
+}
+
+// unary returns the function of x that is f0 when x is false and f1 when x is true
+func (c *Circuit) unary(f0, f1 bool, x Wire) Wire {
+	switch {
+	case f0 == f1:
+		return c.Constant(f0)
+	case f1:
+		return x
+	default:
+		return c.NOT(x)
+	}
+}
+
+// gate2 adds the cheapest gates computing f(x, y): a constant, x, y or a
+// NOT of one of them, or a single two-input gate
+func (c *Circuit) gate2(f func(x, y bool) bool, x, y Wire) Wire {
+	switch {
+	case f(false, false) == f(false, true) && f(true, false) == f(true, true):
+		return c.unary(f(false, false), f(true, false), x)
+	case f(false, false) == f(true, false) && f(false, true) == f(true, true):
+		return c.unary(f(false, false), f(false, true), y)
+	}
+	for op := AND; op < MUX; op++ {
+		if op.eval(false, false, false) == f(false, false) && op.eval(false, true, false) == f(false, true) &&
+			op.eval(true, false, false) == f(true, false) && op.eval(true, true, false) == f(true, true) {
+			return c.add(op, false, x, y)
+		}
+	}
+	panic("circuit: no gate for truth table")
+}
+
+// Gate adds a node applying op to the operands and returns its output.
+// It panics if the number of operands does not match op.
+func (c *Circuit) Gate(op Op, in ...Wire) Wire {
+	switch op {
+	case Input:
+		return c.Input()
+	case Constant:
+		panic("circuit: use Circuit.Constant for constant nodes")
+	}
+	return c.add(op, false, in...)
+}
+
+// Input adds an input bit. Inputs are numbered in the order they are added.
+func (c *Circuit) Input() Wire {
+	w := c.add(Input, false)
+	c.inputs = append(c.inputs, w)
+	return w
+}
+
+// Constant adds a constant bit (a trivial ciphertext, no bootstrapping).
+// Gates on constant operands are folded as they are added, so AND with false
+// adds no node at all and XOR with true only adds a NOT.
+func (c *Circuit) Constant(value bool) Wire {
+	return c.add(Constant, value)
+}
+
+// Output marks wires as circuit outputs, in order
+func (c *Circuit) Output(ws ...Wire) {
+	for _, w := range ws {
+		if w < 0 || int(w) >= len(c.nodes) {
+			panic(fmt.Sprintf("circuit: wire %d does not belong to the circuit", w))
+		}
+	}
+	c.outputs = append(c.outputs, ws...)
+}
+
+// NOT adds a NOT gate (no bootstrapping)
+func (c *Circuit) NOT(a Wire) Wire { return c.add(NOT, false, a) }
+
+// AND adds an AND gate
+func (c *Circuit) AND(a, b Wire) Wire { return c.add(AND, false, a, b) }
+
+// OR adds an OR gate
+func (c *Circuit) OR(a, b Wire) Wire { return c.add(OR, false, a, b) }
+
+// NAND adds a NAND gate
+func (c *Circuit) NAND(a, b Wire) Wire { return c.add(NAND, false, a, b) }
+
+// NOR adds a NOR gate
+func (c *Circuit) NOR(a, b Wire) Wire { return c.add(NOR, false, a, b) }
+
+// XOR adds an XOR gate
+func (c *Circuit) XOR(a, b Wire) Wire { return c.add(XOR, false, a, b) }
+
+// XNOR adds an XNOR gate
+func (c *Circuit) XNOR(a, b Wire) Wire { return c.add(XNOR, false, a, b) }
+
+// ANDNY adds a NOT(a) AND b gate
+func (c *Circuit) ANDNY(a, b Wire) Wire { return c.add(ANDNY, false, a, b) }
+
+// ANDYN adds an a AND NOT(b) gate
+func (c *Circuit) ANDYN(a, b Wire) Wire { return c.add(ANDYN, false, a, b) }
+
+// ORNY adds a NOT(a) OR b gate
+func (c *Circuit) ORNY(a, b Wire) Wire { return c.add(ORNY, false, a, b) }
+
+// ORYN adds an a OR NOT(b) gate
+func (c *Circuit) ORYN(a, b Wire) Wire { return c.add(ORYN, false, a, b) }
+
+// MUX adds a multiplexer: sel ? a : b
+func (c *Circuit) MUX(sel, a, b Wire) Wire { return c.add(MUX, false, sel, a, b) }
+
+// MAJ adds a majority gate: true when at least two of a, b and c are
+// (one bootstrap)
+func (c *Circuit) MAJ(a, b, d Wire) Wire { return c.add(MAJ, false, a, b, d) }
+
+// XOR3 adds a three-input XOR gate (one bootstrap)
+func (c *Circuit) XOR3(a, b, d Wire) Wire { return c.add(XOR3, false, a, b, d) }
+
+// AND3 adds a three-input AND as two AND gates: unlike MAJ and XOR3 it
+// cannot be computed with one bootstrap (see gates.Evaluator.AND3)
+func (c *Circuit) AND3(a, b, d Wire) Wire { return c.AND(c.AND(a, b), d) }
+
+// OR3 adds a three-input OR as two OR gates
+func (c *Circuit) OR3(a, b, d Wire) Wire { return c.OR(c.OR(a, b), d) }
+
+// NumInputs returns the number of input bits
+func (c *Circuit) NumInputs() int { return len(c.inputs) }
+
+// NumOutputs returns the number of output bits
+func (c *Circuit) NumOutputs() int { return len(c.outputs) }
+
+// Levels groups the bootstrapped gates into levels: every gate of a level
+// only depends on inputs, constants and gates of earlier levels (through
+// any number of NOT gates), so the gates of one level can run in parallel.
+// Level 0 holds the inputs and constants.
+func (c *Circuit) Levels() [][]Wire {
+	depth := c.depths()
+	levels := [][]Wire{nil}
+	for i, n := range c.nodes {
+		if n.op != Input && n.op != Constant && n.op.Bootstraps() == 0 {
+			continue
+		}
+		for depth[i] >= len(levels) {
+			levels = append(levels, nil)
+		}
+		levels[depth[i]] = append(levels[depth[i]], Wire(i))
+	}
+	return levels
+}
+
+// depths returns the number of bootstrapped gates on the longest path
+// from an input to every node
+func (c *Circuit) depths() []int {
+	depth := make([]int, len(c.nodes))
+	for i, n := range c.nodes {
+		d := 0
+		for _, w := range n.in[:n.op.arity()] {
+			d = max(d, depth[w])
+		}
+		if n.op.Bootstraps() > 0 {
+			d++
+		}
+		depth[i] = d
+	}
+	return depth
+}
+
+// Stats summarizes the cost of evaluating a circuit
+type Stats struct {
+	Gates      int // Gate nodes, excluding inputs and constants
+	Bootstraps int // Blind rotations (MUX costs two)
+	Depth      int // Bootstrapped gates on the critical path
+	MaxWidth   int // Gates in the widest level; more workers than this sit idle
+}
+
+// Stats returns the cost of evaluating c
+func (c *Circuit) Stats() Stats {
+	var s Stats
+	for _, n := range c.nodes {
+		if n.op != Input && n.op != Constant {
+			s.Gates++
+		}
+		s.Bootstraps += n.op.Bootstraps()
+	}
+	levels := c.Levels()
+	s.Depth = len(levels) - 1
+	for _, level := range levels[1:] {
+		s.MaxWidth = max(s.MaxWidth, len(level))
+	}
+	return s
+}
+
+// EvalPlain evaluates c on clear bits, for testing circuits
+func (c *Circuit) EvalPlain(inputs []bool) ([]bool, error) {
+	if len(inputs) != len(c.inputs) {
+		return nil, fmt.Errorf("%w: got %d, want %d", ErrInputCount, len(inputs), len(c.inputs))
+	}
+	values := make([]bool, len(c.nodes))
+	next := 0
+	for i, n := range c.nodes {
+		switch n.op {
+		case Input:
+			values[i] = inputs[next]
+			next++
+		case Constant:
+			values[i] = n.value
+		default:
+			values[i] = n.op.eval(values[n.in[0]], values[n.in[1]], values[n.in[2]])
+		}
+	}
+	outputs := make([]bool, len(c.outputs))
+	for i, w := range c.outputs {
+		outputs[i] = values[w]
+	}
+	return outputs, nil
+}
+
+// eval applies a gate operation to clear operands (unused operands are ignored)
+func (op Op) eval(x, y, z bool) bool {
+	switch op {
+	case NOT:
+		return !x
+	case AND:
+		return x && y
+	case OR:
+		return x || y
+	case NAND:
+		return !(x && y)
+	case NOR:
+		return !(x || y)
+	case XOR:
+		return x != y
+	case XNOR:
+		return x == y
+	case ANDNY:
+		return !x && y
+	case ANDYN:
+		return x && !y
+	case ORNY:
+		return !x || y
+	case ORYN:
+		return x || !y
+	case MUX:
+		if x {
+			return y
+		}
+		return z
+	case MAJ:
+		return (x && y) || (x && z) || (y && z)
+	case XOR3:
+		return x != y != z
+	default:
+		panic(fmt.Sprintf("circuit: %v is not a gate", op))
+	}
+}
@@ -0,0 +1,203 @@
+package circuit_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/thedonutfactory/go-tfhe/circuit"
+	"github.com/thedonutfactory/go-tfhe/cloudkey"
+	"github.com/thedonutfactory/go-tfhe/gates"
+	"github.com/thedonutfactory/go-tfhe/key"
+	"github.com/thedonutfactory/go-tfhe/params"
+	"github.com/thedonutfactory/go-tfhe/tlwe"
+)
+
+// fullAdder adds a full adder to c and returns the sum and carry out
+func fullAdder(c *circuit.Circuit, a, b, cin circuit.Wire) (sum, cout circuit.Wire) {
+	return c.XOR3(a, b, cin), c.MAJ(a, b, cin)
+}
+
+// rippleAdder returns a circuit adding two n-bit numbers (inputs and outputs
+// least significant bit first, a before b, carry out last)
+func rippleAdder(n int) *circuit.Circuit {
+	c := circuit.New()
+	a := make([]circuit.Wire, n)
+	b := make([]circuit.Wire, n)
+	for i := range a {
+		a[i] = c.Input()
+	}
+	for i := range b {
+		b[i] = c.Input()
+	}
+	carry := c.Constant(false)
+	for i := 0; i < n; i++ {
+		var sum circuit.Wire
+		sum, carry = fullAdder(c, a[i], b[i], carry)
+		c.Output(sum)
+	}
+	c.Output(carry)
+	return c
+}
+
+// bits returns the n least significant bits of v
+func bits(v, n int) []bool {
+	result := make([]bool, n)
+	for i := range result {
+		result[i] = v>>i&1 == 1
+	}
+	return result
+}
+
+// value returns the integer with the given bits
+func value(bs []bool) int {
+	v := 0
+	for i, b := range bs {
+		if b {
+			v |= 1 << i
+		}
+	}
+	return v
+}
+
+func TestStats(t *testing.T) {
+	c := circuit.New()
+	a, b, cin := c.Input(), c.Input(), c.Input()
+	sum, cout := fullAdder(c, a, b, cin)
+	c.Output(sum, cout)
+
+	want := circuit.Stats{Gates: 2, Bootstraps: 2, Depth: 1, MaxWidth: 2}
+	if got := c.Stats(); got != want {
+		t.Errorf("full adder Stats() = %+v, want %+v", got, want)
+	}
+
+	// NOT is free, MUX costs two blind rotations and AND3 two ANDs
+	c.Output(c.MUX(c.NOT(cout), a, b), c.AND3(sum, a, b))
+	want = circuit.Stats{Gates: 6, Bootstraps: 6, Depth: 3, MaxWidth: 2}
+	if got := c.Stats(); got != want {
+		t.Errorf("Stats() with MUX = %+v, want %+v", got, want)
+	}
+
+	levels := c.Levels()
+	if len(levels) != 4 || len(levels[0]) != 3 || len(levels[3]) != 1 {
+		t.Errorf("Levels() = %v", levels)
+	}
+}
+
+func TestConstantFolding(t *testing.T) {
+	c := circuit.New()
+	a, b := c.Input(), c.Input()
+	yes, no := c.Constant(true), c.Constant(false)
+
+	if w := c.AND(a, no); w == a || w == b {
+		t.Errorf("AND(a, false) is not a constant")
+	}
+	if w := c.OR(no, b); w != b {
+		t.Errorf("OR(false, b) = wire %d, want b", w)
+	}
+	if w := c.MUX(yes, a, b); w != a {
+		t.Errorf("MUX(true, a, b) = wire %d, want a", w)
+	}
+	c.Output(
+		c.AND(a, no),         // false
+		c.XOR(yes, b),        // NOT b
+		c.MUX(a, yes, b),     // a OR b
+		c.MUX(a, b, no),      // a AND b
+		c.MUX(a, no, yes),    // NOT a
+		c.NAND(c.NOT(no), a), // NOT a
+		c.MAJ(a, yes, b),     // a OR b
+		c.XOR3(no, a, yes),   // NOT a
+		c.AND3(a, b, yes),    // a AND b
+	)
+
+	// Only the two MUXes with one constant data input, the MAJ and the AND3
+	// bootstrap, once each
+	if got := c.Stats().Bootstraps; got != 4 {
+		t.Errorf("Bootstraps = %d, want 4", got)
+	}
+	for v := 0; v < 4; v++ {
+		x, y := v&1 == 1, v&2 == 2
+		got, err := c.EvalPlain([]bool{x, y})
+		if err != nil {
+			t.Fatal(err)
+		}
+		want := []bool{false, !y, x || y, x && y, !x, !x, x || y, !x, x && y}
+		for i := range want {
+			if got[i] != want[i] {
+				t.Errorf("a=%v b=%v: output %d = %v, want %v", x, y, i, got[i], want[i])
+			}
+		}
+	}
+}
+
+func TestEvalPlain(t *testing.T) {
+	c := rippleAdder(4)
+	for x := 0; x < 16; x++ {
+		for y := 0; y < 16; y++ {
+			out, err := c.EvalPlain(append(bits(x, 4), bits(y, 4)...))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := value(out); got != x+y {
+				t.Errorf("%d + %d = %d", x, y, got)
+			}
+		}
+	}
+
+	if _, err := c.EvalPlain(bits(0, 3)); !errors.Is(err, circuit.ErrInputCount) {
+		t.Errorf("EvalPlain with 3 inputs: got %v, want ErrInputCount", err)
+	}
+}
+
+func TestRun(t *testing.T) {
+	sk := key.NewSecretKey()
+	ck := cloudkey.NewCloudKey(sk)
+	encrypt := func(bs []bool) []*gates.Ciphertext {
+		result := make([]*gates.Ciphertext, len(bs))
+		for i, b := range bs {
+			result[i] = tlwe.NewTLWELv0().EncryptBool(b, params.GetTLWELv0().ALPHA, sk.KeyLv0)
+		}
+		return result
+	}
+	decrypt := func(cts []*gates.Ciphertext) []bool {
+		result := make([]bool, len(cts))
+		for i, ct := range cts {
+			result[i] = ct.DecryptBool(sk.KeyLv0)
+		}
+		return result
+	}
+
+	adder := rippleAdder(4)
+	for _, workers := range []int{1, 3} {
+		x := circuit.NewExecutor(ck, workers)
+		for _, tc := range []struct{ a, b int }{{0, 0}, {9, 7}, {15, 15}} {
+			out, err := x.Run(adder, encrypt(append(bits(tc.a, 4), bits(tc.b, 4)...)))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := value(decrypt(out)); got != tc.a+tc.b {
+				t.Errorf("%d workers: %d + %d = %d", workers, tc.a, tc.b, got)
+			}
+		}
+	}
+
+	// NOT chains, MUX and outputs that are inputs
+	c := circuit.New()
+	s, a, b := c.Input(), c.Input(), c.Input()
+	c.Output(c.MUX(c.NOT(c.NOT(s)), c.NOT(a), b), a, c.ANDYN(c.Constant(true), s))
+	x := circuit.NewExecutor(ck, 2)
+	for v := 0; v < 8; v++ {
+		in := bits(v, 3)
+		want, _ := c.EvalPlain(in)
+		out, err := x.Run(c, encrypt(in))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := decrypt(out); value(got) != value(want) {
+			t.Errorf("inputs %v: got %v, want %v", in, got, want)
+		}
+	}
+
+	if _, err := x.Run(c, encrypt(bits(0, 2))); !errors.Is(err, circuit.ErrInputCount) {
+		t.Errorf("Run with 2 inputs: got %v, want ErrInputCount", err)
+	}
+}
@@ -0,0 +1,157 @@
+package circuit
+
+import (
+	"errors"
+	"fmt"
+	"runtime"
+	"sync"
+
+	"github.com/thedonutfactory/go-tfhe/cloudkey"
+	"github.com/thedonutfactory/go-tfhe/gates"
+	"github.com/thedonutfactory/go-tfhe/params"
+)
+
+// ErrInputCount is returned when a circuit is given the wrong number of inputs
+var ErrInputCount = errors.New("circuit: wrong number of inputs")
+
+// Executor evaluates circuits on encrypted bits with a pool of evaluators,
+// one per worker goroutine.
+// An Executor runs one circuit at a time; use one Executor per goroutine
+// to run circuits concurrently.
+type Executor struct {
+	ck    *cloudkey.CloudKey
+	evals []*gates.Evaluator
+}
+
+// NewExecutor creates an executor with the given number of workers.
+// workers <= 0 uses one worker per CPU.
+func NewExecutor(ck *cloudkey.CloudKey, workers int) *Executor {
+	if workers <= 0 {
+		workers = runtime.NumCPU()
+	}
+	base := gates.NewEvaluator(ck)
+	evals := []*gates.Evaluator{base}
+	for len(evals) < workers {
+		evals = append(evals, base.ShallowCopy())
+	}
+	return &Executor{ck: ck, evals: evals}
+}
+
+// Workers returns the number of worker goroutines
+func (x *Executor) Workers() int {
+	return len(x.evals)
+}
+
+// Run evaluates c on encrypted inputs, given in the order the inputs were
+// added, and returns the outputs in the order they were marked.
+//
+// The bootstrapped gates of each level (see Circuit.Levels) are spread over
+// the workers; NOT gates and constants are evaluated inline.
+func (x *Executor) Run(c *Circuit, inputs []*gates.Ciphertext) ([]*gates.Ciphertext, error) {
+	if len(inputs) != len(c.inputs) {
+		return nil, fmt.Errorf("%w: got %d, want %d", ErrInputCount, len(inputs), len(c.inputs))
+	}
+	for i, in := range inputs {
+		if in.Params != x.ck.Params {
+			return nil, fmt.Errorf("%w: circuit input %d", params.ErrParamsMismatch, i)
+		}
+	}
+
+	// Bootstrapped and free nodes of every level, in node order
+	depth := c.depths()
+	levels := c.Levels()
+	free := make([][]Wire, len(levels))
+	for i, n := range c.nodes {
+		if n.op == NOT {
+			free[depth[i]] = append(free[depth[i]], Wire(i))
+		}
+	}
+
+	values := make([]*gates.Ciphertext, len(c.nodes))
+	for i, w := range c.inputs {
+		values[w] = inputs[i]
+	}
+	for level, ws := range levels {
+		if level == 0 {
+			for _, w := range ws {
+				if n := c.nodes[w]; n.op == Constant {
+					values[w] = gates.ConstantWithParams(n.value, x.ck.Params)
+				}
+			}
+		} else {
+			x.runLevel(c, ws, values)
+		}
+		for _, w := range free[level] {
+			values[w] = gates.NOT(values[c.nodes[w].in[0]])
+		}
+	}
+
+	outputs := make([]*gates.Ciphertext, len(c.outputs))
+	for i, w := range c.outputs {
+		outputs[i] = values[w]
+	}
+	return outputs, nil
+}
+
+// runLevel bootstraps the independent gates ws in parallel
+func (x *Executor) runLevel(c *Circuit, ws []Wire, values []*gates.Ciphertext) {
+	if len(ws) == 1 || len(x.evals) == 1 {
+		for _, w := range ws {
+			values[w] = evalGate(x.evals[0], c.nodes[w], values)
+		}
+		return
+	}
+
+	jobs := make(chan Wire, len(ws))
+	for _, w := range ws {
+		jobs <- w
+	}
+	close(jobs)
+
+	var wg sync.WaitGroup
+	for _, e := range x.evals[:min(len(x.evals), len(ws))] {
+		wg.Add(1)
+		go func(e *gates.Evaluator) {
+			defer wg.Done()
+			for w := range jobs {
+				values[w] = evalGate(e, c.nodes[w], values)
+			}
+		}(e)
+	}
+	wg.Wait()
+}
+
+// evalGate evaluates one bootstrapped gate
+func evalGate(e *gates.Evaluator, n node, values []*gates.Ciphertext) *gates.Ciphertext {
+	a, b := values[n.in[0]], values[n.in[1]]
+	switch n.op {
+	case AND:
+		return e.AND(a, b)
+	case OR:
+		return e.OR(a, b)
+	case NAND:
+		return e.NAND(a, b)
+	case NOR:
+		return e.NOR(a, b)
+	case XOR:
+		return e.XOR(a, b)
+	case XNOR:
+		return e.XNOR(a, b)
+	case ANDNY:
+		return e.ANDNY(a, b)
+	case ANDYN:
+		return e.ANDYN(a, b)
+	case ORNY:
+		return e.ORNY(a, b)
+	case ORYN:
+		return e.ORYN(a, b)
+	case MUX:
+		return e.MUX(a, b, values[n.in[2]])
+	case MAJ:
+		return e.MAJ(a, b, values[n.in[2]])
+	case XOR3:
+		return e.XOR3(a, b, values[n.in[2]])
+	default:
+		panic(fmt.Sprintf("circuit: %v is not a bootstrapped gate", n.op))
+	}
+}
@@ -0,0 +1,104 @@
+package circuit
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrNetlist is returned when a netlist file cannot be parsed
+var ErrNetlist = errors.New("circuit: malformed netlist")
+
+// Port is a named group of consecutive circuit inputs or outputs
+type Port struct {
+	Name  string
+	Width int
+}
+
+// Netlist is a circuit read from a netlist file, with the ports that group
+// its input and output bits
+type Netlist struct {
+	Circuit *Circuit
+	Inputs  []Port // In circuit input order
+	Outputs []Port // In circuit output order
+}
+
+// netlistError returns an ErrNetlist error for a line of a netlist file
+func netlistError(format string, line int, msg string, args ...any) error {
+	return fmt.Errorf("%w: %s line %d: %s", ErrNetlist, format, line, fmt.Sprintf(msg, args...))
+}
+
+// cellSpec describes a Yosys internal gate cell ($_AND_ and friends)
+type cellSpec struct {
+	inputs []string                         // Input port names, in the order passed to build
+	build  func(c *Circuit, in []Wire) Wire // Output port Y
+}
+
+// yosysCells maps the Yosys internal gate cells onto circuit gates
+var yosysCells = map[string]cellSpec{
+	"$_BUF_":    {[]string{"A"}, func(c *Circuit, in []Wire) Wire { return in[0] }},
+	"$_NOT_":    {[]string{"A"}, func(c *Circuit, in []Wire) Wire { return c.NOT(in[0]) }},
+	"$_AND_":    {[]string{"A", "B"}, func(c *Circuit, in []Wire) Wire { return c.AND(in[0], in[1]) }},
+	"$_NAND_":   {[]string{"A", "B"}, func(c *Circuit, in []Wire) Wire { return c.NAND(in[0], in[1]) }},
+	"$_OR_":     {[]string{"A", "B"}, func(c *Circuit, in []Wire) Wire { return c.OR(in[0], in[1]) }},
+	"$_NOR_":    {[]string{"A", "B"}, func(c *Circuit, in []Wire) Wire { return c.NOR(in[0], in[1]) }},
+	"$_XOR_":    {[]string{"A", "B"}, func(c *Circuit, in []Wire) Wire { return c.XOR(in[0], in[1]) }},
+	"$_XNOR_":   {[]string{"A", "B"}, func(c *Circuit, in []Wire) Wire { return c.XNOR(in[0], in[1]) }},
+	"$_ANDNOT_": {[]string{"A", "B"}, func(c *Circuit, in []Wire) Wire { return c.ANDYN(in[0], in[1]) }},
+	"$_ORNOT_":  {[]string{"A", "B"}, func(c *Circuit, in []Wire) Wire { return c.ORYN(in[0], in[1]) }},
+	// Y = S ? B : A
+	"$_MUX_":  {[]string{"S", "B", "A"}, func(c *Circuit, in []Wire) Wire { return c.MUX(in[0], in[1], in[2]) }},
+	"$_NMUX_": {[]string{"S", "B", "A"}, func(c *Circuit, in []Wire) Wire { return c.NOT(c.MUX(in[0], in[1], in[2])) }},
+}
+
+// ErrPort is returned when port values do not match the ports of a netlist
+var ErrPort = errors.New("circuit: unknown port or wrong port width")
+
+// FlattenPorts concatenates the bit vectors of named ports in port order,
+// for use as circuit inputs. Bits are least significant first, as produced by
+// bitutils.ToBits and bitutils.EncryptBits.
+func FlattenPorts[T any](ports []Port, values map[string][]T) ([]T, error) {
+	var result []T
+	for _, p := range ports {
+		bits, ok := values[p.Name]
+		if !ok {
+			return nil, fmt.Errorf("%w: missing value for %q", ErrPort, p.Name)
+		}
+		if len(bits) != p.Width {
+			return nil, fmt.Errorf("%w: %q has %d bits, want %d", ErrPort, p.Name, len(bits), p.Width)
+		}
+		result = append(result, bits...)
+	}
+	if len(values) != len(ports) {
+		for name := range values {
+			if !hasPort(ports, name) {
+				return nil, fmt.Errorf("%w: no port named %q", ErrPort, name)
+			}
+		}
+	}
+	return result, nil
+}
+
+// SplitPorts splits circuit outputs into the bit vectors of named ports
+func SplitPorts[T any](ports []Port, bits []T) (map[string][]T, error) {
+	result := make(map[string][]T, len(ports))
+	for _, p := range ports {
+		if len(bits) < p.Width {
+			return nil, fmt.Errorf("%w: not enough bits for %q", ErrPort, p.Name)
+		}
+		result[p.Name], bits = bits[:p.Width:p.Width], bits[p.Width:]
+	}
+	if len(bits) != 0 {
+		return nil, fmt.Errorf("%w: %d bits left over", ErrPort, len(bits))
+	}
+	return result, nil
+}
+
+// hasPort reports whether ports has a port called name
+func hasPort(ports []Port, name string) bool {
+	for _, p := range ports {
+		if p.Name == name {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,175 @@
+package circuit_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/thedonutfactory/go-tfhe/circuit"
+	"github.com/thedonutfactory/go-tfhe/cloudkey"
+	"github.com/thedonutfactory/go-tfhe/gates"
+	"github.com/thedonutfactory/go-tfhe/key"
+	"github.com/thedonutfactory/go-tfhe/params"
+	"github.com/thedonutfactory/go-tfhe/tlwe"
+)
+
+// bristolAdder adds two 2-bit values (least significant bit first) into a
+// 3-bit value, using every Bristol Fashion gate type
+const bristolAdder = `13 18
+2 2 2
+1 3
+
+2 1 0 2 4 XOR
+4 2 0 1 2 3 5 6 MAND
+2 1 1 3 7 XOR
+2 1 7 5 8 XOR
+2 1 7 5 9 AND
+1 1 6 10 INV
+1 1 9 11 INV
+2 1 10 11 12 AND
+1 1 1 14 EQ
+2 1 12 14 13 XOR
+1 1 4 15 EQW
+1 1 8 16 EQW
+1 1 13 17 EQW
+`
+
+// blifAdder is a full adder with signals used before they are defined
+const blifAdder = `# full adder
+.model adder
+.inputs a b \
+  cin
+.outputs sum cout sel
+.names t cin sum
+01 1
+10 1
+.names a b t
+00 0
+11 0
+.names a b cin cout
+11- 1
+1-1 1
+-11 1
+.subckt $_MUX_ S=cin A=a B=b Y=sel
+.end
+`
+
+func TestParseBristol(t *testing.T) {
+	n, err := circuit.ParseBristol(strings.NewReader(bristolAdder))
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantPorts := []circuit.Port{{Name: "in0", Width: 2}, {Name: "in1", Width: 2}}
+	if len(n.Inputs) != 2 || n.Inputs[0] != wantPorts[0] || n.Inputs[1] != wantPorts[1] {
+		t.Errorf("Inputs = %v, want %v", n.Inputs, wantPorts)
+	}
+	if len(n.Outputs) != 1 || n.Outputs[0].Width != 3 {
+		t.Errorf("Outputs = %v", n.Outputs)
+	}
+	// 8 XOR and AND gates, but the XOR with the constant 1 folds to a NOT
+	if got := n.Circuit.Stats().Bootstraps; got != 7 {
+		t.Errorf("Bootstraps = %d, want 7", got)
+	}
+
+	for x := 0; x < 4; x++ {
+		for y := 0; y < 4; y++ {
+			out, err := n.Circuit.EvalPlain(append(bits(x, 2), bits(y, 2)...))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := value(out); got != x+y {
+				t.Errorf("%d + %d = %d", x, y, got)
+			}
+		}
+	}
+}
+
+func TestParseBLIF(t *testing.T) {
+	n, err := circuit.ParseBLIF(strings.NewReader(blifAdder))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(n.Inputs) != 3 || n.Inputs[2].Name != "cin" || len(n.Outputs) != 3 {
+		t.Errorf("ports = %v / %v", n.Inputs, n.Outputs)
+	}
+
+	for v := 0; v < 8; v++ {
+		a, b, cin := v&1, v>>1&1, v>>2&1
+		out, err := n.Circuit.EvalPlain(bits(v, 3))
+		if err != nil {
+			t.Fatal(err)
+		}
+		sel := a
+		if cin == 1 {
+			sel = b
+		}
+		if got, want := value(out), (a+b+cin)&1|(a+b+cin)>>1<<1|sel<<2; got != want {
+			t.Errorf("a=%d b=%d cin=%d: outputs %03b, want %03b", a, b, cin, got, want)
+		}
+	}
+
+	// Covers become single gates: XOR, XNOR, MAJ and MUX
+	if got := n.Circuit.Stats(); got.Bootstraps != 1+1+1+2 {
+		t.Errorf("Stats() = %+v", got)
+	}
+}
+
+func TestParseNetlistErrors(t *testing.T) {
+	testCases := []struct {
+		name   string
+		parse  func(string) error
+		source string
+	}{
+		{"bristol undefined wire", parseBristol, "1 3\n1 1\n1 1\n2 1 0 1 2 AND\n"},
+		{"bristol unknown gate", parseBristol, "1 3\n2 1 1\n1 1\n2 1 0 1 2 OR\n"},
+		{"bristol wire assigned twice", parseBristol, "1 3\n2 1 1\n1 1\n2 1 0 1 1 AND\n"},
+		{"bristol bad header", parseBristol, "1 3\n3 1 1\n1 1\n2 1 0 1 2 AND\n"},
+		{"bristol missing gates", parseBristol, "2 3\n2 1 1\n1 1\n2 1 0 1 2 AND\n"},
+		{"blif undriven signal", parseBLIF, ".model m\n.inputs a\n.outputs y\n.names a b y\n11 1\n.end\n"},
+		{"blif loop", parseBLIF, ".model m\n.inputs a\n.outputs y\n.names a z y\n11 1\n.names y z\n1 1\n.end\n"},
+		{"blif latch", parseBLIF, ".model m\n.inputs a\n.outputs y\n.latch a y 0\n.end\n"},
+		{"blif mixed cover", parseBLIF, ".model m\n.inputs a b\n.outputs y\n.names a b y\n11 1\n00 0\n.end\n"},
+		{"blif unknown cell", parseBLIF, ".model m\n.inputs a b\n.outputs y\n.subckt adder A=a B=b Y=y\n.end\n"},
+	}
+
+	for _, tc := range testCases {
+		if err := tc.parse(tc.source); !errors.Is(err, circuit.ErrNetlist) {
+			t.Errorf("%s: got %v, want ErrNetlist", tc.name, err)
+		}
+	}
+}
+
+func parseBristol(s string) error {
+	_, err := circuit.ParseBristol(strings.NewReader(s))
+	return err
+}
+
+func parseBLIF(s string) error {
+	_, err := circuit.ParseBLIF(strings.NewReader(s))
+	return err
+}
+
+func TestRunBristol(t *testing.T) {
+	sk := key.NewSecretKey()
+	ck := cloudkey.NewCloudKey(sk)
+
+	n, err := circuit.ParseBristol(strings.NewReader(bristolAdder))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var inputs []*gates.Ciphertext
+	for _, b := range append(bits(3, 2), bits(2, 2)...) {
+		inputs = append(inputs, tlwe.NewTLWELv0().EncryptBool(b, params.GetTLWELv0().ALPHA, sk.KeyLv0))
+	}
+	out, err := circuit.NewExecutor(ck, 2).Run(n.Circuit, inputs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := make([]bool, len(out))
+	for i, ct := range out {
+		got[i] = ct.DecryptBool(sk.KeyLv0)
+	}
+	if value(got) != 5 {
+		t.Errorf("3 + 2 = %d", value(got))
+	}
+}
@@ -0,0 +1,435 @@
+package circuit
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+)
+
+// YosysOptions configures ParseYosysJSON
+type YosysOptions struct {
+	// Module is the module to load. If empty, the module marked as top is
+	// used, or the only module of the file.
+	Module string
+
+	// Cycles is the number of clock edges to unroll. Flip-flops start from
+	// their init attribute (or zero), and the outputs are read after the
+	// last edge; inputs hold their value for every cycle.
+	Cycles int
+}
+
+// ParseYosysJSON reads a netlist written by the Yosys write_json command.
+//
+// The module must be mapped to the Yosys internal gate cells ($_AND_, $_OR_,
+// $_XOR_, $_MUX_, $_NOT_, ..., for example with "synth; abc -g AND,OR,XOR,MUX")
+// plus $_DFF_P_, $_DFF_N_, $_DFFE_*_, $dff and $dffe flip-flops, which are
+// unrolled for opts.Cycles clock edges. A flip-flop with an enable keeps its
+// value through a MUX when the enable is inactive.
+//
+// The ports of the netlist are the module ports in declaration order, with
+// bits least significant first; FlattenPorts and SplitPorts convert between
+// them and the circuit inputs and outputs.
+func ParseYosysJSON(r io.Reader, opts YosysOptions) (*Netlist, error) {
+	if opts.Cycles < 0 {
+		return nil, yosysError("negative cycle count %d", opts.Cycles)
+	}
+	var file struct {
+		Modules map[string]*yosysModule `json:"modules"`
+	}
+	if err := json.NewDecoder(r).Decode(&file); err != nil {
+		return nil, fmt.Errorf("%w: yosys: %v", ErrNetlist, err)
+	}
+	m, err := selectModule(file.Modules, opts.Module)
+	if err != nil {
+		return nil, err
+	}
+
+	b := &yosysBuilder{
+		n:        &Netlist{Circuit: New()},
+		drivers:  make(map[int]*yosysDriver),
+		driven:   make(map[int]string),
+		inputs:   make(map[int]Wire),
+		visiting: make(map[int]bool),
+		consts:   make(map[bool]Wire),
+	}
+	if err := b.collectCells(m); err != nil {
+		return nil, err
+	}
+	c := b.n.Circuit
+
+	var outputs []yosysPort
+	for _, p := range m.Ports {
+		switch p.Direction {
+		case "input":
+			for _, bit := range p.Bits {
+				if bit.value != "" {
+					return nil, yosysError("input port %q has a constant bit", p.name)
+				}
+				if _, ok := b.driven[bit.net]; ok {
+					return nil, yosysError("input port %q is driven by a cell", p.name)
+				}
+				b.inputs[bit.net] = c.Input()
+			}
+			b.n.Inputs = append(b.n.Inputs, Port{Name: p.name, Width: len(p.Bits)})
+		case "output":
+			outputs = append(outputs, p)
+		default:
+			return nil, yosysError("port %q has unsupported direction %q", p.name, p.Direction)
+		}
+	}
+
+	// Unroll the flip-flops
+	b.state = make(map[int]Wire)
+	init := initValues(m)
+	for _, ff := range b.dffs {
+		for _, q := range ff.q {
+			b.state[q.net] = b.constant(init[q.net])
+		}
+	}
+	for cycle := 0; cycle < opts.Cycles; cycle++ {
+		b.memo = make(map[int]Wire)
+		next := make(map[int]Wire, len(b.state))
+		for _, ff := range b.dffs {
+			var en Wire
+			if ff.en != nil {
+				if en, err = b.bit(*ff.en); err != nil {
+					return nil, err
+				}
+			}
+			for i, q := range ff.q {
+				d, err := b.bit(ff.d[i])
+				if err != nil {
+					return nil, err
+				}
+				switch {
+				case ff.en == nil:
+					next[q.net] = d
+				case ff.enHigh:
+					next[q.net] = c.MUX(en, d, b.state[q.net])
+				default:
+					next[q.net] = c.MUX(en, b.state[q.net], d)
+				}
+			}
+		}
+		b.state = next
+	}
+
+	b.memo = make(map[int]Wire)
+	for _, p := range outputs {
+		for _, bit := range p.Bits {
+			w, err := b.bit(bit)
+			if err != nil {
+				return nil, err
+			}
+			c.Output(w)
+		}
+		b.n.Outputs = append(b.n.Outputs, Port{Name: p.name, Width: len(p.Bits)})
+	}
+	return b.n, nil
+}
+
+// yosysError returns an ErrNetlist error for a Yosys netlist
+func yosysError(msg string, args ...any) error {
+	return fmt.Errorf("%w: yosys: %s", ErrNetlist, fmt.Sprintf(msg, args...))
+}
+
+// yosysModule is a module of a Yosys JSON netlist
+type yosysModule struct {
+	Attributes map[string]any          `json:"attributes"`
+	Ports      yosysPorts              `json:"ports"`
+	Cells      map[string]yosysCell    `json:"cells"`
+	Netnames   map[string]yosysNetname `json:"netnames"`
+}
+
+// yosysCell is a cell instance
+type yosysCell struct {
+	Type        string                `json:"type"`
+	Parameters  map[string]any        `json:"parameters"`
+	Connections map[string][]yosysBit `json:"connections"`
+}
+
+// yosysNetname is a named net (only its bits and init attribute are used)
+type yosysNetname struct {
+	Bits       []yosysBit     `json:"bits"`
+	Attributes map[string]any `json:"attributes"`
+}
+
+// yosysPort is a module port
+type yosysPort struct {
+	name      string
+	Direction string     `json:"direction"`
+	Bits      []yosysBit `json:"bits"`
+}
+
+// yosysPorts keeps the module ports in declaration order
+type yosysPorts []yosysPort
+
+// UnmarshalJSON decodes the ports object, preserving the key order
+func (ps *yosysPorts) UnmarshalJSON(data []byte) error {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	if tok, err := dec.Token(); err != nil || tok != json.Delim('{') {
+		return fmt.Errorf("ports must be an object")
+	}
+	for dec.More() {
+		tok, err := dec.Token()
+		if err != nil {
+			return err
+		}
+		p := yosysPort{name: tok.(string)}
+		if err := dec.Decode(&p); err != nil {
+			return err
+		}
+		*ps = append(*ps, p)
+	}
+	_, err := dec.Token()
+	return err
+}
+
+// yosysBit is a net number, or a constant "0", "1", "x" or "z"
+type yosysBit struct {
+	net   int
+	value string
+}
+
+// UnmarshalJSON decodes a net number or a constant string
+func (b *yosysBit) UnmarshalJSON(data []byte) error {
+	if err := json.Unmarshal(data, &b.net); err == nil {
+		return nil
+	}
+	if err := json.Unmarshal(data, &b.value); err != nil {
+		return fmt.Errorf("invalid bit %s", data)
+	}
+	if b.value != "0" && b.value != "1" && b.value != "x" && b.value != "z" {
+		return fmt.Errorf("invalid constant bit %q", b.value)
+	}
+	return nil
+}
+
+// paramTrue reports whether a Yosys parameter or attribute is non-zero.
+// Values are binary strings, or numbers in older Yosys versions.
+func paramTrue(v any) bool {
+	switch v := v.(type) {
+	case string:
+		return strings.Contains(v, "1")
+	case float64:
+		return v != 0
+	}
+	return false
+}
+
+// selectModule returns the module to load
+func selectModule(modules map[string]*yosysModule, name string) (*yosysModule, error) {
+	if name != "" {
+		m, ok := modules[name]
+		if !ok {
+			return nil, yosysError("no module %q", name)
+		}
+		return m, nil
+	}
+	var top *yosysModule
+	for _, m := range modules {
+		if paramTrue(m.Attributes["top"]) {
+			top = m
+		}
+	}
+	if top != nil {
+		return top, nil
+	}
+	if len(modules) == 1 {
+		for _, m := range modules {
+			return m, nil
+		}
+	}
+	return nil, yosysError("%d modules and none is marked as top; set YosysOptions.Module", len(modules))
+}
+
+// initValues returns the init attribute of every net that has one
+func initValues(m *yosysModule) map[int]bool {
+	result := make(map[int]bool)
+	for _, net := range m.Netnames {
+		init, ok := net.Attributes["init"].(string)
+		if !ok {
+			continue
+		}
+		// The attribute is most significant bit first
+		for i, bit := range net.Bits {
+			if j := len(init) - 1 - i; j >= 0 && bit.value == "" {
+				result[bit.net] = init[j] == '1'
+			}
+		}
+	}
+	return result
+}
+
+// yosysDriver is a combinational gate cell driving one net
+type yosysDriver struct {
+	name   string
+	spec   *cellSpec
+	inputs []yosysBit
+}
+
+// yosysDFF is a (possibly multi-bit) flip-flop
+type yosysDFF struct {
+	name   string
+	d, q   []yosysBit
+	en     *yosysBit // nil if the flip-flop has no enable
+	enHigh bool
+}
+
+// yosysBuilder unrolls a Yosys module into a circuit
+type yosysBuilder struct {
+	n        *Netlist
+	drivers  map[int]*yosysDriver
+	dffs     []*yosysDFF
+	inputs   map[int]Wire
+	state    map[int]Wire // Flip-flop outputs in the current cycle
+	memo     map[int]Wire // Nets built in the current cycle
+	visiting map[int]bool
+	driven   map[int]string // Cell driving every net
+	consts   map[bool]Wire
+}
+
+// collectCells sorts the cells of m into gate drivers and flip-flops
+func (b *yosysBuilder) collectCells(m *yosysModule) error {
+	names := make([]string, 0, len(m.Cells))
+	for name := range m.Cells {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	drive := func(cell string, bits []yosysBit) error {
+		for _, bit := range bits {
+			if bit.value != "" {
+				return yosysError("cell %s drives a constant", cell)
+			}
+			if other, ok := b.driven[bit.net]; ok {
+				return yosysError("net %d is driven by %s and %s", bit.net, other, cell)
+			}
+			b.driven[bit.net] = cell
+		}
+		return nil
+	}
+	port := func(name string, cell yosysCell, port string, width int) ([]yosysBit, error) {
+		bits, ok := cell.Connections[port]
+		if !ok || (width > 0 && len(bits) != width) {
+			return nil, yosysError("cell %s (%s) has no %d-bit port %s", name, cell.Type, width, port)
+		}
+		return bits, nil
+	}
+
+	for _, name := range names {
+		cell := m.Cells[name]
+		if spec, ok := yosysCells[cell.Type]; ok {
+			d := &yosysDriver{name: name, spec: &spec}
+			for _, p := range spec.inputs {
+				bits, err := port(name, cell, p, 1)
+				if err != nil {
+					return err
+				}
+				d.inputs = append(d.inputs, bits[0])
+			}
+			y, err := port(name, cell, "Y", 1)
+			if err != nil {
+				return err
+			}
+			if err := drive(name, y); err != nil {
+				return err
+			}
+			b.drivers[y[0].net] = d
+			continue
+		}
+
+		ff := &yosysDFF{name: name}
+		var err error
+		switch t := cell.Type; {
+		case t == "$_DFF_P_" || t == "$_DFF_N_":
+		case strings.HasPrefix(t, "$_DFFE_") && len(t) == len("$_DFFE_PP_"):
+			ff.en = &yosysBit{}
+			ff.enHigh = t[len(t)-2] == 'P'
+		case t == "$dff":
+		case t == "$dffe":
+			ff.en = &yosysBit{}
+			ff.enHigh = paramTrue(cell.Parameters["EN_POLARITY"])
+		default:
+			return yosysError("unsupported cell type %s (%s); map the design to gate cells first", cell.Type, name)
+		}
+		fine := strings.HasPrefix(cell.Type, "$_")
+		width := 1
+		if !fine {
+			width = 0
+		}
+		if ff.d, err = port(name, cell, "D", width); err != nil {
+			return err
+		}
+		if ff.q, err = port(name, cell, "Q", len(ff.d)); err != nil {
+			return err
+		}
+		if ff.en != nil {
+			enPort := "E"
+			if !fine {
+				enPort = "EN"
+			}
+			en, err := port(name, cell, enPort, 1)
+			if err != nil {
+				return err
+			}
+			*ff.en = en[0]
+		}
+		if err := drive(name, ff.q); err != nil {
+			return err
+		}
+		b.dffs = append(b.dffs, ff)
+	}
+	return nil
+}
+
+// constant returns a shared constant wire
+func (b *yosysBuilder) constant(v bool) Wire {
+	w, ok := b.consts[v]
+	if !ok {
+		w = b.n.Circuit.Constant(v)
+		b.consts[v] = w
+	}
+	return w
+}
+
+// bit returns the wire of a bit in the current cycle, building its driver first
+func (b *yosysBuilder) bit(bit yosysBit) (Wire, error) {
+	if bit.value != "" {
+		// Undefined (x) and floating (z) bits are taken as zero
+		return b.constant(bit.value == "1"), nil
+	}
+	if w, ok := b.inputs[bit.net]; ok {
+		return w, nil
+	}
+	if w, ok := b.state[bit.net]; ok {
+		return w, nil
+	}
+	if w, ok := b.memo[bit.net]; ok {
+		return w, nil
+	}
+	d, ok := b.drivers[bit.net]
+	if !ok {
+		return 0, yosysError("net %d is never driven", bit.net)
+	}
+	if b.visiting[bit.net] {
+		return 0, yosysError("combinational loop through cell %s", d.name)
+	}
+	b.visiting[bit.net] = true
+
+	in := make([]Wire, len(d.inputs))
+	for i, input := range d.inputs {
+		w, err := b.bit(input)
+		if err != nil {
+			return 0, err
+		}
+		in[i] = w
+	}
+	w := d.spec.build(b.n.Circuit, in)
+	b.memo[bit.net] = w
+	delete(b.visiting, bit.net)
+	return w, nil
+}
@@ -0,0 +1,188 @@
+package circuit_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/thedonutfactory/go-tfhe/bitutils"
+	"github.com/thedonutfactory/go-tfhe/circuit"
+	"github.com/thedonutfactory/go-tfhe/cloudkey"
+	"github.com/thedonutfactory/go-tfhe/gates"
+	"github.com/thedonutfactory/go-tfhe/key"
+	"github.com/thedonutfactory/go-tfhe/params"
+)
+
+// yosysComb is a combinational module whose ports are not in alphabetical order:
+// y = {b[0] ? a[1] : a[0], a[0] & b[0]}, z = {1, ~a[0]}
+const yosysComb = `{
+  "creator": "Yosys",
+  "modules": {
+    "helper": {"attributes": {}, "ports": {}, "cells": {}, "netnames": {}},
+    "comb": {
+      "attributes": {"top": "00000000000000000000000000000001"},
+      "ports": {
+        "b": {"direction": "input", "bits": [4, 5]},
+        "a": {"direction": "input", "bits": [2, 3]},
+        "y": {"direction": "output", "bits": [6, 7]},
+        "z": {"direction": "output", "bits": [8, "1"]}
+      },
+      "cells": {
+        "$and": {"type": "$_AND_", "connections": {"A": [2], "B": [4], "Y": [6]}},
+        "$mux": {"type": "$_MUX_", "connections": {"S": [4], "A": [2], "B": [3], "Y": [7]}},
+        "$not": {"type": "$_NOT_", "connections": {"A": [2], "Y": [8]}}
+      },
+      "netnames": {}
+    }
+  }
+}`
+
+// yosysCounter is a 2-bit counter that counts when en is set
+const yosysCounter = `{
+  "modules": {
+    "counter": {
+      "ports": {
+        "en": {"direction": "input", "bits": [2]},
+        "count": {"direction": "output", "bits": [3, 4]}
+      },
+      "cells": {
+        "$q0": {"type": "$_DFFE_PP_", "connections": {"C": [9], "D": [5], "E": [2], "Q": [3]}},
+        "$q1": {"type": "$dffe", "parameters": {"WIDTH": 1, "EN_POLARITY": "1"},
+                "connections": {"CLK": [9], "D": [6], "EN": [2], "Q": [4]}},
+        "$inc0": {"type": "$_NOT_", "connections": {"A": [3], "Y": [5]}},
+        "$inc1": {"type": "$_XOR_", "connections": {"A": [3], "B": [4], "Y": [6]}}
+      },
+      "netnames": {
+        "count": {"bits": [3, 4], "attributes": {"init": "%s"}}
+      }
+    }
+  }
+}`
+
+func TestParseYosysJSON(t *testing.T) {
+	n, err := circuit.ParseYosysJSON(strings.NewReader(yosysComb), circuit.YosysOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(n.Inputs) != 2 || n.Inputs[0].Name != "b" || n.Inputs[1].Name != "a" {
+		t.Errorf("Inputs = %v, want ports b then a", n.Inputs)
+	}
+
+	for v := 0; v < 16; v++ {
+		a, b := uint64(v&3), uint64(v>>2)
+		in, err := circuit.FlattenPorts(n.Inputs, map[string][]bool{"a": bitutils.ToBits(a, 2), "b": bitutils.ToBits(b, 2)})
+		if err != nil {
+			t.Fatal(err)
+		}
+		out, err := n.Circuit.EvalPlain(in)
+		if err != nil {
+			t.Fatal(err)
+		}
+		ports, err := circuit.SplitPorts(n.Outputs, out)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		a0, a1, b0 := a&1, a>>1, b&1
+		mux := a0
+		if b0 == 1 {
+			mux = a1
+		}
+		if got, want := bitutils.ConvertU8(ports["y"]), uint8(mux<<1|a0&b0); got != want {
+			t.Errorf("a=%d b=%d: y = %d, want %d", a, b, got, want)
+		}
+		if got, want := bitutils.ConvertU8(ports["z"]), uint8(2|(a0^1)); got != want {
+			t.Errorf("a=%d b=%d: z = %d, want %d", a, b, got, want)
+		}
+	}
+}
+
+func TestYosysUnrolling(t *testing.T) {
+	testCases := []struct {
+		init   string
+		cycles int
+		en     bool
+		want   uint8
+	}{
+		{"00", 0, true, 0},
+		{"00", 3, true, 3},
+		{"00", 5, true, 1},
+		{"00", 3, false, 0},
+		{"10", 3, true, 1},
+		{"10", 2, false, 2},
+	}
+
+	for _, tc := range testCases {
+		src := strings.Replace(yosysCounter, "%s", tc.init, 1)
+		n, err := circuit.ParseYosysJSON(strings.NewReader(src), circuit.YosysOptions{Cycles: tc.cycles})
+		if err != nil {
+			t.Fatal(err)
+		}
+		out, err := n.Circuit.EvalPlain([]bool{tc.en})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := bitutils.ConvertU8(out); got != tc.want {
+			t.Errorf("init %s, %d cycles, en %v: count = %d, want %d", tc.init, tc.cycles, tc.en, got, tc.want)
+		}
+	}
+}
+
+func TestYosysEncrypted(t *testing.T) {
+	sk := key.NewSecretKey()
+	ck := cloudkey.NewCloudKey(sk)
+
+	src := strings.Replace(yosysCounter, "%s", "01", 1)
+	n, err := circuit.ParseYosysJSON(strings.NewReader(src), circuit.YosysOptions{Cycles: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	in, err := circuit.FlattenPorts(n.Inputs, map[string][]*gates.Ciphertext{
+		"en": bitutils.EncryptBits([]bool{true}, params.GetTLWELv0().ALPHA, sk.KeyLv0),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	out, err := circuit.NewExecutor(ck, 2).Run(n.Circuit, in)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ports, err := circuit.SplitPorts(n.Outputs, out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := bitutils.ConvertU8(bitutils.DecryptBits(ports["count"], sk.KeyLv0)); got != 3 {
+		t.Errorf("1 + 2 cycles = %d, want 3", got)
+	}
+}
+
+func TestYosysErrors(t *testing.T) {
+	testCases := []struct {
+		name string
+		src  string
+	}{
+		{"coarse cell", `{"modules": {"m": {"ports": {}, "cells": {"c": {"type": "$add", "connections": {}}}}}}`},
+		{"undriven net", `{"modules": {"m": {"ports": {"y": {"direction": "output", "bits": [7]}}, "cells": {}}}}`},
+		{"no top", `{"modules": {"m1": {"ports": {}}, "m2": {"ports": {}}}}`},
+		{"bad bit", `{"modules": {"m": {"ports": {"y": {"direction": "output", "bits": ["q"]}}}}}`},
+		{"loop", `{"modules": {"m": {"ports": {"y": {"direction": "output", "bits": [2]}}, "cells": {
+			"n1": {"type": "$_NOT_", "connections": {"A": [3], "Y": [2]}},
+			"n2": {"type": "$_NOT_", "connections": {"A": [2], "Y": [3]}}}}}}`},
+	}
+	for _, tc := range testCases {
+		if _, err := circuit.ParseYosysJSON(strings.NewReader(tc.src), circuit.YosysOptions{}); !errors.Is(err, circuit.ErrNetlist) {
+			t.Errorf("%s: got %v, want ErrNetlist", tc.name, err)
+		}
+	}
+
+	n, err := circuit.ParseYosysJSON(strings.NewReader(yosysComb), circuit.YosysOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := circuit.FlattenPorts(n.Inputs, map[string][]bool{"a": {true}, "b": {true, false}}); !errors.Is(err, circuit.ErrPort) {
+		t.Errorf("FlattenPorts with a 1-bit a: got %v, want ErrPort", err)
+	}
+	if _, err := circuit.FlattenPorts(n.Inputs, map[string][]bool{"a": {true, true}, "b": {true, false}, "c": {}}); !errors.Is(err, circuit.ErrPort) {
+		t.Errorf("FlattenPorts with an unknown port: got %v, want ErrPort", err)
+	}
+}
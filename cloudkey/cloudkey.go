@@ -3,6 +3,7 @@ package cloudkey
 import (
 	"sync"
 
+	"github.com/thedonutfactory/go-tfhe/csprng"
 	"github.com/thedonutfactory/go-tfhe/key"
 	"github.com/thedonutfactory/go-tfhe/params"
 	"github.com/thedonutfactory/go-tfhe/poly"
@@ -18,62 +19,80 @@ type CloudKey struct {
 	BlindRotateTestvec  *trlwe.TRLWELv1
 	KeySwitchingKey     []*tlwe.TLWELv0
 	BootstrappingKey    []*trgsw.TRGSWLv1FFT
+	Params              params.Parameters // Parameter set the key was generated for
 }
 
-// NewCloudKey generates a new cloud key from a secret key
+// NewCloudKey generates a new cloud key from a secret key.
+// The cloud key uses the secret key's parameter set. It panics if the
+// parameter set uses block blind rotation and the level 0 key is not block
+// binary (see key.SecretKey.CheckBlockBinary).
 func NewCloudKey(secretKey *key.SecretKey) *CloudKey {
+	if err := secretKey.CheckBlockBinary(); err != nil {
+		panic(err)
+	}
+	p := secretKey.Params
 	return &CloudKey{
-		DecompositionOffset: genDecompositionOffset(),
-		BlindRotateTestvec:  genTestvec(),
-		KeySwitchingKey:     genKeySwitchingKey(secretKey),
-		BootstrappingKey:    genBootstrappingKey(secretKey),
+		DecompositionOffset: genDecompositionOffset(p),
+		BlindRotateTestvec:  genTestvec(p),
+		KeySwitchingKey:     genKeySwitchingKey(p, secretKey),
+		BootstrappingKey:    genBootstrappingKey(p, secretKey),
+		Params:              p,
 	}
 }
 
 // NewCloudKeyNoKSK creates a cloud key without key switching key (for testing)
 func NewCloudKeyNoKSK() *CloudKey {
-	base := 1 << params.GetTRGSWLv1().BASEBIT
-	iksT := params.GetTRGSWLv1().IKS_T
-	n := params.GetTRGSWLv1().N
-	lv0N := params.GetTLWELv0().N
+	p := params.Current()
+	base := 1 << p.TRGSWLv1.BASEBIT
+	iksT := p.TRGSWLv1.IKS_T
+	n := p.TRGSWLv1.N
+	lv0N := p.TLWELv0.N
 
 	ksk := make([]*tlwe.TLWELv0, base*iksT*n)
 	for i := range ksk {
-		ksk[i] = tlwe.NewTLWELv0()
+		ksk[i] = tlwe.NewTLWELv0WithParams(p)
 	}
 
 	polyEval := poly.NewEvaluator(n)
 	bsk := make([]*trgsw.TRGSWLv1FFT, lv0N)
 	for i := range bsk {
-		bsk[i] = trgsw.NewTRGSWLv1FFTDummy(polyEval)
+		bsk[i] = trgsw.NewTRGSWLv1FFTDummyWithParams(p, polyEval)
 	}
 
 	return &CloudKey{
-		DecompositionOffset: genDecompositionOffset(),
-		BlindRotateTestvec:  genTestvec(),
+		DecompositionOffset: genDecompositionOffset(p),
+		BlindRotateTestvec:  genTestvec(p),
 		KeySwitchingKey:     ksk,
 		BootstrappingKey:    bsk,
+		Params:              p,
 	}
 }
 
-// genDecompositionOffset generates the decomposition offset
-func genDecompositionOffset() params.Torus {
+// genDecompositionOffset generates the decomposition offset: Bg/2 at every
+// level, so that the digits are balanced in [-Bg/2, Bg/2), plus half of the
+// last level, so that the bits below L*BGBIT are rounded rather than
+// truncated. Truncation biases every external product by the same amount,
+// which adds up over the blind rotation.
+func genDecompositionOffset(p params.Parameters) params.Torus {
 	var offset params.Torus
-	l := params.GetTRGSWLv1().L
-	bg := params.GetTRGSWLv1().BG
-	bgbit := params.GetTRGSWLv1().BGBIT
+	l := p.TRGSWLv1.L
+	bg := p.TRGSWLv1.BG
+	bgbit := p.TRGSWLv1.BGBIT
 
 	for i := 0; i < l; i++ {
 		offset += params.Torus(bg/2) * params.Torus(1<<(32-((i+1)*int(bgbit))))
 	}
+	if shift := 32 - l*int(bgbit); shift > 0 {
+		offset += params.Torus(1) << (shift - 1)
+	}
 
 	return offset
 }
 
 // genTestvec generates the test vector for blind rotation
-func genTestvec() *trlwe.TRLWELv1 {
-	n := params.GetTRGSWLv1().N
-	testvec := trlwe.NewTRLWELv1()
+func genTestvec(p params.Parameters) *trlwe.TRLWELv1 {
+	n := p.TRGSWLv1.N
+	testvec := trlwe.NewTRLWELv1WithParams(p)
 	bTorus := utils.F64ToTorus(0.125)
 
 	for i := 0; i < n; i++ {
@@ -85,21 +104,23 @@ func genTestvec() *trlwe.TRLWELv1 {
 }
 
 // genKeySwitchingKey generates the key switching key (parallelized)
-func genKeySwitchingKey(secretKey *key.SecretKey) []*tlwe.TLWELv0 {
-	basebit := params.GetTRGSWLv1().BASEBIT
-	iksT := params.GetTRGSWLv1().IKS_T
+func genKeySwitchingKey(prm params.Parameters, secretKey *key.SecretKey) []*tlwe.TLWELv0 {
+	basebit := prm.TRGSWLv1.BASEBIT
+	iksT := prm.TRGSWLv1.IKS_T
 	base := 1 << basebit
-	n := params.GetTRGSWLv1().N
+	n := prm.TRGSWLv1.N
 
 	result := make([]*tlwe.TLWELv0, base*iksT*n)
 	for i := range result {
-		result[i] = tlwe.NewTLWELv0()
+		result[i] = tlwe.NewTLWELv0WithParams(prm)
 	}
 
+	// Fork one randomness source per row up front so that seeded
+	// (deterministic) sources give reproducible keys despite the parallelism
 	var wg sync.WaitGroup
 	for i := 0; i < n; i++ {
 		wg.Add(1)
-		go func(iIdx int) {
+		go func(iIdx int, src csprng.Source) {
 			defer wg.Done()
 			for j := 0; j < iksT; j++ {
 				for k := 0; k < base; k++ {
@@ -109,10 +130,10 @@ func genKeySwitchingKey(secretKey *key.SecretKey) []*tlwe.TLWELv0 {
 					shift := uint((j + 1) * basebit)
 					p := (float64(k) * float64(secretKey.KeyLv1[iIdx])) / float64(uint64(1)<<shift)
 					idx := (base * iksT * iIdx) + (base * j) + k
-					result[idx] = tlwe.NewTLWELv0().EncryptF64(p, params.KSKAlpha(), secretKey.KeyLv0)
+					result[idx].EncryptF64WithSource(p, prm.KSKAlpha(), secretKey.KeyLv0, src)
 				}
 			}
-		}(i)
+		}(i, csprng.Fork())
 	}
 	wg.Wait()
 
@@ -120,24 +141,25 @@ func genKeySwitchingKey(secretKey *key.SecretKey) []*tlwe.TLWELv0 {
 }
 
 // genBootstrappingKey generates the bootstrapping key (parallelized)
-func genBootstrappingKey(secretKey *key.SecretKey) []*trgsw.TRGSWLv1FFT {
-	lv0N := params.GetTLWELv0().N
+func genBootstrappingKey(p params.Parameters, secretKey *key.SecretKey) []*trgsw.TRGSWLv1FFT {
+	lv0N := p.TLWELv0.N
 	result := make([]*trgsw.TRGSWLv1FFT, lv0N)
 
 	var wg sync.WaitGroup
 	for i := 0; i < lv0N; i++ {
 		wg.Add(1)
-		go func(idx int) {
+		go func(idx int, src csprng.Source) {
 			defer wg.Done()
-			polyEval := poly.NewEvaluator(params.GetTRGSWLv1().N)
-			trgswCipher := trgsw.NewTRGSWLv1().EncryptTorus(
+			polyEval := poly.NewEvaluator(p.TRGSWLv1.N)
+			trgswCipher := trgsw.NewTRGSWLv1WithParams(p).EncryptTorusWithSource(
 				secretKey.KeyLv0[idx],
-				params.BSKAlpha(),
+				p.BSKAlpha(),
 				secretKey.KeyLv1,
 				polyEval,
+				src,
 			)
 			result[idx] = trgsw.NewTRGSWLv1FFT(trgswCipher, polyEval)
-		}(i)
+		}(i, csprng.Fork())
 	}
 	wg.Wait()
 
@@ -0,0 +1,189 @@
+package cloudkey
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+
+	"github.com/thedonutfactory/go-tfhe/params"
+	"github.com/thedonutfactory/go-tfhe/poly"
+	"github.com/thedonutfactory/go-tfhe/tlwe"
+	"github.com/thedonutfactory/go-tfhe/trgsw"
+	"github.com/thedonutfactory/go-tfhe/trlwe"
+	"github.com/thedonutfactory/go-tfhe/utils"
+)
+
+// WriteTo writes the cloud key in binary form to w.
+//
+// Layout (little-endian):
+//   - params.Header
+//   - DecompositionOffset (uint32)
+//   - BlindRotateTestvec A and B polynomials
+//   - uint32 count followed by every key switching key TLWELv0
+//   - uint32 count followed by every bootstrapping key TRGSWLv1FFT
+//     (2*L Fourier TRLWE pairs each)
+//
+// Cloud keys are large (tens to hundreds of MB), so wrap w in a
+// bufio.Writer when writing to a file or socket.
+func (ck *CloudKey) WriteTo(w io.Writer) (int64, error) {
+	cw := &countingWriter{w: w}
+
+	if ck.Params == (params.Parameters{}) {
+		return 0, params.ErrNoParams
+	}
+	if _, err := params.NewHeader(params.ObjectCloudKey, ck.Params).WriteTo(cw); err != nil {
+		return cw.n, err
+	}
+	if err := binary.Write(cw, binary.LittleEndian, uint32(ck.DecompositionOffset)); err != nil {
+		return cw.n, err
+	}
+	if _, err := utils.WriteTorusVec(cw, ck.BlindRotateTestvec.A); err != nil {
+		return cw.n, err
+	}
+	if _, err := utils.WriteTorusVec(cw, ck.BlindRotateTestvec.B); err != nil {
+		return cw.n, err
+	}
+
+	if err := binary.Write(cw, binary.LittleEndian, uint32(len(ck.KeySwitchingKey))); err != nil {
+		return cw.n, err
+	}
+	for _, ct := range ck.KeySwitchingKey {
+		if _, err := utils.WriteTorusVec(cw, ct.P); err != nil {
+			return cw.n, err
+		}
+	}
+
+	if err := binary.Write(cw, binary.LittleEndian, uint32(len(ck.BootstrappingKey))); err != nil {
+		return cw.n, err
+	}
+	for _, bk := range ck.BootstrappingKey {
+		for _, t := range bk.TRLWEFFT {
+			if _, err := utils.WriteF64Vec(cw, t.A.Coeffs); err != nil {
+				return cw.n, err
+			}
+			if _, err := utils.WriteF64Vec(cw, t.B.Coeffs); err != nil {
+				return cw.n, err
+			}
+		}
+	}
+
+	return cw.n, nil
+}
+
+// ReadFrom reads a cloud key written by WriteTo from r.
+// The key's parameter set is restored from the header.
+func (ck *CloudKey) ReadFrom(r io.Reader) (int64, error) {
+	cr := &countingReader{r: r}
+
+	var h params.Header
+	if _, err := h.ReadFrom(cr); err != nil {
+		return cr.n, err
+	}
+	if err := h.Check(params.ObjectCloudKey); err != nil {
+		return cr.n, err
+	}
+
+	p := h.Params
+	lv0N := p.TLWELv0.N
+	n := p.TRGSWLv1.N
+	l := p.TRGSWLv1.L
+	kskLen := (1 << p.TRGSWLv1.BASEBIT) * p.TRGSWLv1.IKS_T * n
+
+	var offset uint32
+	if err := binary.Read(cr, binary.LittleEndian, &offset); err != nil {
+		return cr.n, err
+	}
+
+	testvec := trlwe.NewTRLWELv1WithParams(p)
+	if _, err := utils.ReadTorusVec(cr, testvec.A); err != nil {
+		return cr.n, err
+	}
+	if _, err := utils.ReadTorusVec(cr, testvec.B); err != nil {
+		return cr.n, err
+	}
+
+	var count uint32
+	if err := binary.Read(cr, binary.LittleEndian, &count); err != nil {
+		return cr.n, err
+	}
+	if int(count) != kskLen {
+		return cr.n, fmt.Errorf("cloudkey: key switching key has %d entries, expected %d", count, kskLen)
+	}
+	ksk := make([]*tlwe.TLWELv0, kskLen)
+	for i := range ksk {
+		ksk[i] = tlwe.NewTLWELv0WithParams(p)
+		if _, err := utils.ReadTorusVec(cr, ksk[i].P); err != nil {
+			return cr.n, err
+		}
+	}
+
+	if err := binary.Read(cr, binary.LittleEndian, &count); err != nil {
+		return cr.n, err
+	}
+	if int(count) != lv0N {
+		return cr.n, fmt.Errorf("cloudkey: bootstrapping key has %d entries, expected %d", count, lv0N)
+	}
+	bsk := make([]*trgsw.TRGSWLv1FFT, lv0N)
+	for i := range bsk {
+		bk := &trgsw.TRGSWLv1FFT{TRLWEFFT: make([]trgsw.TRLWELv1FFT, 2*l)}
+		for j := range bk.TRLWEFFT {
+			bk.TRLWEFFT[j].A = poly.NewFourierPoly(n)
+			bk.TRLWEFFT[j].B = poly.NewFourierPoly(n)
+			if _, err := utils.ReadF64Vec(cr, bk.TRLWEFFT[j].A.Coeffs); err != nil {
+				return cr.n, err
+			}
+			if _, err := utils.ReadF64Vec(cr, bk.TRLWEFFT[j].B.Coeffs); err != nil {
+				return cr.n, err
+			}
+		}
+		bsk[i] = bk
+	}
+
+	ck.DecompositionOffset = params.Torus(offset)
+	ck.BlindRotateTestvec = testvec
+	ck.KeySwitchingKey = ksk
+	ck.BootstrappingKey = bsk
+	ck.Params = p
+
+	return cr.n, nil
+}
+
+// MarshalBinary implements encoding.BinaryMarshaler
+func (ck *CloudKey) MarshalBinary() ([]byte, error) {
+	var buf bytes.Buffer
+	if _, err := ck.WriteTo(&buf); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// UnmarshalBinary implements encoding.BinaryUnmarshaler
+func (ck *CloudKey) UnmarshalBinary(data []byte) error {
+	_, err := ck.ReadFrom(bytes.NewReader(data))
+	return err
+}
+
+// countingWriter tracks the number of bytes written for io.WriterTo
+type countingWriter struct {
+	w io.Writer
+	n int64
+}
+
+func (cw *countingWriter) Write(p []byte) (int, error) {
+	n, err := cw.w.Write(p)
+	cw.n += int64(n)
+	return n, err
+}
+
+// countingReader tracks the number of bytes read for io.ReaderFrom
+type countingReader struct {
+	r io.Reader
+	n int64
+}
+
+func (cr *countingReader) Read(p []byte) (int, error) {
+	n, err := cr.r.Read(p)
+	cr.n += int64(n)
+	return n, err
+}
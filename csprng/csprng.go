@@ -0,0 +1,201 @@
+// Package csprng provides the randomness used for key generation and encryption.
+//
+// All secret keys, LWE masks and Gaussian noise in go-tfhe are sampled from a
+// Source. The default Source reads directly from crypto/rand. For faster key
+// generation an AES-256-CTR keystream seeded from crypto/rand can be selected
+// instead, and for reproducible tests a deterministic, explicitly seeded
+// Source is available.
+//
+// # Example
+//
+//	// Faster key generation (still cryptographically secure)
+//	csprng.SetDefault(csprng.NewCTRSource())
+//
+//	// Reproducible tests (NEVER use in production)
+//	csprng.SetDefault(csprng.NewSeededSource([]byte("test seed")))
+//	defer csprng.SetDefault(csprng.NewCryptoSource())
+package csprng
+
+import (
+	"bufio"
+	"crypto/aes"
+	"crypto/cipher"
+	cryptorand "crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
+	"io"
+	"math/rand"
+	"sync"
+)
+
+// Source is a pluggable source of cryptographically secure random bits.
+//
+// A Source is not safe for concurrent use. Use Fork to obtain an independent
+// Source for each goroutine.
+type Source interface {
+	io.Reader
+
+	// Uint64 returns 64 uniformly random bits
+	Uint64() uint64
+
+	// Fork returns a new Source that is independent of the receiver.
+	// For deterministic sources the child is derived from the parent's
+	// stream, so the same sequence of Fork calls yields the same children.
+	Fork() Source
+}
+
+// cryptoSource reads from crypto/rand through a small buffer
+type cryptoSource struct {
+	r *bufio.Reader
+}
+
+// NewCryptoSource returns a Source backed by crypto/rand.
+// This is the default Source.
+func NewCryptoSource() Source {
+	return &cryptoSource{r: bufio.NewReaderSize(cryptorand.Reader, 4096)}
+}
+
+func (s *cryptoSource) Read(p []byte) (int, error) {
+	return io.ReadFull(s.r, p)
+}
+
+func (s *cryptoSource) Uint64() uint64 {
+	var buf [8]byte
+	if _, err := io.ReadFull(s.r, buf[:]); err != nil {
+		panic("csprng: crypto/rand failed: " + err.Error())
+	}
+	return binary.LittleEndian.Uint64(buf[:])
+}
+
+func (s *cryptoSource) Fork() Source {
+	return NewCryptoSource()
+}
+
+// ctrSource is an AES-256 keystream generator (CTR mode over a zero plaintext)
+type ctrSource struct {
+	stream cipher.Stream
+	buf    [4096]byte
+	pos    int
+}
+
+// NewCTRSource returns a fast Source producing an AES-256-CTR keystream
+// keyed with 32 bytes from crypto/rand.
+func NewCTRSource() Source {
+	var key [32]byte
+	if _, err := io.ReadFull(cryptorand.Reader, key[:]); err != nil {
+		panic("csprng: crypto/rand failed: " + err.Error())
+	}
+	return newCTRSource(key)
+}
+
+// NewSeededSource returns a deterministic Source derived from seed.
+// Two sources created from the same seed produce identical output.
+//
+// This is intended for reproducible tests only. Keys generated from a
+// known seed provide no security.
+func NewSeededSource(seed []byte) Source {
+	return newCTRSource(sha256.Sum256(seed))
+}
+
+func newCTRSource(key [32]byte) *ctrSource {
+	block, err := aes.NewCipher(key[:])
+	if err != nil {
+		panic("csprng: " + err.Error())
+	}
+	var iv [aes.BlockSize]byte
+	s := &ctrSource{stream: cipher.NewCTR(block, iv[:])}
+	s.refill()
+	return s
+}
+
+// refill generates the next block of keystream into the buffer
+func (s *ctrSource) refill() {
+	for i := range s.buf {
+		s.buf[i] = 0
+	}
+	s.stream.XORKeyStream(s.buf[:], s.buf[:])
+	s.pos = 0
+}
+
+func (s *ctrSource) Read(p []byte) (int, error) {
+	n := 0
+	for n < len(p) {
+		if s.pos == len(s.buf) {
+			s.refill()
+		}
+		c := copy(p[n:], s.buf[s.pos:])
+		s.pos += c
+		n += c
+	}
+	return n, nil
+}
+
+func (s *ctrSource) Uint64() uint64 {
+	if len(s.buf)-s.pos < 8 {
+		s.refill()
+	}
+	v := binary.LittleEndian.Uint64(s.buf[s.pos:])
+	s.pos += 8
+	return v
+}
+
+func (s *ctrSource) Fork() Source {
+	var key [32]byte
+	s.Read(key[:])
+	return newCTRSource(key)
+}
+
+// Rand wraps a Source as a *rand.Rand so it can be used with the
+// samplers in the utils package (Uint32, Intn, NormFloat64, ...).
+func Rand(src Source) *rand.Rand {
+	return rand.New(source64{src})
+}
+
+// source64 adapts a Source to math/rand.Source64
+type source64 struct {
+	src Source
+}
+
+func (s source64) Int63() int64 {
+	return int64(s.src.Uint64() >> 1)
+}
+
+func (s source64) Uint64() uint64 {
+	return s.src.Uint64()
+}
+
+func (s source64) Seed(int64) {
+	panic("csprng: Seed is not supported, use NewSeededSource")
+}
+
+var (
+	defaultMu     sync.Mutex
+	defaultSource Source = NewCryptoSource()
+)
+
+// SetDefault replaces the package-wide default Source
+func SetDefault(src Source) {
+	defaultMu.Lock()
+	defer defaultMu.Unlock()
+	defaultSource = src
+}
+
+// SetSeed switches the default Source to a deterministic source derived
+// from seed. Intended for reproducible tests only.
+func SetSeed(seed []byte) {
+	SetDefault(NewSeededSource(seed))
+}
+
+// Fork returns a new Source forked from the default Source.
+// It is safe for concurrent use.
+func Fork() Source {
+	defaultMu.Lock()
+	defer defaultMu.Unlock()
+	return defaultSource.Fork()
+}
+
+// New returns a *rand.Rand drawing from a fresh fork of the default Source.
+// It is safe for concurrent use; the returned *rand.Rand is not.
+func New() *rand.Rand {
+	return Rand(Fork())
+}
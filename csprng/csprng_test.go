@@ -0,0 +1,94 @@
+package csprng_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/thedonutfactory/go-tfhe/csprng"
+	"github.com/thedonutfactory/go-tfhe/key"
+	"github.com/thedonutfactory/go-tfhe/params"
+	"github.com/thedonutfactory/go-tfhe/tlwe"
+)
+
+func TestSeededSourceDeterministic(t *testing.T) {
+	a := csprng.NewSeededSource([]byte("seed"))
+	b := csprng.NewSeededSource([]byte("seed"))
+	c := csprng.NewSeededSource([]byte("other seed"))
+
+	for i := 0; i < 1000; i++ {
+		va, vb, vc := a.Uint64(), b.Uint64(), c.Uint64()
+		if va != vb {
+			t.Fatalf("same seed diverged at %d: %x != %x", i, va, vb)
+		}
+		if va == vc {
+			t.Fatalf("different seeds produced the same value at %d", i)
+		}
+	}
+
+	// Forks are derived from the parent stream and therefore also reproducible
+	fa, fb := a.Fork(), b.Fork()
+	if fa.Uint64() != fb.Uint64() {
+		t.Error("forks of identical sources diverged")
+	}
+	if a.Uint64() == fa.Uint64() {
+		t.Error("fork reproduces its parent stream")
+	}
+}
+
+func TestSourcesRead(t *testing.T) {
+	sources := map[string]csprng.Source{
+		"crypto": csprng.NewCryptoSource(),
+		"ctr":    csprng.NewCTRSource(),
+		"seeded": csprng.NewSeededSource(nil),
+	}
+
+	for name, src := range sources {
+		t.Run(name, func(t *testing.T) {
+			// Read across the internal buffer boundary
+			buf := make([]byte, 10000)
+			if n, err := src.Read(buf); n != len(buf) || err != nil {
+				t.Fatalf("Read returned (%d, %v)", n, err)
+			}
+			if bytes.Count(buf, []byte{0}) > len(buf)/16 {
+				t.Errorf("output looks biased: %d zero bytes", bytes.Count(buf, []byte{0}))
+			}
+
+			// Uniform bits: the mean of many samples should be close to 1/2
+			rng := csprng.Rand(src)
+			ones := 0
+			const trials = 10000
+			for i := 0; i < trials; i++ {
+				ones += rng.Intn(2)
+			}
+			if ones < trials*45/100 || ones > trials*55/100 {
+				t.Errorf("Intn(2) returned 1 %d/%d times", ones, trials)
+			}
+		})
+	}
+}
+
+func TestSetSeedReproducibleKeys(t *testing.T) {
+	defer csprng.SetDefault(csprng.NewCryptoSource())
+
+	csprng.SetSeed([]byte("reproducible"))
+	sk1 := key.NewSecretKey()
+	ct1 := tlwe.NewTLWELv0().EncryptBool(true, params.GetTLWELv0().ALPHA, sk1.KeyLv0)
+
+	csprng.SetSeed([]byte("reproducible"))
+	sk2 := key.NewSecretKey()
+	ct2 := tlwe.NewTLWELv0().EncryptBool(true, params.GetTLWELv0().ALPHA, sk2.KeyLv0)
+
+	for i := range sk1.KeyLv0 {
+		if sk1.KeyLv0[i] != sk2.KeyLv0[i] {
+			t.Fatalf("KeyLv0[%d] differs between seeded runs", i)
+		}
+	}
+	for i := range ct1.P {
+		if ct1.P[i] != ct2.P[i] {
+			t.Fatalf("ciphertext coefficient %d differs between seeded runs", i)
+		}
+	}
+	if !ct1.DecryptBool(sk1.KeyLv0) {
+		t.Error("seeded encryption does not decrypt correctly")
+	}
+}
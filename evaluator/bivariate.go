@@ -0,0 +1,90 @@
+package evaluator
+
+import (
+	"errors"
+	"fmt"
+	"math"
+
+	"github.com/thedonutfactory/go-tfhe/lut"
+	"github.com/thedonutfactory/go-tfhe/params"
+	"github.com/thedonutfactory/go-tfhe/tlwe"
+	"github.com/thedonutfactory/go-tfhe/trgsw"
+)
+
+// Errors returned when two inputs cannot be packed into one bootstrap
+var (
+	ErrBivariateOverflow = errors.New("evaluator: packed bivariate input overflows the message space")
+	ErrBivariateNoise    = errors.New("evaluator: packed bivariate input exceeds the noise budget")
+)
+
+// bivariateNoiseBound is the number of standard deviations of the packed
+// input's noise that must fit in half a message interval
+const bivariateNoiseBound = 4.0
+
+// CheckBivariate reports whether two ciphertexts of parameter set p holding
+// messages in [0, messageModulus), encrypted with EncryptLWEMessage under
+// plaintextModulus, can be packed as x*messageModulus + y and bootstrapped.
+//
+// The packed value must fit in the plaintext space (messageModulus^2 <=
+// plaintextModulus), and the noise of the packed input (the noise of a
+// key-switched ciphertext scaled by messageModulus, plus the modulus switch
+// of the bootstrap) must stay well inside half a message interval.
+func CheckBivariate(p params.Parameters, messageModulus, plaintextModulus int) error {
+	if messageModulus < 1 || messageModulus*messageModulus > plaintextModulus {
+		return fmt.Errorf("%w: %d^2 > plaintext modulus %d", ErrBivariateOverflow, messageModulus, plaintextModulus)
+	}
+	if plaintextModulus > p.LookUpTableSize() {
+		return fmt.Errorf("%w: plaintext modulus %d > lookup table size %d", ErrBivariateOverflow, plaintextModulus, p.LookUpTableSize())
+	}
+
+	m := float64(messageModulus)
+	stdDev := math.Sqrt((m*m+1)*p.KeySwitchVariance() + p.ModSwitchVariance())
+	if budget := 1 / float64(4*plaintextModulus); bivariateNoiseBound*stdDev > budget {
+		return fmt.Errorf("%w: standard deviation %.3g, budget %.3g", ErrBivariateNoise, stdDev, budget/bivariateNoiseBound)
+	}
+	return nil
+}
+
+// PackBivariate returns ctX*messageModulus + ctY, the input of a bivariate bootstrap
+func PackBivariate(ctX, ctY *tlwe.TLWELv0, messageModulus int) *tlwe.TLWELv0 {
+	return ctY.AddMul(ctX, params.Torus(messageModulus))
+}
+
+// BootstrapBivariate evaluates f(x, y) with one programmable bootstrap.
+//
+// ctX and ctY encrypt x and y in [0, messageModulus) with EncryptLWEMessage
+// under plaintextModulus; the result is encrypted the same way. The inputs
+// are packed as x*messageModulus + y and bootstrapped with a lookup table
+// over the whole plaintext space. It returns ErrBivariateOverflow or
+// ErrBivariateNoise if the packed input would not decrypt reliably (see
+// CheckBivariate).
+func (e *Evaluator) BootstrapBivariate(
+	ctX, ctY *tlwe.TLWELv0,
+	f func(x, y int) int,
+	messageModulus, plaintextModulus int,
+	bsk []*trgsw.TRGSWLv1FFT,
+	ksk []*tlwe.TLWELv0,
+	decompositionOffset params.Torus,
+) (*tlwe.TLWELv0, error) {
+	if err := CheckBivariate(e.Params, messageModulus, plaintextModulus); err != nil {
+		return nil, err
+	}
+
+	generator := lut.NewGeneratorWithParams(e.Params, plaintextModulus)
+	lookupTable := generator.GenBivariateLookUpTable(f, messageModulus)
+	return e.BootstrapBivariateLUT(ctX, ctY, lookupTable, messageModulus, bsk, ksk, decompositionOffset), nil
+}
+
+// BootstrapBivariateLUT is BootstrapBivariate with a table from
+// lut.Generator.GenBivariateLookUpTable. It does not repeat the checks;
+// call CheckBivariate once when the table is created.
+func (e *Evaluator) BootstrapBivariateLUT(
+	ctX, ctY *tlwe.TLWELv0,
+	lookupTable *lut.LookUpTable,
+	messageModulus int,
+	bsk []*trgsw.TRGSWLv1FFT,
+	ksk []*tlwe.TLWELv0,
+	decompositionOffset params.Torus,
+) *tlwe.TLWELv0 {
+	return e.BootstrapLUT(PackBivariate(ctX, ctY, messageModulus), lookupTable, bsk, ksk, decompositionOffset)
+}
@@ -0,0 +1,63 @@
+package evaluator
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/thedonutfactory/go-tfhe/cloudkey"
+	"github.com/thedonutfactory/go-tfhe/key"
+	"github.com/thedonutfactory/go-tfhe/params"
+	"github.com/thedonutfactory/go-tfhe/tlwe"
+)
+
+func TestBootstrapBivariate(t *testing.T) {
+	p := params.GetParameters(params.SecurityUint4)
+	secretKey := key.NewSecretKeyWithParams(p)
+	cloudKey := cloudkey.NewCloudKey(secretKey)
+	eval := NewEvaluatorWithParams(p)
+
+	// Two 2-bit messages packed into the 4-bit plaintext space
+	const messageModulus, plaintextModulus = 4, 16
+	f := func(x, y int) int { return x*y + 1 }
+
+	for x := 0; x < messageModulus; x++ {
+		for y := 0; y < messageModulus; y++ {
+			ctX := tlwe.NewTLWELv0WithParams(p).EncryptLWEMessage(x, plaintextModulus, p.TLWELv0.ALPHA, secretKey.KeyLv0)
+			ctY := tlwe.NewTLWELv0WithParams(p).EncryptLWEMessage(y, plaintextModulus, p.TLWELv0.ALPHA, secretKey.KeyLv0)
+
+			result, err := eval.BootstrapBivariate(ctX, ctY, f, messageModulus, plaintextModulus,
+				cloudKey.BootstrappingKey, cloudKey.KeySwitchingKey, cloudKey.DecompositionOffset)
+			if err != nil {
+				t.Fatalf("BootstrapBivariate: %v", err)
+			}
+			if got := result.DecryptLWEMessage(plaintextModulus, secretKey.KeyLv0); got != f(x, y) {
+				t.Errorf("f(%d, %d) = %d, want %d", x, y, got, f(x, y))
+			}
+		}
+	}
+}
+
+func TestCheckBivariate(t *testing.T) {
+	testCases := []struct {
+		name             string
+		level            params.SecurityLevel
+		messageModulus   int
+		plaintextModulus int
+		want             error
+	}{
+		{"Uint4 2+2 bits", params.SecurityUint4, 4, 16, nil},
+		{"Uint8 4+4 bits", params.SecurityUint8, 16, 256, nil},
+		{"carry space too small", params.SecurityUint4, 8, 16, ErrBivariateOverflow},
+		{"plaintext larger than the lookup table", params.Security80Bit, 64, 4096, ErrBivariateOverflow},
+		{"noise scaled past the budget", params.Security128Bit, 8, 64, ErrBivariateNoise},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := CheckBivariate(params.GetParameters(tc.level), tc.messageModulus, tc.plaintextModulus)
+			if !errors.Is(err, tc.want) {
+				t.Errorf("CheckBivariate(%d, %d) = %v, want %v", tc.messageModulus, tc.plaintextModulus, err, tc.want)
+			}
+		})
+	}
+}
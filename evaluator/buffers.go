@@ -51,7 +51,9 @@ type BufferPool struct {
 	// Bootstrap buffers (full bootstrap = blind rotate + key switch)
 	Bootstrap struct {
 		ExtractedLWE *tlwe.TLWELv1 // After sample extraction
+		MuxLWE       *tlwe.TLWELv1 // Second level 1 sample summed by MUXAssign
 		KeySwitched  *tlwe.TLWELv0 // After key switching
+		Rounded      *tlwe.TLWELv0 // Input rounded for a many-LUT bootstrap
 	}
 
 	// === Gate Operation Buffers ===
@@ -67,26 +69,34 @@ type BufferPool struct {
 	}
 
 	// === Block Blind Rotation Buffers (for 3-4x speedup) ===
-	// Only allocated if params.UseBlockBlindRotation() == true
+	// Only allocated if the parameter set uses block blind rotation
 
 	BlockRotation *BlockRotationBuffers
+
+	// === Extended Lookup Table Buffers ===
+	// Only allocated if the parameter set uses extended lookup tables (polyExtendFactor > 1)
+
+	ExtendedRotation *ExtendedRotationBuffers
+
+	// parameters is the parameter set the buffers were sized for
+	parameters params.Parameters
 }
 
 // BlockRotationBuffers contains buffers for block-based blind rotation algorithm
 // This provides 3-4x speedup by processing multiple LWE coefficients together
 type BlockRotationBuffers struct {
-	// Decomposed accumulator in Fourier domain
-	// [blockSize][glweRank+1][level]
-	AccFourierDecomposed [][][]poly.FourierPoly
+	// Decomposed accumulator in Fourier domain, shared by every key in a block
+	// [glweRank+1][level]
+	AccFourierDecomposed [][]poly.FourierPoly
 
-	// Block accumulator in Fourier domain [blockSize]
+	// Per-key external product results in Fourier domain [blockSize]
 	BlockFourierAcc []struct {
 		A poly.FourierPoly
 		B poly.FourierPoly
 	}
 
-	// Intermediate Fourier accumulator [blockSize]
-	FourierAcc []struct {
+	// Sum of the rotated block results in Fourier domain
+	FourierAcc struct {
 		A poly.FourierPoly
 		B poly.FourierPoly
 	}
@@ -95,76 +105,110 @@ type BlockRotationBuffers struct {
 	FourierMono poly.FourierPoly
 }
 
-// NewBufferPool creates a new centralized buffer pool for the given polynomial size.
+// ExtendedRotationBuffers contains buffers for blind rotation of extended lookup tables.
+// Every slice has one entry per lookup table polynomial (polyExtendFactor).
+type ExtendedRotationBuffers struct {
+	// One TRLWE accumulator per lookup table polynomial
+	Accumulators []*trlwe.TRLWELv1
+
+	// Decomposed accumulators in Fourier domain
+	// [polyExtendFactor][glweRank+1][level]
+	AccFourierDecomposed [][][]poly.FourierPoly
+
+	// External product results for the current key [polyExtendFactor]
+	BlockFourierAcc []struct {
+		A poly.FourierPoly
+		B poly.FourierPoly
+	}
+
+	// Sums of the rotated block results in Fourier domain [polyExtendFactor]
+	FourierAcc []struct {
+		A poly.FourierPoly
+		B poly.FourierPoly
+	}
+
+	// Fourier monomials X^q and X^(q+1) for the current key
+	FourierMono     poly.FourierPoly
+	FourierMonoNext poly.FourierPoly
+}
+
+// NewBufferPool creates a new centralized buffer pool for the given parameter set.
 // This allocates all buffers once during initialization (~250 KB total).
 //
 // Parameters:
 //
-//	n: Polynomial degree (typically 1024 for standard TFHE parameters)
+//	p: Parameter set (polynomial degree is typically 1024 for standard TFHE parameters)
 //
 // Memory allocation:
 //   - Polynomial buffers: ~200 KB (managed by poly.BufferManager)
 //   - Ciphertext buffers: ~50 KB (TRLWE, LWE structures)
 //   - Block rotation: ~30 KB (if enabled)
-func NewBufferPool(n int) *BufferPool {
+func NewBufferPool(p params.Parameters) *BufferPool {
+	n := p.TRGSWLv1.N
 	bp := &BufferPool{
-		PolyBuffers: poly.NewBufferManager(n),
+		PolyBuffers: poly.NewBufferManager(n, p.TRGSWLv1.L),
+		parameters:  p,
 	}
 
 	// Initialize external product buffers
 	bp.ExternalProduct.FourierA = poly.NewFourierPoly(n)
 	bp.ExternalProduct.FourierB = poly.NewFourierPoly(n)
-	bp.ExternalProduct.Result = trlwe.NewTRLWELv1()
+	bp.ExternalProduct.Result = trlwe.NewTRLWELv1WithParams(p)
 
 	// Initialize CMUX buffers
-	bp.CMUX.Temp = trlwe.NewTRLWELv1()
+	bp.CMUX.Temp = trlwe.NewTRLWELv1WithParams(p)
 
 	// Initialize blind rotation buffers
-	bp.BlindRotation.Accumulator1 = trlwe.NewTRLWELv1()
-	bp.BlindRotation.Accumulator2 = trlwe.NewTRLWELv1()
-	bp.BlindRotation.Rotated = trlwe.NewTRLWELv1()
+	bp.BlindRotation.Accumulator1 = trlwe.NewTRLWELv1WithParams(p)
+	bp.BlindRotation.Accumulator2 = trlwe.NewTRLWELv1WithParams(p)
+	bp.BlindRotation.Rotated = trlwe.NewTRLWELv1WithParams(p)
 
 	// Initialize bootstrap buffers
-	bp.Bootstrap.ExtractedLWE = tlwe.NewTLWELv1()
-	bp.Bootstrap.KeySwitched = tlwe.NewTLWELv0()
+	bp.Bootstrap.ExtractedLWE = tlwe.NewTLWELv1WithParams(p)
+	bp.Bootstrap.MuxLWE = tlwe.NewTLWELv1WithParams(p)
+	bp.Bootstrap.KeySwitched = tlwe.NewTLWELv0WithParams(p)
+	bp.Bootstrap.Rounded = tlwe.NewTLWELv0WithParams(p)
 
 	// Initialize gate preparation buffer
-	bp.GatePrep = tlwe.NewTLWELv0()
+	bp.GatePrep = tlwe.NewTLWELv0WithParams(p)
 
 	// Initialize result pool
 	for i := 0; i < 4; i++ {
-		bp.ResultPool.Buffers[i] = tlwe.NewTLWELv0()
+		bp.ResultPool.Buffers[i] = tlwe.NewTLWELv0WithParams(p)
 	}
 	bp.ResultPool.Index = 0
 
 	// Initialize block rotation buffers if enabled
-	if params.UseBlockBlindRotation() {
-		bp.BlockRotation = newBlockRotationBuffers(n)
+	if p.UseBlockBlindRotation() {
+		bp.BlockRotation = newBlockRotationBuffers(p)
+	}
+
+	// Initialize extended lookup table buffers if needed
+	if p.PolyExtendFactor() > 1 {
+		bp.ExtendedRotation = newExtendedRotationBuffers(p)
 	}
 
 	return bp
 }
 
 // newBlockRotationBuffers creates buffers for block-based blind rotation
-func newBlockRotationBuffers(n int) *BlockRotationBuffers {
-	blockSize := params.GetTRGSWLv1().BlockSize
+func newBlockRotationBuffers(p params.Parameters) *BlockRotationBuffers {
+	n := p.TRGSWLv1.N
+	blockSize := p.TRGSWLv1.BlockSize
 	if blockSize < 1 {
 		blockSize = 1
 	}
 	glweRank := 1 // Fixed for our parameters
-	level := params.GetTRGSWLv1().L
+	level := p.TRGSWLv1.L
 
 	brb := &BlockRotationBuffers{}
 
-	// Initialize AccFourierDecomposed[blockSize][glweRank+1][level]
-	brb.AccFourierDecomposed = make([][][]poly.FourierPoly, blockSize)
-	for i := 0; i < blockSize; i++ {
-		brb.AccFourierDecomposed[i] = make([][]poly.FourierPoly, glweRank+1)
-		for j := 0; j < glweRank+1; j++ {
-			brb.AccFourierDecomposed[i][j] = make([]poly.FourierPoly, level)
-			for k := 0; k < level; k++ {
-				brb.AccFourierDecomposed[i][j][k] = poly.NewFourierPoly(n)
-			}
+	// Initialize AccFourierDecomposed[glweRank+1][level]
+	brb.AccFourierDecomposed = make([][]poly.FourierPoly, glweRank+1)
+	for j := 0; j < glweRank+1; j++ {
+		brb.AccFourierDecomposed[j] = make([]poly.FourierPoly, level)
+		for k := 0; k < level; k++ {
+			brb.AccFourierDecomposed[j][k] = poly.NewFourierPoly(n)
 		}
 	}
 
@@ -178,15 +222,9 @@ func newBlockRotationBuffers(n int) *BlockRotationBuffers {
 		brb.BlockFourierAcc[i].B = poly.NewFourierPoly(n)
 	}
 
-	// Initialize FourierAcc[blockSize]
-	brb.FourierAcc = make([]struct {
-		A poly.FourierPoly
-		B poly.FourierPoly
-	}, blockSize)
-	for i := 0; i < blockSize; i++ {
-		brb.FourierAcc[i].A = poly.NewFourierPoly(n)
-		brb.FourierAcc[i].B = poly.NewFourierPoly(n)
-	}
+	// Initialize FourierAcc
+	brb.FourierAcc.A = poly.NewFourierPoly(n)
+	brb.FourierAcc.B = poly.NewFourierPoly(n)
 
 	// Initialize Fourier monomial
 	brb.FourierMono = poly.NewFourierPoly(n)
@@ -194,6 +232,48 @@ func newBlockRotationBuffers(n int) *BlockRotationBuffers {
 	return brb
 }
 
+// newExtendedRotationBuffers creates buffers for extended lookup table blind rotation
+func newExtendedRotationBuffers(p params.Parameters) *ExtendedRotationBuffers {
+	n := p.TRGSWLv1.N
+	polyExtendFactor := p.PolyExtendFactor()
+	glweRank := 1 // Fixed for our parameters
+	level := p.TRGSWLv1.L
+
+	erb := &ExtendedRotationBuffers{
+		Accumulators:         make([]*trlwe.TRLWELv1, polyExtendFactor),
+		AccFourierDecomposed: make([][][]poly.FourierPoly, polyExtendFactor),
+		BlockFourierAcc: make([]struct {
+			A poly.FourierPoly
+			B poly.FourierPoly
+		}, polyExtendFactor),
+		FourierAcc: make([]struct {
+			A poly.FourierPoly
+			B poly.FourierPoly
+		}, polyExtendFactor),
+		FourierMono:     poly.NewFourierPoly(n),
+		FourierMonoNext: poly.NewFourierPoly(n),
+	}
+
+	for i := 0; i < polyExtendFactor; i++ {
+		erb.Accumulators[i] = trlwe.NewTRLWELv1WithParams(p)
+
+		erb.AccFourierDecomposed[i] = make([][]poly.FourierPoly, glweRank+1)
+		for j := 0; j < glweRank+1; j++ {
+			erb.AccFourierDecomposed[i][j] = make([]poly.FourierPoly, level)
+			for k := 0; k < level; k++ {
+				erb.AccFourierDecomposed[i][j][k] = poly.NewFourierPoly(n)
+			}
+		}
+
+		erb.BlockFourierAcc[i].A = poly.NewFourierPoly(n)
+		erb.BlockFourierAcc[i].B = poly.NewFourierPoly(n)
+		erb.FourierAcc[i].A = poly.NewFourierPoly(n)
+		erb.FourierAcc[i].B = poly.NewFourierPoly(n)
+	}
+
+	return erb
+}
+
 // GetNextResult returns the next available result buffer from the round-robin pool.
 // This allows operations to return results without allocation.
 // The buffer is valid until 4 more operations are performed.
@@ -212,7 +292,7 @@ func (bp *BufferPool) Reset() {
 
 // MemoryUsage returns the approximate memory usage in bytes
 func (bp *BufferPool) MemoryUsage() int {
-	n := params.GetTRGSWLv1().N
+	n := bp.parameters.TRGSWLv1.N
 
 	// Polynomial buffers (managed by poly.BufferManager)
 	polyMem := bp.PolyBuffers.MemoryUsage()
@@ -222,19 +302,32 @@ func (bp *BufferPool) MemoryUsage() int {
 	tlweSize := (n + 1) * 4 // (N+1) elements * 4 bytes
 
 	ciphertextMem := trlweSize*5 + // 5 TRLWE buffers
-		tlweSize*5 + // 5 LWE buffers
+		tlweSize*7 + // 7 LWE buffers
 		2*n*8 // 2 FourierPoly in ExternalProduct
 
 	// Block rotation buffers (if enabled)
 	blockMem := 0
 	if bp.BlockRotation != nil {
-		blockSize := params.GetTRGSWLv1().BlockSize
-		level := params.GetTRGSWLv1().L
+		blockSize := bp.parameters.TRGSWLv1.BlockSize
+		level := bp.parameters.TRGSWLv1.L
+		glweRank := 1
+		blockMem = (glweRank + 1) * level * n * 8 // AccFourierDecomposed
+		blockMem += blockSize * 2 * n * 8         // BlockFourierAcc
+		blockMem += 2 * n * 8                     // FourierAcc
+		blockMem += n * 8                         // FourierMono
+	}
+
+	// Extended lookup table buffers (if enabled)
+	extendedMem := 0
+	if bp.ExtendedRotation != nil {
+		polyExtendFactor := bp.parameters.PolyExtendFactor()
+		level := bp.parameters.TRGSWLv1.L
 		glweRank := 1
-		blockMem = blockSize * (glweRank + 1) * level * n * 8 * 2 // AccFourierDecomposed
-		blockMem += blockSize * 2 * n * 8 * 2                     // BlockFourierAcc + FourierAcc
-		blockMem += n * 8 * 2                                     // FourierMono
+		extendedMem = polyExtendFactor * trlweSize                       // Accumulators
+		extendedMem += polyExtendFactor * (glweRank + 1) * level * n * 8 // AccFourierDecomposed
+		extendedMem += polyExtendFactor * 2 * 2 * n * 8                  // BlockFourierAcc, FourierAcc
+		extendedMem += 2 * n * 8                                         // FourierMono, FourierMonoNext
 	}
 
-	return polyMem + ciphertextMem + blockMem
+	return polyMem + ciphertextMem + blockMem + extendedMem
 }
@@ -21,16 +21,31 @@ type Evaluator struct {
 
 	// Centralized buffer pool for all operations
 	Buffers *BufferPool
+
+	// Params is the parameter set the evaluator operates on
+	Params params.Parameters
 }
 
-// NewEvaluator creates a new zero-allocation evaluator
+// NewEvaluator creates a new zero-allocation evaluator for the current security level.
+// n must be the polynomial degree of that level.
 func NewEvaluator(n int) *Evaluator {
-	l := params.GetTRGSWLv1().L
+	p := params.Current()
+	if n != p.TRGSWLv1.N {
+		panic("evaluator: polynomial degree does not match the current parameter set")
+	}
+	return NewEvaluatorWithParams(p)
+}
+
+// NewEvaluatorWithParams creates a new zero-allocation evaluator for parameter set p
+func NewEvaluatorWithParams(p params.Parameters) *Evaluator {
+	n := p.TRGSWLv1.N
+	l := p.TRGSWLv1.L
 
 	return &Evaluator{
 		PolyEvaluator: poly.NewEvaluator(n),
 		Decomposer:    poly.NewDecomposer(n, l*2), // 2*L levels for A and B
-		Buffers:       NewBufferPool(n),
+		Buffers:       NewBufferPool(p),
+		Params:        p,
 	}
 }
 
@@ -40,16 +55,17 @@ func NewEvaluator(n int) *Evaluator {
 func (e *Evaluator) ShallowCopy() *Evaluator {
 	return &Evaluator{
 		PolyEvaluator: e.PolyEvaluator.ShallowCopy(),
-		Decomposer:    poly.NewDecomposer(e.PolyEvaluator.Degree(), len(e.Decomposer.GetPolyDecomposedBuffer(1))),
-		Buffers:       NewBufferPool(e.PolyEvaluator.Degree()),
+		Decomposer:    poly.NewDecomposer(e.Params.TRGSWLv1.N, e.Params.TRGSWLv1.L*2),
+		Buffers:       NewBufferPool(e.Params),
+		Params:        e.Params,
 	}
 }
 
 // ExternalProductAssign computes external product and writes to ctOut
 // This is the zero-allocation version following tfhe-go exactly
 func (e *Evaluator) ExternalProductAssign(ctFourierGGSW *trgsw.TRGSWLv1FFT, ctIn *trlwe.TRLWELv1, decompositionOffset params.Torus, ctOut *trlwe.TRLWELv1) {
-	l := params.GetTRGSWLv1().L
-	bgbit := params.GetTRGSWLv1().BGBIT
+	l := e.Params.TRGSWLv1.L
+	bgbit := e.Params.TRGSWLv1.BGBIT
 
 	// Decompose ctIn into pre-allocated buffers
 	polyDecomposed := e.Decomposer.GetPolyDecomposedBuffer(l * 2)
@@ -83,7 +99,7 @@ func (e *Evaluator) ExternalProductAssign(ctFourierGGSW *trgsw.TRGSWLv1FFT, ctIn
 // CMuxAssign computes ctOut = ct0 + ctCond * (ct1 - ct0)
 // Following tfhe-go's pattern exactly
 func (e *Evaluator) CMuxAssign(ctCond *trgsw.TRGSWLv1FFT, ct0, ct1 *trlwe.TRLWELv1, decompositionOffset params.Torus, ctOut *trlwe.TRLWELv1) {
-	n := params.GetTRGSWLv1().N
+	n := e.Params.TRGSWLv1.N
 
 	// First copy ct0 to output
 	copy(ctOut.A, ct0.A)
@@ -106,11 +122,23 @@ func (e *Evaluator) CMuxAssign(ctCond *trgsw.TRGSWLv1FFT, ct0, ct1 *trlwe.TRLWEL
 }
 
 // BlindRotateAssign performs blind rotation and writes to ctOut
-// Zero-allocation version following tfhe-go
+// Zero-allocation version following tfhe-go.
+//
+// If the parameter set has BlockSize > 1 the block algorithm is used;
+// otherwise this falls back to one CMux per LWE coefficient.
 func (e *Evaluator) BlindRotateAssign(ctIn *tlwe.TLWELv0, testvec *trlwe.TRLWELv1, bsk []*trgsw.TRGSWLv1FFT, decompositionOffset params.Torus, ctOut *trlwe.TRLWELv1) {
-	n := params.GetTRGSWLv1().N
-	nBit := params.GetTRGSWLv1().NBIT
-	tlweLv0N := params.GetTLWELv0().N
+	if e.Buffers.BlockRotation != nil {
+		e.blindRotateBlockAssign(ctIn, testvec, bsk, decompositionOffset, ctOut)
+		return
+	}
+	e.blindRotateCMuxAssign(ctIn, testvec, bsk, decompositionOffset, ctOut)
+}
+
+// blindRotateCMuxAssign is the classic blind rotation: one CMux per LWE coefficient
+func (e *Evaluator) blindRotateCMuxAssign(ctIn *tlwe.TLWELv0, testvec *trlwe.TRLWELv1, bsk []*trgsw.TRGSWLv1FFT, decompositionOffset params.Torus, ctOut *trlwe.TRLWELv1) {
+	n := e.Params.TRGSWLv1.N
+	nBit := e.Params.TRGSWLv1.NBIT
+	tlweLv0N := e.Params.TLWELv0.N
 
 	// Initial rotation into buffer.ctAcc1
 	bTilda := 2*n - ((int(ctIn.B()) + (1 << (31 - nBit - 1))) >> (32 - nBit - 1))
@@ -134,9 +162,191 @@ func (e *Evaluator) BlindRotateAssign(ctIn *tlwe.TLWELv0, testvec *trlwe.TRLWELv
 	copy(ctOut.B, e.Buffers.BlindRotation.Accumulator1.B)
 }
 
+// blindRotateBlockAssign is the block blind rotation of tfhe-go
+// (Algorithm 2 and Section 5.1 of https://eprint.iacr.org/2023/958).
+//
+// The LWE key is block binary: each block of BlockSize coefficients has at
+// most one non-zero entry, so the CMux chain over a block collapses to
+//
+//	ACC += sum_j (X^a_j - 1) * (BSK_j ⊡ ACC)
+//
+// The accumulator is decomposed and transformed once per block instead of
+// once per coefficient, and the block is summed in the Fourier domain.
+func (e *Evaluator) blindRotateBlockAssign(ctIn *tlwe.TLWELv0, testvec *trlwe.TRLWELv1, bsk []*trgsw.TRGSWLv1FFT, decompositionOffset params.Torus, ctOut *trlwe.TRLWELv1) {
+	n := e.Params.TRGSWLv1.N
+	nBit := e.Params.TRGSWLv1.NBIT
+	l := e.Params.TRGSWLv1.L
+	bgbit := int(e.Params.TRGSWLv1.BGBIT)
+	tlweLv0N := e.Params.TLWELv0.N
+	blockSize := e.Params.TRGSWLv1.BlockSize
+
+	acc := e.Buffers.BlindRotation.Accumulator1
+	brb := e.Buffers.BlockRotation
+	polyDecomposed := e.Decomposer.GetPolyDecomposedBuffer(l * 2)
+
+	// Initial rotation into the accumulator
+	bTilda := 2*n - ((int(ctIn.B()) + (1 << (31 - nBit - 1))) >> (32 - nBit - 1))
+	poly.PolyMulWithXKInPlace(testvec.A, bTilda, acc.A)
+	poly.PolyMulWithXKInPlace(testvec.B, bTilda, acc.B)
+
+	for start := 0; start < tlweLv0N; start += blockSize {
+		end := min(start+blockSize, tlweLv0N)
+
+		// Decompose the accumulator once for the whole block
+		poly.DecomposePolyAssign(acc.A, bgbit, l, decompositionOffset, polyDecomposed[:l])
+		poly.DecomposePolyAssign(acc.B, bgbit, l, decompositionOffset, polyDecomposed[l:l*2])
+		for k := 0; k < l; k++ {
+			e.PolyEvaluator.ToFourierPolyAssign(polyDecomposed[k], brb.AccFourierDecomposed[0][k])
+			e.PolyEvaluator.ToFourierPolyAssign(polyDecomposed[l+k], brb.AccFourierDecomposed[1][k])
+		}
+
+		brb.FourierAcc.A.Clear()
+		brb.FourierAcc.B.Clear()
+
+		for j := start; j < end; j++ {
+			blk := brb.BlockFourierAcc[j-start]
+
+			// External product BSK_j ⊡ ACC using the shared decomposition
+			blk.A.Clear()
+			blk.B.Clear()
+			for k := 0; k < l; k++ {
+				e.PolyEvaluator.MulAddFourierPolyAssign(brb.AccFourierDecomposed[0][k], bsk[j].TRLWEFFT[k].A, blk.A)
+				e.PolyEvaluator.MulAddFourierPolyAssign(brb.AccFourierDecomposed[0][k], bsk[j].TRLWEFFT[k].B, blk.B)
+				e.PolyEvaluator.MulAddFourierPolyAssign(brb.AccFourierDecomposed[1][k], bsk[j].TRLWEFFT[l+k].A, blk.A)
+				e.PolyEvaluator.MulAddFourierPolyAssign(brb.AccFourierDecomposed[1][k], bsk[j].TRLWEFFT[l+k].B, blk.B)
+			}
+
+			// FourierAcc += (X^aTilda - 1) * blk
+			aTilda := int((ctIn.P[j] + (1 << (31 - nBit - 1))) >> (32 - nBit - 1))
+			e.PolyEvaluator.MonomialToFourierPolyAssign(aTilda, brb.FourierMono)
+			e.PolyEvaluator.MulAddFourierPolyAssign(blk.A, brb.FourierMono, brb.FourierAcc.A)
+			e.PolyEvaluator.MulAddFourierPolyAssign(blk.B, brb.FourierMono, brb.FourierAcc.B)
+			e.PolyEvaluator.SubFourierPolyAssign(brb.FourierAcc.A, blk.A, brb.FourierAcc.A)
+			e.PolyEvaluator.SubFourierPolyAssign(brb.FourierAcc.B, blk.B, brb.FourierAcc.B)
+		}
+
+		// ACC += FourierAcc
+		e.PolyEvaluator.ToPolyAddAssignUnsafe(brb.FourierAcc.A, poly.Poly{Coeffs: acc.A})
+		e.PolyEvaluator.ToPolyAddAssignUnsafe(brb.FourierAcc.B, poly.Poly{Coeffs: acc.B})
+	}
+
+	// Copy result to output
+	copy(ctOut.A, acc.A)
+	copy(ctOut.B, acc.B)
+}
+
+// blindRotateExtendedAssign blind rotates an extended lookup table made of
+// polyExtendFactor polynomials (LookUpTableSize = polyExtendFactor * N), as in tfhe-go.
+//
+// The table is a polynomial modulo X^(polyExtendFactor*N) + 1, stored as
+//
+//	F(X) = sum_i X^i F_i(Y),  Y = X^polyExtendFactor
+//
+// Multiplying by X^a with a = q*polyExtendFactor + r moves F_i to component
+// i+r multiplied by Y^q, or to component i+r-polyExtendFactor multiplied by
+// Y^(q+1) when it wraps. Every rotation is therefore a permutation of the
+// accumulators followed by a monomial product of degree N. Keys are applied
+// with the same block update as blindRotateBlockAssign (one key per block
+// when BlockSize <= 1). The result is the accumulator holding F_0.
+func (e *Evaluator) blindRotateExtendedAssign(ctIn *tlwe.TLWELv0, lutPolys []*trlwe.TRLWELv1, bsk []*trgsw.TRGSWLv1FFT, decompositionOffset params.Torus, ctOut *trlwe.TRLWELv1) {
+	erb := e.Buffers.ExtendedRotation
+	if erb == nil || len(erb.Accumulators) != len(lutPolys) {
+		panic("evaluator: lookup table size does not match the evaluator's parameter set")
+	}
+
+	polyExtendFactor := len(lutPolys)
+	lookUpTableSize := polyExtendFactor * e.Params.TRGSWLv1.N
+	l := e.Params.TRGSWLv1.L
+	bgbit := int(e.Params.TRGSWLv1.BGBIT)
+	tlweLv0N := e.Params.TLWELv0.N
+	blockSize := max(e.Params.TRGSWLv1.BlockSize, 1)
+
+	polyDecomposed := e.Decomposer.GetPolyDecomposedBuffer(l * 2)
+
+	// Initial rotation by -b
+	b2N := 2*lookUpTableSize - modSwitch(ctIn.B(), lookUpTableSize)
+	q, r := b2N/polyExtendFactor, b2N%polyExtendFactor
+	for i, acc := range erb.Accumulators {
+		src, k := i-r, q
+		if src < 0 {
+			src, k = src+polyExtendFactor, k+1
+		}
+		poly.PolyMulWithXKInPlace(lutPolys[src].A, k, acc.A)
+		poly.PolyMulWithXKInPlace(lutPolys[src].B, k, acc.B)
+	}
+
+	for start := 0; start < tlweLv0N; start += blockSize {
+		end := min(start+blockSize, tlweLv0N)
+
+		// Decompose every accumulator once for the whole block
+		for i, acc := range erb.Accumulators {
+			poly.DecomposePolyAssign(acc.A, bgbit, l, decompositionOffset, polyDecomposed[:l])
+			poly.DecomposePolyAssign(acc.B, bgbit, l, decompositionOffset, polyDecomposed[l:l*2])
+			for k := 0; k < l; k++ {
+				e.PolyEvaluator.ToFourierPolyAssign(polyDecomposed[k], erb.AccFourierDecomposed[i][0][k])
+				e.PolyEvaluator.ToFourierPolyAssign(polyDecomposed[l+k], erb.AccFourierDecomposed[i][1][k])
+			}
+			erb.FourierAcc[i].A.Clear()
+			erb.FourierAcc[i].B.Clear()
+		}
+
+		for j := start; j < end; j++ {
+			// External products BSK_j ⊡ ACC_i using the shared decompositions
+			for i := range erb.Accumulators {
+				blk := erb.BlockFourierAcc[i]
+				blk.A.Clear()
+				blk.B.Clear()
+				for k := 0; k < l; k++ {
+					e.PolyEvaluator.MulAddFourierPolyAssign(erb.AccFourierDecomposed[i][0][k], bsk[j].TRLWEFFT[k].A, blk.A)
+					e.PolyEvaluator.MulAddFourierPolyAssign(erb.AccFourierDecomposed[i][0][k], bsk[j].TRLWEFFT[k].B, blk.B)
+					e.PolyEvaluator.MulAddFourierPolyAssign(erb.AccFourierDecomposed[i][1][k], bsk[j].TRLWEFFT[l+k].A, blk.A)
+					e.PolyEvaluator.MulAddFourierPolyAssign(erb.AccFourierDecomposed[i][1][k], bsk[j].TRLWEFFT[l+k].B, blk.B)
+				}
+			}
+
+			// FourierAcc += (X^a2N - 1) * blk, with X^a2N permuting the components
+			a2N := modSwitch(ctIn.P[j], lookUpTableSize)
+			q, r := a2N/polyExtendFactor, a2N%polyExtendFactor
+			e.PolyEvaluator.MonomialToFourierPolyAssign(q, erb.FourierMono)
+			e.PolyEvaluator.MonomialToFourierPolyAssign(q+1, erb.FourierMonoNext)
+			for i := range erb.Accumulators {
+				src, mono := i-r, erb.FourierMono
+				if src < 0 {
+					src, mono = src+polyExtendFactor, erb.FourierMonoNext
+				}
+				e.PolyEvaluator.MulAddFourierPolyAssign(erb.BlockFourierAcc[src].A, mono, erb.FourierAcc[i].A)
+				e.PolyEvaluator.MulAddFourierPolyAssign(erb.BlockFourierAcc[src].B, mono, erb.FourierAcc[i].B)
+				e.PolyEvaluator.SubFourierPolyAssign(erb.FourierAcc[i].A, erb.BlockFourierAcc[i].A, erb.FourierAcc[i].A)
+				e.PolyEvaluator.SubFourierPolyAssign(erb.FourierAcc[i].B, erb.BlockFourierAcc[i].B, erb.FourierAcc[i].B)
+			}
+		}
+
+		// ACC_i += FourierAcc_i
+		for i, acc := range erb.Accumulators {
+			e.PolyEvaluator.ToPolyAddAssignUnsafe(erb.FourierAcc[i].A, poly.Poly{Coeffs: acc.A})
+			e.PolyEvaluator.ToPolyAddAssignUnsafe(erb.FourierAcc[i].B, poly.Poly{Coeffs: acc.B})
+		}
+	}
+
+	// Copy result to output
+	copy(ctOut.A, erb.Accumulators[0].A)
+	copy(ctOut.B, erb.Accumulators[0].B)
+}
+
+// modSwitch rounds x from the torus to Z_(2*lookUpTableSize)
+func modSwitch(x params.Torus, lookUpTableSize int) int {
+	twoL := uint64(2 * lookUpTableSize)
+	return int(((uint64(x)*twoL + 1<<31) >> 32) % twoL)
+}
+
 // BootstrapAssign performs full bootstrapping (blind rotate + key switch)
 // Zero-allocation version - writes to ctOut
+//
+// ctIn is a gate input (see SetNoiseCheck), and ctOut.Variance is reset to
+// the bootstrap noise of the parameter set.
 func (e *Evaluator) BootstrapAssign(ctIn *tlwe.TLWELv0, testvec *trlwe.TRLWELv1, bsk []*trgsw.TRGSWLv1FFT, ksk []*tlwe.TLWELv0, decompositionOffset params.Torus, ctOut *tlwe.TLWELv0) {
+	e.checkGateInput(ctIn.Variance)
+
 	// Blind rotate
 	e.BlindRotateAssign(ctIn, testvec, bsk, decompositionOffset, e.Buffers.BlindRotation.Rotated)
 
@@ -145,6 +355,7 @@ func (e *Evaluator) BootstrapAssign(ctIn *tlwe.TLWELv0, testvec *trlwe.TRLWELv1,
 
 	// Key switch - writes directly to ctOut (zero-allocation!)
 	trgsw.IdentityKeySwitchingAssign(e.Buffers.Bootstrap.ExtractedLWE, ksk, ctOut)
+	ctOut.Variance = e.Params.BootstrapVariance()
 }
 
 // Bootstrap performs full bootstrapping and returns result using buffer pool
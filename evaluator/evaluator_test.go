@@ -0,0 +1,120 @@
+package evaluator
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/thedonutfactory/go-tfhe/cloudkey"
+	"github.com/thedonutfactory/go-tfhe/key"
+	"github.com/thedonutfactory/go-tfhe/lut"
+	"github.com/thedonutfactory/go-tfhe/params"
+	"github.com/thedonutfactory/go-tfhe/tlwe"
+	"github.com/thedonutfactory/go-tfhe/trlwe"
+)
+
+// phaseLv1 returns b - <a, s> of a TLWE Level 1 ciphertext
+func phaseLv1(ct *tlwe.TLWELv1, key []params.Torus) params.Torus {
+	var innerProduct params.Torus
+	for i := range key {
+		innerProduct += ct.P[i] * key[i]
+	}
+	return ct.P[len(key)] - innerProduct
+}
+
+func TestBlockBlindRotationMatchesCMux(t *testing.T) {
+	p := params.GetParameters(params.Security80Bit)
+	p.TRGSWLv1.BlockSize = 3
+
+	secretKey := key.NewSecretKeyWithParams(p)
+	cloudKey := cloudkey.NewCloudKey(secretKey)
+	eval := NewEvaluatorWithParams(p)
+
+	const messageModulus = 8
+	f := func(x int) int { return (3*x + 1) % messageModulus }
+	lookupTable := lut.NewGeneratorWithParams(p, messageModulus).GenLookUpTable(f)
+	scale := params.Torus(1<<31) / messageModulus
+
+	blockOut := trlwe.NewTRLWELv1WithParams(p)
+	cmuxOut := trlwe.NewTRLWELv1WithParams(p)
+
+	// Both paths encrypt the same value and only the noise may differ,
+	// so compare the decoded messages and the empirical noise variance
+	const samples = 16 * messageModulus
+	var blockVar, cmuxVar float64
+	for i := 0; i < samples; i++ {
+		x := i % messageModulus
+		ct := tlwe.NewTLWELv0WithParams(p).EncryptLWEMessage(x, messageModulus, p.TLWELv0.ALPHA, secretKey.KeyLv0)
+
+		eval.blindRotateBlockAssign(ct, lookupTable.Poly, cloudKey.BootstrappingKey, cloudKey.DecompositionOffset, blockOut)
+		eval.blindRotateCMuxAssign(ct, lookupTable.Poly, cloudKey.BootstrappingKey, cloudKey.DecompositionOffset, cmuxOut)
+
+		blockPhase := phaseLv1(trlwe.SampleExtractIndex(blockOut, 0), secretKey.KeyLv1)
+		cmuxPhase := phaseLv1(trlwe.SampleExtractIndex(cmuxOut, 0), secretKey.KeyLv1)
+
+		for _, tc := range []struct {
+			name  string
+			phase params.Torus
+			sum   *float64
+		}{{"block", blockPhase, &blockVar}, {"cmux", cmuxPhase, &cmuxVar}} {
+			if got := int((tc.phase+scale/2)/scale) % messageModulus; got != f(x) {
+				t.Errorf("%s blind rotation of %d decoded to %d, want %d", tc.name, x, got, f(x))
+			}
+			e := float64(int32(tc.phase-params.Torus(f(x))*scale)) / (1 << 32)
+			*tc.sum += e * e
+		}
+	}
+
+	// Block blind rotation multiplies every bootstrapping key by X^a - 1,
+	// which doubles its noise. Over 128 samples the ratio of the two empirical
+	// variances then follows 2*F(128, 128), whose 99.99% quantile is 2*1.95.
+	const maxRatio = 2 * 1.95
+	if blockVar > maxRatio*cmuxVar {
+		t.Errorf("block blind rotation noise variance %g exceeds %.1f times the CMux variance %g", blockVar/samples, maxRatio, cmuxVar/samples)
+	}
+}
+
+func TestBlindRotationFallback(t *testing.T) {
+	p := params.GetParameters(params.Security80Bit)
+	p.TRGSWLv1.BlockSize = 3
+	secretKey := key.NewSecretKeyWithParams(p)
+	cloudKey := cloudkey.NewCloudKey(secretKey)
+
+	// CMux blind rotation is also correct for block binary keys
+	classic := p
+	classic.TRGSWLv1.BlockSize = 1
+	eval := NewEvaluatorWithParams(classic)
+	if eval.Buffers.BlockRotation != nil {
+		t.Fatal("BlockSize 1 should not allocate block rotation buffers")
+	}
+
+	for x := 0; x < 4; x++ {
+		ct := tlwe.NewTLWELv0WithParams(p).EncryptLWEMessage(x, 4, p.TLWELv0.ALPHA, secretKey.KeyLv0)
+		result := eval.BootstrapFunc(ct, func(m int) int { return 3 - m }, 4, cloudKey.BootstrappingKey, cloudKey.KeySwitchingKey, cloudKey.DecompositionOffset)
+		if got := result.DecryptLWEMessage(4, secretKey.KeyLv0); got != 3-x {
+			t.Errorf("classic blind rotation: 3 - %d = %d", x, got)
+		}
+	}
+}
+
+func TestBlockBlindRotationRejectsUniformKeys(t *testing.T) {
+	classic := params.GetParameters(params.Security80Bit)
+	block := classic
+	block.TRGSWLv1.BlockSize = 3
+
+	if err := key.NewSecretKeyWithParams(block).CheckBlockBinary(); err != nil {
+		t.Errorf("block binary key: CheckBlockBinary() = %v", err)
+	}
+
+	// About half of the blocks of a uniform binary key hold two or more ones
+	uniform := key.NewSecretKeyWithParams(classic)
+	uniform.Params = block
+	if err := uniform.CheckBlockBinary(); !errors.Is(err, key.ErrNotBlockBinary) {
+		t.Fatalf("uniform key: CheckBlockBinary() = %v, want ErrNotBlockBinary", err)
+	}
+	defer func() {
+		if recover() == nil {
+			t.Error("NewCloudKey accepted a uniform key for block blind rotation")
+		}
+	}()
+	cloudkey.NewCloudKey(uniform)
+}
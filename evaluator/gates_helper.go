@@ -3,61 +3,145 @@ package evaluator
 import (
 	"github.com/thedonutfactory/go-tfhe/params"
 	"github.com/thedonutfactory/go-tfhe/tlwe"
+	"github.com/thedonutfactory/go-tfhe/trgsw"
+	"github.com/thedonutfactory/go-tfhe/trlwe"
 	"github.com/thedonutfactory/go-tfhe/utils"
 )
 
 // PrepareNAND prepares a NAND input for bootstrapping (zero-allocation)
 func (e *Evaluator) PrepareNAND(a, b *tlwe.TLWELv0) *tlwe.TLWELv0 {
-	n := params.GetTLWELv0().N
-	result := tlwe.NewTLWELv0()
+	n := e.Params.TLWELv0.N
+	result := tlwe.NewTLWELv0WithParams(e.Params)
 
 	// NAND: -(a + b) + 1/8
 	for i := 0; i < n; i++ {
 		result.P[i] = -(a.P[i] + b.P[i])
 	}
 	result.P[n] = -(a.P[n] + b.P[n]) + utils.F64ToTorus(0.125)
+	result.Variance = a.Variance + b.Variance
 
 	return result
 }
 
 // PrepareAND prepares an AND input for bootstrapping
 func (e *Evaluator) PrepareAND(a, b *tlwe.TLWELv0) *tlwe.TLWELv0 {
-	n := params.GetTLWELv0().N
-	result := tlwe.NewTLWELv0()
+	n := e.Params.TLWELv0.N
+	result := tlwe.NewTLWELv0WithParams(e.Params)
 
 	// AND: (a + b) - 1/8
 	for i := 0; i < n; i++ {
 		result.P[i] = a.P[i] + b.P[i]
 	}
 	result.P[n] = a.P[n] + b.P[n] + utils.F64ToTorus(-0.125)
+	result.Variance = a.Variance + b.Variance
 
 	return result
 }
 
 // PrepareOR prepares an OR input for bootstrapping
 func (e *Evaluator) PrepareOR(a, b *tlwe.TLWELv0) *tlwe.TLWELv0 {
-	n := params.GetTLWELv0().N
-	result := tlwe.NewTLWELv0()
+	n := e.Params.TLWELv0.N
+	result := tlwe.NewTLWELv0WithParams(e.Params)
 
 	// OR: (a + b) + 1/8
 	for i := 0; i < n; i++ {
 		result.P[i] = a.P[i] + b.P[i]
 	}
 	result.P[n] = a.P[n] + b.P[n] + utils.F64ToTorus(0.125)
+	result.Variance = a.Variance + b.Variance
 
 	return result
 }
 
 // PrepareXOR prepares an XOR input for bootstrapping
 func (e *Evaluator) PrepareXOR(a, b *tlwe.TLWELv0) *tlwe.TLWELv0 {
-	n := params.GetTLWELv0().N
-	result := tlwe.NewTLWELv0()
+	n := e.Params.TLWELv0.N
+	result := tlwe.NewTLWELv0WithParams(e.Params)
 
 	// XOR: (a + 2*b) + 1/4
 	for i := 0; i < n; i++ {
 		result.P[i] = a.P[i] + 2*b.P[i]
 	}
 	result.P[n] = a.P[n] + 2*b.P[n] + utils.F64ToTorus(0.25)
+	result.Variance = a.Variance + 4*b.Variance
 
 	return result
 }
+
+// PrepareMAJ prepares a three-input majority for bootstrapping.
+//
+// The sum of three ±1/8 inputs is -3/8 or -1/8 when at most one is true and
+// 1/8 or 3/8 otherwise, so the sign test of the gate test vector gives the
+// majority with the usual 1/8 margin (the noise variance is that of three
+// inputs instead of two).
+func (e *Evaluator) PrepareMAJ(a, b, c *tlwe.TLWELv0) *tlwe.TLWELv0 {
+	n := e.Params.TLWELv0.N
+	result := tlwe.NewTLWELv0WithParams(e.Params)
+
+	// MAJ: a + b + c
+	for i := 0; i <= n; i++ {
+		result.P[i] = a.P[i] + b.P[i] + c.P[i]
+	}
+	result.Variance = a.Variance + b.Variance + c.Variance
+
+	return result
+}
+
+// PrepareXOR3 prepares a three-input XOR for bootstrapping.
+//
+// -2(a + b + c) is -1/4 (mod 1) for an even number of true inputs and 1/4
+// for an odd number, a 1/4 margin on either side of the sign test.
+func (e *Evaluator) PrepareXOR3(a, b, c *tlwe.TLWELv0) *tlwe.TLWELv0 {
+	n := e.Params.TLWELv0.N
+	result := tlwe.NewTLWELv0WithParams(e.Params)
+
+	// XOR3: -2(a + b + c)
+	for i := 0; i <= n; i++ {
+		sum := a.P[i] + b.P[i] + c.P[i]
+		result.P[i] = -(sum + sum)
+	}
+	result.Variance = 4 * (a.Variance + b.Variance + c.Variance)
+
+	return result
+}
+
+// MUXAssign evaluates the gate-encoded multiplexer a ? b : c and writes it to ctOut.
+//
+// a AND b and NOT(a) AND c are blind rotated separately but not key switched:
+// at most one of them is true, so their level 1 samples plus 1/8 encode the
+// result, and a single key switch brings the sum back to level 0. This costs
+// two blind rotations and one key switch instead of the three full
+// bootstraps of AND, AND and OR.
+func (e *Evaluator) MUXAssign(a, b, c *tlwe.TLWELv0, testvec *trlwe.TRLWELv1, bsk []*trgsw.TRGSWLv1FFT, ksk []*tlwe.TLWELv0, decompositionOffset params.Torus, ctOut *tlwe.TLWELv0) {
+	n := e.Params.TLWELv0.N
+	prep := e.Buffers.GatePrep
+	andAB := e.Buffers.Bootstrap.ExtractedLWE
+	andNotAC := e.Buffers.Bootstrap.MuxLWE
+
+	// a AND b: (a + b) - 1/8
+	for i := 0; i <= n; i++ {
+		prep.P[i] = a.P[i] + b.P[i]
+	}
+	prep.P[n] += utils.F64ToTorus(-0.125)
+	e.checkGateInput(a.Variance + b.Variance)
+	e.BlindRotateAssign(prep, testvec, bsk, decompositionOffset, e.Buffers.BlindRotation.Rotated)
+	trlwe.SampleExtractIndexAssign(e.Buffers.BlindRotation.Rotated, 0, andAB)
+
+	// NOT(a) AND c: (c - a) - 1/8
+	for i := 0; i <= n; i++ {
+		prep.P[i] = c.P[i] - a.P[i]
+	}
+	prep.P[n] += utils.F64ToTorus(-0.125)
+	e.checkGateInput(c.Variance + a.Variance)
+	e.BlindRotateAssign(prep, testvec, bsk, decompositionOffset, e.Buffers.BlindRotation.Rotated)
+	trlwe.SampleExtractIndexAssign(e.Buffers.BlindRotation.Rotated, 0, andNotAC)
+
+	// OR of two exclusive booleans: sum + 1/8
+	for i := range andAB.P {
+		andAB.P[i] += andNotAC.P[i]
+	}
+	andAB.P[len(andAB.P)-1] += utils.F64ToTorus(0.125)
+
+	trgsw.IdentityKeySwitchingAssign(andAB, ksk, ctOut)
+	ctOut.Variance = 2*e.Params.BlindRotateVariance() + e.Params.KeySwitchVariance()
+}
@@ -0,0 +1,96 @@
+package evaluator
+
+import (
+	"github.com/thedonutfactory/go-tfhe/lut"
+	"github.com/thedonutfactory/go-tfhe/params"
+	"github.com/thedonutfactory/go-tfhe/tlwe"
+	"github.com/thedonutfactory/go-tfhe/trgsw"
+	"github.com/thedonutfactory/go-tfhe/trlwe"
+)
+
+// BootstrapManyFunc evaluates every function in fs on ctIn with a single
+// blind rotation, returning one ciphertext per function
+func (e *Evaluator) BootstrapManyFunc(
+	ctIn *tlwe.TLWELv0,
+	fs []func(int) int,
+	messageModulus int,
+	bsk []*trgsw.TRGSWLv1FFT,
+	ksk []*tlwe.TLWELv0,
+	decompositionOffset params.Torus,
+) []*tlwe.TLWELv0 {
+	generator := lut.NewGeneratorWithParams(e.Params, messageModulus)
+	return e.BootstrapManyLUT(ctIn, generator.GenManyLookUpTable(fs), bsk, ksk, decompositionOffset)
+}
+
+// BootstrapManyLUT evaluates the functions of a many-function lookup table
+// on ctIn. It costs one blind rotation plus one sample extraction and key
+// switch per function, instead of a full bootstrap per function.
+func (e *Evaluator) BootstrapManyLUT(
+	ctIn *tlwe.TLWELv0,
+	table *lut.ManyLookUpTable,
+	bsk []*trgsw.TRGSWLv1FFT,
+	ksk []*tlwe.TLWELv0,
+	decompositionOffset params.Torus,
+) []*tlwe.TLWELv0 {
+	ctOut := make([]*tlwe.TLWELv0, table.Count)
+	for i := range ctOut {
+		ctOut[i] = tlwe.NewTLWELv0WithParams(e.Params)
+	}
+	e.BootstrapManyLUTAssign(ctIn, table, bsk, ksk, decompositionOffset, ctOut)
+	return ctOut
+}
+
+// BootstrapManyLUTAssign is BootstrapManyLUT writing to ctOut, which must
+// hold table.Count ciphertexts (zero-allocation)
+//
+// The input is first rounded so that every coefficient modulus switches to a
+// multiple of table.Count. The rotated table then starts on a group of
+// coefficients, and coefficient j holds function j. The rounding is a
+// modulus switch to a grid table.Count times coarser, which the noise check
+// takes into account.
+func (e *Evaluator) BootstrapManyLUTAssign(
+	ctIn *tlwe.TLWELv0,
+	table *lut.ManyLookUpTable,
+	bsk []*trgsw.TRGSWLv1FFT,
+	ksk []*tlwe.TLWELv0,
+	decompositionOffset params.Torus,
+	ctOut []*tlwe.TLWELv0,
+) {
+	if mm := table.Table.MessageModulus; mm > 0 && ctIn.Variance != 0 {
+		count := float64(table.Count)
+		checkInput(ctIn.Variance+count*count*e.Params.ModSwitchVariance(), LUTNoiseMargin(mm))
+	}
+
+	lookUpTableSize := e.Params.LookUpTableSize()
+	rounded := e.Buffers.Bootstrap.Rounded
+	for i, x := range ctIn.P {
+		rounded.P[i] = roundToGrid(x, 2*lookUpTableSize/table.Count)
+	}
+
+	polys := table.Table.Polys
+	if len(polys) > 1 {
+		e.blindRotateExtendedAssign(rounded, polys, bsk, decompositionOffset, e.Buffers.BlindRotation.Rotated)
+	} else {
+		e.BlindRotateAssign(rounded, table.Table.Poly, bsk, decompositionOffset, e.Buffers.BlindRotation.Rotated)
+	}
+
+	// Coefficient j of an extended table lives in accumulator j % polyExtendFactor
+	for j := 0; j < table.Count; j++ {
+		var acc *trlwe.TRLWELv1
+		if len(polys) > 1 {
+			acc = e.Buffers.ExtendedRotation.Accumulators[j%len(polys)]
+		} else {
+			acc = e.Buffers.BlindRotation.Rotated
+		}
+		trlwe.SampleExtractIndexAssign(acc, j/len(polys), e.Buffers.Bootstrap.ExtractedLWE)
+		trgsw.IdentityKeySwitchingAssign(e.Buffers.Bootstrap.ExtractedLWE, ksk, ctOut[j])
+		ctOut[j].Variance = e.Params.BootstrapVariance()
+	}
+}
+
+// roundToGrid rounds x to the nearest multiple of 1/steps on the torus
+func roundToGrid(x params.Torus, steps int) params.Torus {
+	s := uint64(steps)
+	m := ((uint64(x)*s + 1<<31) >> 32) % s
+	return params.Torus((m<<32 + s/2) / s)
+}
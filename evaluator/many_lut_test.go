@@ -0,0 +1,51 @@
+package evaluator
+
+import (
+	"testing"
+
+	"github.com/thedonutfactory/go-tfhe/cloudkey"
+	"github.com/thedonutfactory/go-tfhe/key"
+	"github.com/thedonutfactory/go-tfhe/lut"
+	"github.com/thedonutfactory/go-tfhe/params"
+	"github.com/thedonutfactory/go-tfhe/tlwe"
+)
+
+func TestBootstrapManyLUT(t *testing.T) {
+	p := params.GetParameters(params.Security80Bit)
+	secretKey := key.NewSecretKeyWithParams(p)
+	cloudKey := cloudkey.NewCloudKey(secretKey)
+	eval := NewEvaluatorWithParams(p)
+
+	const messageModulus = 8
+	fs := []func(int) int{
+		func(x int) int { return x % 4 },       // sum digit
+		func(x int) int { return x / 4 },       // carry
+		func(x int) int { return (x + 3) % 8 }, // shift
+		func(x int) int { return 7 - x },       // complement
+	}
+
+	for _, count := range []int{2, 4} {
+		table := lut.NewGeneratorWithParams(p, messageModulus).GenManyLookUpTable(fs[:count])
+		for x := 0; x < messageModulus; x++ {
+			ct := tlwe.NewTLWELv0WithParams(p).EncryptLWEMessage(x, messageModulus, p.TLWELv0.ALPHA, secretKey.KeyLv0)
+			results := eval.BootstrapManyLUT(ct, table, cloudKey.BootstrappingKey, cloudKey.KeySwitchingKey, cloudKey.DecompositionOffset)
+			if len(results) != count {
+				t.Fatalf("got %d results, want %d", len(results), count)
+			}
+			for j, result := range results {
+				if got, want := result.DecryptLWEMessage(messageModulus, secretKey.KeyLv0), fs[j](x); got != want {
+					t.Errorf("%d functions: f%d(%d) = %d, want %d", count, j, x, got, want)
+				}
+			}
+		}
+	}
+}
+
+func TestRoundToGrid(t *testing.T) {
+	const lookUpTableSize, count = 1024, 4
+	for _, x := range []params.Torus{0, 1, 1 << 20, 1 << 31, 0xFFFFFFFF, 0x12345678} {
+		if got := modSwitch(roundToGrid(x, 2*lookUpTableSize/count), lookUpTableSize); got%count != 0 {
+			t.Errorf("modSwitch(roundToGrid(%#x)) = %d, not a multiple of %d", x, got, count)
+		}
+	}
+}
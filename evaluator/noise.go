@@ -0,0 +1,130 @@
+package evaluator
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"math"
+	"sync/atomic"
+
+	"github.com/thedonutfactory/go-tfhe/params"
+	"github.com/thedonutfactory/go-tfhe/tlwe"
+)
+
+// ErrNoiseOverflow is reported when the noise of a bootstrap input is large
+// enough that the bootstrap fails with a probability above the threshold
+var ErrNoiseOverflow = errors.New("evaluator: ciphertext noise exceeds the failure probability threshold")
+
+// GateNoiseMargin is the distance from the input of a gate bootstrap to the
+// decision boundaries of the gate test vector (the Prepare functions put
+// the input at ±1/8). PrepareXOR3 leaves a margin of 1/4, so its inputs are
+// checked conservatively.
+const GateNoiseMargin = 0.125
+
+// DefaultNoiseThreshold is a failure probability for one bootstrap that is
+// negligible for circuits of any practical size (2^-40)
+const DefaultNoiseThreshold = 1.0 / (1 << 40)
+
+// LUTNoiseMargin returns the distance from an input encrypted with
+// EncryptLWEMessage under messageModulus to the edges of its lookup table
+// box: half of the spacing between two messages
+func LUTNoiseMargin(messageModulus int) float64 {
+	return 1 / float64(4*messageModulus)
+}
+
+// FailureProbability returns the probability that centered Gaussian noise
+// of the given variance is larger than margin in absolute value
+func FailureProbability(variance, margin float64) float64 {
+	if variance <= 0 {
+		return 0
+	}
+	return math.Erfc(margin / math.Sqrt(2*variance))
+}
+
+// CheckNoise reports whether bootstrapping ct with parameter set p fails
+// with a probability above threshold, from the noise variance tracked in ct
+// plus the modulus switch at the start of the bootstrap. margin is the
+// distance from the encoded message to the nearest decision boundary (see
+// GateNoiseMargin and LUTNoiseMargin). Ciphertexts without tracked noise
+// (Variance 0) always pass.
+func CheckNoise(p params.Parameters, ct *tlwe.TLWELv0, margin, threshold float64) error {
+	if ct.Variance == 0 {
+		return nil
+	}
+	return checkFailure(ct.Variance+p.ModSwitchVariance(), margin, threshold)
+}
+
+// checkFailure returns an error wrapping ErrNoiseOverflow if noise of the
+// given variance exceeds margin with a probability above threshold
+func checkFailure(variance, margin, threshold float64) error {
+	if prob := FailureProbability(variance, margin); prob > threshold {
+		return fmt.Errorf("%w: failure probability %.3g > %.3g (standard deviation %.3g, margin %.3g)",
+			ErrNoiseOverflow, prob, threshold, math.Sqrt(variance), margin)
+	}
+	return nil
+}
+
+// NoiseCheck configures the checking of bootstrap inputs (see SetNoiseCheck)
+type NoiseCheck struct {
+	// Threshold is the largest acceptable failure probability of one
+	// bootstrap, for example DefaultNoiseThreshold
+	Threshold float64
+
+	// Handler is called with an error wrapping ErrNoiseOverflow for every
+	// input over the threshold, for example WarnOnNoise or PanicOnNoise
+	Handler func(error)
+}
+
+// noiseCheck is the process-wide check; nil disables it
+var noiseCheck atomic.Pointer[NoiseCheck]
+
+// SetNoiseCheck makes every evaluator check the tracked noise of its
+// bootstrap inputs before bootstrapping them: gate inputs against
+// GateNoiseMargin and lookup table inputs against LUTNoiseMargin of the
+// table's message modulus. nil disables the checks, which is the default.
+//
+// The checks catch circuits that add too many ciphertexts together before a
+// bootstrap (chains of Add, the doubled input of PrepareXOR, ...). They are
+// cheap, but the handler runs on the evaluating goroutine.
+func SetNoiseCheck(c *NoiseCheck) {
+	noiseCheck.Store(c)
+}
+
+// WarnOnNoise is a NoiseCheck handler that logs the error and carries on
+func WarnOnNoise(err error) {
+	log.Print(err)
+}
+
+// PanicOnNoise is a NoiseCheck handler that panics with the error
+func PanicOnNoise(err error) {
+	panic(err)
+}
+
+// checkInput runs the configured noise check on a bootstrap input of the
+// given variance, which includes the modulus switch
+func checkInput(variance, margin float64) {
+	c := noiseCheck.Load()
+	if c == nil || margin <= 0 {
+		return
+	}
+	if err := checkFailure(variance, margin, c.Threshold); err != nil {
+		c.Handler(err)
+	}
+}
+
+// CheckBootstrapInput runs the check configured with SetNoiseCheck on ct,
+// the input of a bootstrap with parameter set p leaving the given margin.
+// Evaluator methods check their inputs themselves; code that bootstraps
+// without an Evaluator (trgsw.BatchBlindRotate) calls this instead.
+func CheckBootstrapInput(p params.Parameters, ct *tlwe.TLWELv0, margin float64) {
+	if ct.Variance != 0 {
+		checkInput(ct.Variance+p.ModSwitchVariance(), margin)
+	}
+}
+
+// checkGateInput checks the input of a gate bootstrap
+func (e *Evaluator) checkGateInput(variance float64) {
+	if variance != 0 {
+		checkInput(variance+e.Params.ModSwitchVariance(), GateNoiseMargin)
+	}
+}
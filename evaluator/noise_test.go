@@ -0,0 +1,86 @@
+package evaluator
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/thedonutfactory/go-tfhe/cloudkey"
+	"github.com/thedonutfactory/go-tfhe/key"
+	"github.com/thedonutfactory/go-tfhe/lut"
+	"github.com/thedonutfactory/go-tfhe/params"
+	"github.com/thedonutfactory/go-tfhe/tlwe"
+)
+
+func TestCheckNoise(t *testing.T) {
+	p := params.GetParameters(params.Security128Bit)
+	fresh := tlwe.NewTLWELv0WithParams(p)
+	fresh.Variance = p.FreshVariance()
+	scaled := fresh.AddMul(fresh, 1000)
+
+	testCases := []struct {
+		name   string
+		ct     *tlwe.TLWELv0
+		margin float64
+		want   error
+	}{
+		{"fresh gate input", fresh, GateNoiseMargin, nil},
+		{"untracked", tlwe.NewTLWELv0WithParams(p), LUTNoiseMargin(1 << 20), nil},
+		{"modulus switch past the margin", fresh, LUTNoiseMargin(256), ErrNoiseOverflow},
+		{"scaled input", scaled, GateNoiseMargin, ErrNoiseOverflow},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := CheckNoise(p, tc.ct, tc.margin, DefaultNoiseThreshold)
+			if !errors.Is(err, tc.want) {
+				t.Errorf("CheckNoise = %v, want %v", err, tc.want)
+			}
+		})
+	}
+
+	if got := FailureProbability(1, 0); got != 1 {
+		t.Errorf("FailureProbability with no margin = %g, want 1", got)
+	}
+	if got := FailureProbability(0, 0.1); got != 0 {
+		t.Errorf("FailureProbability without noise = %g, want 0", got)
+	}
+}
+
+func TestNoiseCheckOnBootstrap(t *testing.T) {
+	p := params.GetParameters(params.SecurityUint2)
+	secretKey := key.NewSecretKeyWithParams(p)
+	cloudKey := cloudkey.NewCloudKey(secretKey)
+	eval := NewEvaluatorWithParams(p)
+
+	var reported []error
+	SetNoiseCheck(&NoiseCheck{
+		Threshold: DefaultNoiseThreshold,
+		Handler:   func(err error) { reported = append(reported, err) },
+	})
+	defer SetNoiseCheck(nil)
+
+	const messageModulus = 4
+	table := lut.NewGeneratorWithParams(p, messageModulus).GenLookUpTable(func(x int) int { return x })
+	bootstrap := func(ct *tlwe.TLWELv0) *tlwe.TLWELv0 {
+		return eval.BootstrapLUT(ct, table, cloudKey.BootstrappingKey, cloudKey.KeySwitchingKey, cloudKey.DecompositionOffset)
+	}
+
+	ct := tlwe.NewTLWELv0WithParams(p).EncryptLWEMessage(1, messageModulus, p.TLWELv0.ALPHA, secretKey.KeyLv0)
+	out := bootstrap(ct)
+	if len(reported) != 0 {
+		t.Errorf("bootstrapping a fresh ciphertext reported %v", reported)
+	}
+	if out.Variance != p.BootstrapVariance() {
+		t.Errorf("bootstrapped variance %g, want %g", out.Variance, p.BootstrapVariance())
+	}
+
+	// Adding a bootstrapped ciphertext to itself 64 times keeps the message
+	// (64 = 0 mod 4) but not the noise budget
+	sum := out
+	for i := 0; i < 64; i++ {
+		sum = sum.Add(out)
+	}
+	bootstrap(sum)
+	if len(reported) != 1 || !errors.Is(reported[0], ErrNoiseOverflow) {
+		t.Errorf("bootstrapping a sum of 65 ciphertexts reported %v, want ErrNoiseOverflow", reported)
+	}
+}
@@ -22,7 +22,7 @@ func (e *Evaluator) BootstrapFunc(
 	decompositionOffset params.Torus,
 ) *tlwe.TLWELv0 {
 	// Generate lookup table from function
-	generator := lut.NewGenerator(messageModulus)
+	generator := lut.NewGeneratorWithParams(e.Params, messageModulus)
 	lookupTable := generator.GenLookUpTable(f)
 
 	// Perform LUT-based bootstrapping
@@ -40,7 +40,7 @@ func (e *Evaluator) BootstrapFuncAssign(
 	ctOut *tlwe.TLWELv0,
 ) {
 	// Generate lookup table from function
-	generator := lut.NewGenerator(messageModulus)
+	generator := lut.NewGeneratorWithParams(e.Params, messageModulus)
 	lookupTable := generator.GenLookUpTable(f)
 
 	// Perform LUT-based bootstrapping
@@ -61,8 +61,8 @@ func (e *Evaluator) BootstrapLUT(
 	result := e.Buffers.GetNextResult()
 	e.BootstrapLUTAssign(ctIn, lut, bsk, ksk, decompositionOffset, result)
 
-	copiedResult := tlwe.NewTLWELv0()
-	copy(copiedResult.P, result.P)
+	copiedResult := tlwe.NewTLWELv0WithParams(e.Params)
+	copiedResult.CopyFrom(result)
 	copiedResult.SetB(result.B())
 
 	return copiedResult
@@ -90,6 +90,10 @@ func (e *Evaluator) BootstrapLUTTemp(
 //
 // The key insight is that we can reuse the existing BlindRotateAssign function
 // by converting the LUT into a TRLWE ciphertext (test vector).
+//
+// If the table records its message modulus, ctIn is checked against
+// LUTNoiseMargin (see SetNoiseCheck). ctOut.Variance is reset to the
+// bootstrap noise of the parameter set.
 func (e *Evaluator) BootstrapLUTAssign(
 	ctIn *tlwe.TLWELv0,
 	lut *lut.LookUpTable,
@@ -98,13 +102,19 @@ func (e *Evaluator) BootstrapLUTAssign(
 	decompositionOffset params.Torus,
 	ctOut *tlwe.TLWELv0,
 ) {
-	// Convert LUT to TRLWE format (test vector)
-	// The LUT is already a TRLWE with the function encoded in the B polynomial
-	testvec := lut.Poly
+	if lut.MessageModulus > 0 && ctIn.Variance != 0 {
+		checkInput(ctIn.Variance+e.Params.ModSwitchVariance(), LUTNoiseMargin(lut.MessageModulus))
+	}
 
 	// Perform blind rotation using the LUT as the test vector
-	// This rotates the LUT based on the encrypted value, effectively evaluating the function
-	e.BlindRotateAssign(ctIn, testvec, bsk, decompositionOffset, e.Buffers.BlindRotation.Rotated)
+	// This rotates the LUT based on the encrypted value, effectively evaluating the function.
+	// Extended LUTs (polyExtendFactor > 1) rotate all of their polynomials together.
+	if len(lut.Polys) > 1 {
+		e.blindRotateExtendedAssign(ctIn, lut.Polys, bsk, decompositionOffset, e.Buffers.BlindRotation.Rotated)
+	} else {
+		// The LUT is already a TRLWE with the function encoded in the B polynomial
+		e.BlindRotateAssign(ctIn, lut.Poly, bsk, decompositionOffset, e.Buffers.BlindRotation.Rotated)
+	}
 
 	// Extract the constant term as an LWE ciphertext
 	// This gives us the function evaluation encrypted under the TRLWE key
@@ -112,4 +122,5 @@ func (e *Evaluator) BootstrapLUTAssign(
 
 	// Key switch to convert back to the original LWE key
 	trgsw.IdentityKeySwitchingAssign(e.Buffers.Bootstrap.ExtractedLWE, ksk, ctOut)
+	ctOut.Variance = e.Params.BootstrapVariance()
 }
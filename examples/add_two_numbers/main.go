@@ -56,15 +56,17 @@ func main() {
 	lutStart := time.Now()
 	gen := lut.NewGenerator(32)
 
-	lutSumLow := gen.GenLookUpTable(func(x int) int {
-		return x % 16 // Extract lower 4 bits
-	})
-
-	lutCarryLow := gen.GenLookUpTable(func(x int) int {
-		if x >= 16 {
-			return 1 // Carry out
-		}
-		return 0
+	// Sum and carry of the low nibbles share one blind rotation
+	lutLow := gen.GenManyLookUpTable([]func(int) int{
+		func(x int) int {
+			return x % 16 // Extract lower 4 bits
+		},
+		func(x int) int {
+			if x >= 16 {
+				return 1 // Carry out
+			}
+			return 0
+		},
 	})
 
 	lutSumHigh := gen.GenLookUpTable(func(x int) int {
@@ -106,38 +108,32 @@ func main() {
 	}
 	fmt.Println("   Step 1: Low nibbles added (homomorphic add, no bootstrap)")
 
-	// Step 5: Bootstrap 1 - Extract low sum (mod 16)
+	// Step 5: Bootstrap 1 - Extract low sum (mod 16) and carry with one blind rotation
 	pbs1Start := time.Now()
-	ctSumLow := eval.BootstrapLUT(ctTempLow, lutSumLow,
+	lowResults := eval.BootstrapManyLUT(ctTempLow, lutLow,
 		cloudKey.BootstrappingKey, cloudKey.KeySwitchingKey, cloudKey.DecompositionOffset)
+	ctSumLow, ctCarry := lowResults[0], lowResults[1]
 	pbs1Duration := time.Since(pbs1Start)
-	fmt.Printf("   Bootstrap 1: Extract low sum (mod 16) - %v\n", pbs1Duration)
+	fmt.Printf("   Bootstrap 1: Extract low sum (mod 16) and carry bit - %v\n", pbs1Duration)
 
-	// Step 6: Bootstrap 2 - Extract carry from low nibbles
-	pbs2Start := time.Now()
-	ctCarry := eval.BootstrapLUT(ctTempLow, lutCarryLow,
-		cloudKey.BootstrappingKey, cloudKey.KeySwitchingKey, cloudKey.DecompositionOffset)
-	pbs2Duration := time.Since(pbs2Start)
-	fmt.Printf("   Bootstrap 2: Extract carry bit - %v\n", pbs2Duration)
-
-	// Step 7: Add high nibbles + carry (homomorphic)
+	// Step 6: Add high nibbles + carry (homomorphic)
 	ctTempHigh := tlwe.NewTLWELv0()
 	for j := 0; j < n+1; j++ {
 		ctTempHigh.P[j] = ctAHigh.P[j] + ctBHigh.P[j] + ctCarry.P[j]
 	}
 	fmt.Println("   Step 2: High nibbles + carry added (homomorphic add, no bootstrap)")
 
-	// Step 8: Bootstrap 3 - Extract high sum (mod 16)
-	pbs3Start := time.Now()
+	// Step 7: Bootstrap 2 - Extract high sum (mod 16)
+	pbs2Start := time.Now()
 	ctSumHigh := eval.BootstrapLUT(ctTempHigh, lutSumHigh,
 		cloudKey.BootstrappingKey, cloudKey.KeySwitchingKey, cloudKey.DecompositionOffset)
-	pbs3Duration := time.Since(pbs3Start)
-	fmt.Printf("   Bootstrap 3: Extract high sum (mod 16) - %v\n", pbs3Duration)
+	pbs2Duration := time.Since(pbs2Start)
+	fmt.Printf("   Bootstrap 2: Extract high sum (mod 16) - %v\n", pbs2Duration)
 
 	addDuration := time.Since(addStart)
 	fmt.Println()
 
-	// Step 9: Decrypt results
+	// Step 8: Decrypt results
 	fmt.Println("🔓 Decrypting result...")
 	decStart := time.Now()
 
@@ -148,7 +144,7 @@ func main() {
 	fmt.Printf("   Decrypted nibbles in %v\n", decDuration)
 	fmt.Println()
 
-	// Step 10: Combine nibbles into final result
+	// Step 9: Combine nibbles into final result
 	result := uint8(sumLow | (sumHigh << 4))
 
 	fmt.Println("═══════════════════════════════════════════════════════════════")
@@ -174,10 +170,9 @@ func main() {
 	fmt.Printf("Key Generation:  %v\n", keyDuration)
 	fmt.Printf("LUT Generation:  %v\n", lutDuration)
 	fmt.Printf("Encryption:      %v (4 nibbles)\n", encDuration)
-	fmt.Printf("Addition:        %v (3 bootstraps)\n", addDuration)
-	fmt.Printf("  - Bootstrap 1: %v (low sum)\n", pbs1Duration)
-	fmt.Printf("  - Bootstrap 2: %v (carry)\n", pbs2Duration)
-	fmt.Printf("  - Bootstrap 3: %v (high sum)\n", pbs3Duration)
+	fmt.Printf("Addition:        %v (2 bootstraps)\n", addDuration)
+	fmt.Printf("  - Bootstrap 1: %v (low sum and carry)\n", pbs1Duration)
+	fmt.Printf("  - Bootstrap 2: %v (high sum)\n", pbs2Duration)
 	fmt.Printf("Decryption:      %v\n", decDuration)
 	fmt.Println()
 
@@ -0,0 +1,311 @@
+package gates
+
+import (
+	"github.com/thedonutfactory/go-tfhe/cloudkey"
+	"github.com/thedonutfactory/go-tfhe/evaluator"
+	"github.com/thedonutfactory/go-tfhe/tlwe"
+	"github.com/thedonutfactory/go-tfhe/utils"
+)
+
+// Evaluator evaluates gates with one cloud key and its own evaluation buffers.
+//
+// Gates with a trivial input (a public value, see Constant and TrivialValue)
+// are short-circuited without bootstrapping: AND with false is the constant
+// false, XOR with true is a NOT, and so on.
+//
+// An Evaluator is not safe for concurrent use. To run gates from several
+// goroutines, give each goroutine its own instance with ShallowCopy, which
+// shares the (read-only) cloud key and allocates fresh buffers:
+//
+//	base := gates.NewEvaluator(ck)
+//	for _, job := range jobs {
+//		go func(e *gates.Evaluator) {
+//			job.out = e.AND(job.a, job.b)
+//		}(base.ShallowCopy())
+//	}
+//
+// The package-level gate functions share one evaluator per parameter set
+// behind a lock, so they are safe to call concurrently but do not run in
+// parallel.
+type Evaluator struct {
+	CloudKey *cloudkey.CloudKey
+
+	eval *evaluator.Evaluator
+}
+
+// NewEvaluator creates an evaluator for the cloud key
+func NewEvaluator(ck *cloudkey.CloudKey) *Evaluator {
+	return &Evaluator{
+		CloudKey: ck,
+		eval:     evaluator.NewEvaluatorWithParams(ck.Params),
+	}
+}
+
+// ShallowCopy returns an evaluator sharing the cloud key of e with its own
+// buffers, for use in another goroutine
+func (e *Evaluator) ShallowCopy() *Evaluator {
+	return &Evaluator{
+		CloudKey: e.CloudKey,
+		eval:     e.eval.ShallowCopy(),
+	}
+}
+
+// bootstrap performs full bootstrapping with key switching and copies the
+// result out of the evaluator's buffers
+func (e *Evaluator) bootstrap(ctxt *Ciphertext) *Ciphertext {
+	ck := e.CloudKey
+	result := tlwe.NewTLWELv0WithParams(ck.Params)
+	bootstrapped := e.eval.Bootstrap(ctxt, ck.BlindRotateTestvec, ck.BootstrappingKey, ck.KeySwitchingKey, ck.DecompositionOffset)
+	result.CopyFrom(bootstrapped)
+	return result
+}
+
+// NAND performs homomorphic NAND operation
+func (e *Evaluator) NAND(tlweA, tlweB *Ciphertext) *Ciphertext {
+	if result, ok := shortCircuit(clearNAND, tlweA, tlweB); ok {
+		return result
+	}
+	return e.bootstrap(e.eval.PrepareNAND(tlweA, tlweB))
+}
+
+// OR performs homomorphic OR operation
+func (e *Evaluator) OR(tlweA, tlweB *Ciphertext) *Ciphertext {
+	if result, ok := shortCircuit(clearOR, tlweA, tlweB); ok {
+		return result
+	}
+	return e.bootstrap(e.eval.PrepareOR(tlweA, tlweB))
+}
+
+// AND performs homomorphic AND operation
+func (e *Evaluator) AND(tlweA, tlweB *Ciphertext) *Ciphertext {
+	if result, ok := shortCircuit(clearAND, tlweA, tlweB); ok {
+		return result
+	}
+	return e.bootstrap(e.eval.PrepareAND(tlweA, tlweB))
+}
+
+// XOR performs homomorphic XOR operation
+func (e *Evaluator) XOR(tlweA, tlweB *Ciphertext) *Ciphertext {
+	if result, ok := shortCircuit(clearXOR, tlweA, tlweB); ok {
+		return result
+	}
+	return e.bootstrap(e.eval.PrepareXOR(tlweA, tlweB))
+}
+
+// XNOR performs homomorphic XNOR operation
+func (e *Evaluator) XNOR(tlweA, tlweB *Ciphertext) *Ciphertext {
+	if result, ok := shortCircuit(clearXNOR, tlweA, tlweB); ok {
+		return result
+	}
+	tlweXNOR := tlweA.SubMul(tlweB, 2)
+	// NOTE: Go implementation uses +0.25 instead of -0.25 (inverted from Rust)
+	// This may be due to FFT library differences
+	tlweXNOR.SetB(tlweXNOR.B() + utils.F64ToTorus(0.25))
+	return e.bootstrap(tlweXNOR)
+}
+
+// NOR performs homomorphic NOR operation
+func (e *Evaluator) NOR(tlweA, tlweB *Ciphertext) *Ciphertext {
+	if result, ok := shortCircuit(clearNOR, tlweA, tlweB); ok {
+		return result
+	}
+	tlweNOR := tlweA.Add(tlweB).Neg()
+	tlweNOR.SetB(tlweNOR.B() + utils.F64ToTorus(-0.125))
+	return e.bootstrap(tlweNOR)
+}
+
+// ANDNY performs homomorphic AND-NOT-Y operation (NOT(a) AND b)
+func (e *Evaluator) ANDNY(tlweA, tlweB *Ciphertext) *Ciphertext {
+	if result, ok := shortCircuit(clearANDNY, tlweA, tlweB); ok {
+		return result
+	}
+	tlweANDNY := tlweA.Neg().Add(tlweB)
+	tlweANDNY.SetB(tlweANDNY.B() + utils.F64ToTorus(-0.125))
+	return e.bootstrap(tlweANDNY)
+}
+
+// ANDYN performs homomorphic AND-Y-NOT operation (a AND NOT(b))
+func (e *Evaluator) ANDYN(tlweA, tlweB *Ciphertext) *Ciphertext {
+	if result, ok := shortCircuit(clearANDYN, tlweA, tlweB); ok {
+		return result
+	}
+	tlweANDYN := tlweA.Sub(tlweB)
+	tlweANDYN.SetB(tlweANDYN.B() + utils.F64ToTorus(-0.125))
+	return e.bootstrap(tlweANDYN)
+}
+
+// ORNY performs homomorphic OR-NOT-Y operation (NOT(a) OR b)
+func (e *Evaluator) ORNY(tlweA, tlweB *Ciphertext) *Ciphertext {
+	if result, ok := shortCircuit(clearORNY, tlweA, tlweB); ok {
+		return result
+	}
+	tlweORNY := tlweA.Neg().Add(tlweB)
+	tlweORNY.SetB(tlweORNY.B() + utils.F64ToTorus(0.125))
+	return e.bootstrap(tlweORNY)
+}
+
+// ORYN performs homomorphic OR-Y-NOT operation (a OR NOT(b))
+func (e *Evaluator) ORYN(tlweA, tlweB *Ciphertext) *Ciphertext {
+	if result, ok := shortCircuit(clearORYN, tlweA, tlweB); ok {
+		return result
+	}
+	tlweORYN := tlweA.Sub(tlweB)
+	tlweORYN.SetB(tlweORYN.B() + utils.F64ToTorus(0.125))
+	return e.bootstrap(tlweORYN)
+}
+
+// MUX performs homomorphic multiplexer: a?b:c = a*b + NOT(a)*c.
+// It costs two blind rotations and one key switch (see evaluator.MUXAssign),
+// or at most one bootstrap when an input is trivial.
+func (e *Evaluator) MUX(tlweA, tlweB, tlweC *Ciphertext) *Ciphertext {
+	if sel, ok := TrivialValue(tlweA); ok {
+		if sel {
+			return Copy(tlweB)
+		}
+		return Copy(tlweC)
+	}
+	vb, tb := TrivialValue(tlweB)
+	vc, tc := TrivialValue(tlweC)
+	switch {
+	case tb && tc:
+		return unary(vc, vb, tlweA)
+	case tb && vb:
+		return e.OR(tlweA, tlweC)
+	case tb:
+		return e.ANDNY(tlweA, tlweC)
+	case tc && vc:
+		return e.ORNY(tlweA, tlweB)
+	case tc:
+		return e.AND(tlweA, tlweB)
+	}
+
+	ck := e.CloudKey
+	result := tlwe.NewTLWELv0WithParams(ck.Params)
+	e.eval.MUXAssign(tlweA, tlweB, tlweC, ck.BlindRotateTestvec, ck.BootstrappingKey, ck.KeySwitchingKey, ck.DecompositionOffset, result)
+	return result
+}
+
+// MAJ performs homomorphic majority: true when at least two of a, b and c
+// are. It costs one bootstrap (see evaluator.PrepareMAJ), so the carry of a
+// full adder is a single gate.
+func (e *Evaluator) MAJ(tlweA, tlweB, tlweC *Ciphertext) *Ciphertext {
+	if result, ok := e.shortCircuit3(clearMAJ, tlweA, tlweB, tlweC); ok {
+		return result
+	}
+	return e.bootstrap(e.eval.PrepareMAJ(tlweA, tlweB, tlweC))
+}
+
+// XOR3 performs homomorphic three-input XOR with one bootstrap
+// (see evaluator.PrepareXOR3)
+func (e *Evaluator) XOR3(tlweA, tlweB, tlweC *Ciphertext) *Ciphertext {
+	if result, ok := e.shortCircuit3(clearXOR3, tlweA, tlweB, tlweC); ok {
+		return result
+	}
+	return e.bootstrap(e.eval.PrepareXOR3(tlweA, tlweB, tlweC))
+}
+
+// AND3 performs homomorphic three-input AND.
+//
+// Unlike MAJ and XOR3 it costs two bootstraps: the sum of three ±1/8 inputs
+// spans more than half the torus, and the negacyclic test vector (whose
+// output flips sign every half turn) cannot single out one of its four
+// values. The same holds for OR3.
+func (e *Evaluator) AND3(tlweA, tlweB, tlweC *Ciphertext) *Ciphertext {
+	if result, ok := e.shortCircuit3(clearAND3, tlweA, tlweB, tlweC); ok {
+		return result
+	}
+	return e.AND(e.AND(tlweA, tlweB), tlweC)
+}
+
+// OR3 performs homomorphic three-input OR with two bootstraps (see AND3)
+func (e *Evaluator) OR3(tlweA, tlweB, tlweC *Ciphertext) *Ciphertext {
+	if result, ok := e.shortCircuit3(clearOR3, tlweA, tlweB, tlweC); ok {
+		return result
+	}
+	return e.OR(e.OR(tlweA, tlweB), tlweC)
+}
+
+// NOT performs homomorphic NOT operation (no bootstrapping)
+func (e *Evaluator) NOT(tlweA *Ciphertext) *Ciphertext {
+	return NOT(tlweA)
+}
+
+// Constant creates a constant encrypted value for the evaluator's parameter set
+func (e *Evaluator) Constant(value bool) *Ciphertext {
+	return ConstantWithParams(value, e.CloudKey.Params)
+}
+
+// BatchNAND performs NAND operations on many inputs in parallel
+func (e *Evaluator) BatchNAND(inputs [][2]*Ciphertext) []*Ciphertext {
+	return BatchNAND(inputs, e.CloudKey)
+}
+
+// BatchAND performs AND operations on many inputs in parallel
+func (e *Evaluator) BatchAND(inputs [][2]*Ciphertext) []*Ciphertext {
+	return BatchAND(inputs, e.CloudKey)
+}
+
+// BatchOR performs OR operations on many inputs in parallel
+func (e *Evaluator) BatchOR(inputs [][2]*Ciphertext) []*Ciphertext {
+	return BatchOR(inputs, e.CloudKey)
+}
+
+// BatchXOR performs XOR operations on many inputs in parallel
+func (e *Evaluator) BatchXOR(inputs [][2]*Ciphertext) []*Ciphertext {
+	return BatchXOR(inputs, e.CloudKey)
+}
+
+// BatchNOR performs NOR operations on many inputs in parallel
+func (e *Evaluator) BatchNOR(inputs [][2]*Ciphertext) []*Ciphertext {
+	return BatchNOR(inputs, e.CloudKey)
+}
+
+// BatchXNOR performs XNOR operations on many inputs in parallel
+func (e *Evaluator) BatchXNOR(inputs [][2]*Ciphertext) []*Ciphertext {
+	return BatchXNOR(inputs, e.CloudKey)
+}
+
+// BatchANDNY performs NOT(a) AND b operations on many inputs in parallel
+func (e *Evaluator) BatchANDNY(inputs [][2]*Ciphertext) []*Ciphertext {
+	return BatchANDNY(inputs, e.CloudKey)
+}
+
+// BatchANDYN performs a AND NOT(b) operations on many inputs in parallel
+func (e *Evaluator) BatchANDYN(inputs [][2]*Ciphertext) []*Ciphertext {
+	return BatchANDYN(inputs, e.CloudKey)
+}
+
+// BatchORNY performs NOT(a) OR b operations on many inputs in parallel
+func (e *Evaluator) BatchORNY(inputs [][2]*Ciphertext) []*Ciphertext {
+	return BatchORNY(inputs, e.CloudKey)
+}
+
+// BatchORYN performs a OR NOT(b) operations on many inputs in parallel
+func (e *Evaluator) BatchORYN(inputs [][2]*Ciphertext) []*Ciphertext {
+	return BatchORYN(inputs, e.CloudKey)
+}
+
+// BatchMUX performs MUX operations on many inputs in parallel
+func (e *Evaluator) BatchMUX(inputs [][3]*Ciphertext) []*Ciphertext {
+	return BatchMUX(inputs, e.CloudKey)
+}
+
+// BatchMAJ performs majority operations on many inputs in parallel
+func (e *Evaluator) BatchMAJ(inputs [][3]*Ciphertext) []*Ciphertext {
+	return BatchMAJ(inputs, e.CloudKey)
+}
+
+// BatchXOR3 performs three-input XOR operations on many inputs in parallel
+func (e *Evaluator) BatchXOR3(inputs [][3]*Ciphertext) []*Ciphertext {
+	return BatchXOR3(inputs, e.CloudKey)
+}
+
+// BatchAND3 performs three-input AND operations on many inputs in parallel
+func (e *Evaluator) BatchAND3(inputs [][3]*Ciphertext) []*Ciphertext {
+	return BatchAND3(inputs, e.CloudKey)
+}
+
+// BatchOR3 performs three-input OR operations on many inputs in parallel
+func (e *Evaluator) BatchOR3(inputs [][3]*Ciphertext) []*Ciphertext {
+	return BatchOR3(inputs, e.CloudKey)
+}
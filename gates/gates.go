@@ -15,210 +15,236 @@ import (
 // Ciphertext is an alias for TLWELv0
 type Ciphertext = tlwe.TLWELv0
 
-// Global evaluator for single-threaded operations (zero-allocation)
-var globalEval *evaluator.Evaluator
+// sharedEvaluator is the evaluator behind the package-level gates for one
+// parameter set. The lock makes those gates safe to call from several
+// goroutines; they are serialized, so use an Evaluator per goroutine to run
+// gates in parallel.
+type sharedEvaluator struct {
+	mu   sync.Mutex
+	eval *evaluator.Evaluator
+}
+
+// Shared evaluators, one per parameter set, created on first use
+var (
+	evalMu sync.Mutex
+	evals  = make(map[params.Parameters]*sharedEvaluator)
+)
+
+// shared runs gate with the shared evaluator for the cloud key's parameter set
+func shared(ck *cloudkey.CloudKey, gate func(e *Evaluator) *Ciphertext) *Ciphertext {
+	evalMu.Lock()
+	s, ok := evals[ck.Params]
+	if !ok {
+		s = &sharedEvaluator{eval: evaluator.NewEvaluatorWithParams(ck.Params)}
+		evals[ck.Params] = s
+	}
+	evalMu.Unlock()
 
-func init() {
-	globalEval = evaluator.NewEvaluator(params.GetTRGSWLv1().N)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return gate(&Evaluator{CloudKey: ck, eval: s.eval})
 }
 
-// NAND performs homomorphic NAND operation (zero-allocation)
+// NAND performs homomorphic NAND operation
 func NAND(tlweA, tlweB *Ciphertext, ck *cloudkey.CloudKey) *Ciphertext {
-	prepared := globalEval.PrepareNAND(tlweA, tlweB)
-	result := bootstrap(prepared, ck)
-
-	return result
+	return shared(ck, func(e *Evaluator) *Ciphertext { return e.NAND(tlweA, tlweB) })
 }
 
-// OR performs homomorphic OR operation (zero-allocation)
+// OR performs homomorphic OR operation
 func OR(tlweA, tlweB *Ciphertext, ck *cloudkey.CloudKey) *Ciphertext {
-	prepared := globalEval.PrepareOR(tlweA, tlweB)
-	return bootstrap(prepared, ck)
+	return shared(ck, func(e *Evaluator) *Ciphertext { return e.OR(tlweA, tlweB) })
 }
 
-// AND performs homomorphic AND operation (zero-allocation)
+// AND performs homomorphic AND operation
 func AND(tlweA, tlweB *Ciphertext, ck *cloudkey.CloudKey) *Ciphertext {
-	prepared := globalEval.PrepareAND(tlweA, tlweB)
-	return bootstrap(prepared, ck)
+	return shared(ck, func(e *Evaluator) *Ciphertext { return e.AND(tlweA, tlweB) })
 }
 
-// XOR performs homomorphic XOR operation (zero-allocation)
+// XOR performs homomorphic XOR operation
 func XOR(tlweA, tlweB *Ciphertext, ck *cloudkey.CloudKey) *Ciphertext {
-	prepared := globalEval.PrepareXOR(tlweA, tlweB)
-	return bootstrap(prepared, ck)
+	return shared(ck, func(e *Evaluator) *Ciphertext { return e.XOR(tlweA, tlweB) })
 }
 
 // XNOR performs homomorphic XNOR operation
 func XNOR(tlweA, tlweB *Ciphertext, ck *cloudkey.CloudKey) *Ciphertext {
-	tlweXNOR := tlweA.SubMul(tlweB, 2)
-	// NOTE: Go implementation uses +0.25 instead of -0.25 (inverted from Rust)
-	// This may be due to FFT library differences
-	tlweXNOR.SetB(tlweXNOR.B() + utils.F64ToTorus(0.25))
-	return bootstrap(tlweXNOR, ck)
+	return shared(ck, func(e *Evaluator) *Ciphertext { return e.XNOR(tlweA, tlweB) })
 }
 
-// Constant creates a constant encrypted value
+// Constant creates a constant encrypted value for the current security level.
+// The result is a trivial ciphertext (see TrivialValue): gates short-circuit
+// on it instead of bootstrapping.
 func Constant(value bool) *Ciphertext {
+	return ConstantWithParams(value, params.Current())
+}
+
+// ConstantWithParams creates a constant encrypted value (a trivial
+// ciphertext) for parameter set p
+func ConstantWithParams(value bool, p params.Parameters) *Ciphertext {
 	mu := utils.F64ToTorus(0.125)
 	if !value {
 		mu = 1 - mu
 	}
-	result := tlwe.NewTLWELv0()
+	result := tlwe.NewTLWELv0WithParams(p)
 	result.SetB(mu)
 	return result
 }
 
 // NOR performs homomorphic NOR operation
 func NOR(tlweA, tlweB *Ciphertext, ck *cloudkey.CloudKey) *Ciphertext {
-	tlweNOR := tlweA.Add(tlweB).Neg()
-	tlweNOR.SetB(tlweNOR.B() + utils.F64ToTorus(-0.125))
-	return bootstrap(tlweNOR, ck)
+	return shared(ck, func(e *Evaluator) *Ciphertext { return e.NOR(tlweA, tlweB) })
 }
 
 // ANDNY performs homomorphic AND-NOT-Y operation (NOT(a) AND b)
 func ANDNY(tlweA, tlweB *Ciphertext, ck *cloudkey.CloudKey) *Ciphertext {
-	tlweANDNY := tlweA.Neg().Add(tlweB)
-	tlweANDNY.SetB(tlweANDNY.B() + utils.F64ToTorus(-0.125))
-	return bootstrap(tlweANDNY, ck)
+	return shared(ck, func(e *Evaluator) *Ciphertext { return e.ANDNY(tlweA, tlweB) })
 }
 
 // ANDYN performs homomorphic AND-Y-NOT operation (a AND NOT(b))
 func ANDYN(tlweA, tlweB *Ciphertext, ck *cloudkey.CloudKey) *Ciphertext {
-	tlweANDYN := tlweA.Sub(tlweB)
-	tlweANDYN.SetB(tlweANDYN.B() + utils.F64ToTorus(-0.125))
-	return bootstrap(tlweANDYN, ck)
+	return shared(ck, func(e *Evaluator) *Ciphertext { return e.ANDYN(tlweA, tlweB) })
 }
 
 // ORNY performs homomorphic OR-NOT-Y operation (NOT(a) OR b)
 func ORNY(tlweA, tlweB *Ciphertext, ck *cloudkey.CloudKey) *Ciphertext {
-	tlweORNY := tlweA.Neg().Add(tlweB)
-	tlweORNY.SetB(tlweORNY.B() + utils.F64ToTorus(0.125))
-	return bootstrap(tlweORNY, ck)
+	return shared(ck, func(e *Evaluator) *Ciphertext { return e.ORNY(tlweA, tlweB) })
 }
 
 // ORYN performs homomorphic OR-Y-NOT operation (a OR NOT(b))
 func ORYN(tlweA, tlweB *Ciphertext, ck *cloudkey.CloudKey) *Ciphertext {
-	tlweORYN := tlweA.Sub(tlweB)
-	tlweORYN.SetB(tlweORYN.B() + utils.F64ToTorus(0.125))
-	return bootstrap(tlweORYN, ck)
+	return shared(ck, func(e *Evaluator) *Ciphertext { return e.ORYN(tlweA, tlweB) })
 }
 
 // MUX performs homomorphic multiplexer: a?b:c = a*b + NOT(a)*c
 func MUX(tlweA, tlweB, tlweC *Ciphertext, ck *cloudkey.CloudKey) *Ciphertext {
-	// Compute using regular AND and OR gates
-	// This is more reliable than the optimized version with bootstrap_without_key_switch
-	andAB := AND(tlweA, tlweB, ck)
-	notA := NOT(tlweA)
-	andNotAC := AND(notA, tlweC, ck)
-	return OR(andAB, andNotAC, ck)
+	return shared(ck, func(e *Evaluator) *Ciphertext { return e.MUX(tlweA, tlweB, tlweC) })
 }
 
-// NOT performs homomorphic NOT operation
-func NOT(tlweA *Ciphertext) *Ciphertext {
-	return tlweA.Neg()
+// MAJ performs homomorphic majority of three inputs with one bootstrap
+func MAJ(tlweA, tlweB, tlweC *Ciphertext, ck *cloudkey.CloudKey) *Ciphertext {
+	return shared(ck, func(e *Evaluator) *Ciphertext { return e.MAJ(tlweA, tlweB, tlweC) })
 }
 
-// Copy copies a ciphertext
-func Copy(tlweA *Ciphertext) *Ciphertext {
-	result := tlwe.NewTLWELv0()
-	copy(result.P, tlweA.P)
-	return result
+// XOR3 performs homomorphic three-input XOR with one bootstrap
+func XOR3(tlweA, tlweB, tlweC *Ciphertext, ck *cloudkey.CloudKey) *Ciphertext {
+	return shared(ck, func(e *Evaluator) *Ciphertext { return e.XOR3(tlweA, tlweB, tlweC) })
 }
 
-// bootstrap performs full bootstrapping with key switching (TRUE zero-allocation)
-// Returns pointer to internal buffer - result is only valid until next bootstrap call
-func bootstrapTemp(ctxt *Ciphertext, ck *cloudkey.CloudKey) *Ciphertext {
-	return globalEval.Bootstrap(ctxt, ck.BlindRotateTestvec, ck.BootstrappingKey, ck.KeySwitchingKey, ck.DecompositionOffset)
+// AND3 performs homomorphic three-input AND with two bootstraps
+func AND3(tlweA, tlweB, tlweC *Ciphertext, ck *cloudkey.CloudKey) *Ciphertext {
+	return shared(ck, func(e *Evaluator) *Ciphertext { return e.AND3(tlweA, tlweB, tlweC) })
 }
 
-// bootstrap2 performs full bootstrapping with key switching (zero-allocation)
-// copies the prepared buffer to the result
-func bootstrap(ctxt *Ciphertext, ck *cloudkey.CloudKey) *Ciphertext {
-	result := tlwe.NewTLWELv0()
-	bootstrapped := globalEval.Bootstrap(ctxt, ck.BlindRotateTestvec, ck.BootstrappingKey, ck.KeySwitchingKey, ck.DecompositionOffset)
-	copy(result.P, bootstrapped.P)
-	result.SetB(bootstrapped.B())
-	return result
+// OR3 performs homomorphic three-input OR with two bootstraps
+func OR3(tlweA, tlweB, tlweC *Ciphertext, ck *cloudkey.CloudKey) *Ciphertext {
+	return shared(ck, func(e *Evaluator) *Ciphertext { return e.OR3(tlweA, tlweB, tlweC) })
+}
+
+// NOT performs homomorphic NOT operation
+func NOT(tlweA *Ciphertext) *Ciphertext {
+	return tlweA.Neg()
 }
 
-// bootstrapWithoutKeySwitch performs bootstrapping without key switching (uses global eval)
-func bootstrapWithoutKeySwitch(ctxt *Ciphertext, ck *cloudkey.CloudKey) *Ciphertext {
-	trlweResult := trlwe.NewTRLWELv1()
-	globalEval.BlindRotateAssign(ctxt, ck.BlindRotateTestvec, ck.BootstrappingKey, ck.DecompositionOffset, trlweResult)
-	return trlwe.SampleExtractIndex2(trlweResult, 0)
+// Copy copies a ciphertext
+func Copy(tlweA *Ciphertext) *Ciphertext {
+	result := &Ciphertext{P: make([]params.Torus, len(tlweA.P)), Params: tlweA.Params}
+	result.CopyFrom(tlweA)
+	return result
 }
 
 // ============================================================================
 // BATCH GATE OPERATIONS - Parallel Processing
 // ============================================================================
 
-// BatchNAND performs batch NAND operations in parallel
-func BatchNAND(inputs [][2]*Ciphertext, ck *cloudkey.CloudKey) []*Ciphertext {
+// batchBootstrap evaluates a two-input gate on every pair in parallel.
+// Pairs with a trivial input are short-circuited without bootstrapping.
+func batchBootstrap(inputs [][2]*Ciphertext, ck *cloudkey.CloudKey, clear truthTable, prepare func(a, b *Ciphertext) *Ciphertext) []*Ciphertext {
+	results := make([]*Ciphertext, len(inputs))
+
 	// Step 1: Prepare all inputs for bootstrapping
-	prepared := make([]*Ciphertext, len(inputs))
+	var prepared []*Ciphertext
+	var indices []int
 	for i, pair := range inputs {
-		tlweNAND := pair[0].Add(pair[1]).Neg()
-		tlweNAND.SetB(tlweNAND.B() + utils.F64ToTorus(0.125))
-		prepared[i] = tlweNAND
+		if result, ok := shortCircuit(clear, pair[0], pair[1]); ok {
+			results[i] = result
+			continue
+		}
+		prepared = append(prepared, prepare(pair[0], pair[1]))
+		indices = append(indices, i)
 	}
+	bootstrapAll(prepared, indices, ck, results)
+	return results
+}
 
-	// Step 2: Batch blind rotate (bottleneck - parallelized)
-	trlwes := trgsw.BatchBlindRotate(prepared, ck.BlindRotateTestvec, ck.BootstrappingKey, ck.DecompositionOffset)
-
-	// Step 3: Post-process (sample extract + key switching, parallel)
-	results := make([]*Ciphertext, len(trlwes))
-	var wg sync.WaitGroup
-	for i, trlweResult := range trlwes {
-		wg.Add(1)
-		go func(idx int, t *trlwe.TRLWELv1) {
-			defer wg.Done()
-			tlweLv1 := trlwe.SampleExtractIndex(t, 0)
-			results[idx] = trgsw.IdentityKeySwitching(tlweLv1, ck.KeySwitchingKey)
-		}(i, trlweResult)
+// batchBootstrap3 evaluates a three-input gate with one bootstrap on every
+// triple in parallel. Triples with a trivial input go through gate, which
+// short-circuits them, also in parallel.
+func batchBootstrap3(inputs [][3]*Ciphertext, ck *cloudkey.CloudKey, gate func(e *Evaluator, a, b, c *Ciphertext) *Ciphertext, prepare func(a, b, c *Ciphertext) *Ciphertext) []*Ciphertext {
+	results := make([]*Ciphertext, len(inputs))
+
+	var prepared []*Ciphertext
+	var indices []int
+	var trivial []int
+	for i, in := range inputs {
+		if in[0].IsTrivial() || in[1].IsTrivial() || in[2].IsTrivial() {
+			trivial = append(trivial, i)
+			continue
+		}
+		prepared = append(prepared, prepare(in[0], in[1], in[2]))
+		indices = append(indices, i)
 	}
-	wg.Wait()
-
+	evalAll(trivial, ck, results, func(e *Evaluator, i int) *Ciphertext {
+		return gate(e, inputs[i][0], inputs[i][1], inputs[i][2])
+	})
+	bootstrapAll(prepared, indices, ck, results)
 	return results
 }
 
-// BatchAND performs batch AND operations in parallel
-func BatchAND(inputs [][2]*Ciphertext, ck *cloudkey.CloudKey) []*Ciphertext {
-	prepared := make([]*Ciphertext, len(inputs))
-	for i, pair := range inputs {
-		tlweAND := pair[0].Add(pair[1])
-		tlweAND.SetB(tlweAND.B() + utils.F64ToTorus(-0.125))
-		prepared[i] = tlweAND
-	}
+// Evaluator pools for evalAll, one per parameter set
+var evalPools = make(map[params.Parameters]*sync.Pool)
 
-	trlwes := trgsw.BatchBlindRotate(prepared, ck.BlindRotateTestvec, ck.BootstrappingKey, ck.DecompositionOffset)
+// evalAll evaluates gate for every index in parallel, each goroutine with a
+// pooled evaluator, and stores the results at those indices
+func evalAll(indices []int, ck *cloudkey.CloudKey, results []*Ciphertext, gate func(e *Evaluator, i int) *Ciphertext) {
+	if len(indices) == 0 {
+		return
+	}
+	evalMu.Lock()
+	pool, ok := evalPools[ck.Params]
+	if !ok {
+		p := ck.Params
+		pool = &sync.Pool{New: func() any { return evaluator.NewEvaluatorWithParams(p) }}
+		evalPools[p] = pool
+	}
+	evalMu.Unlock()
 
-	results := make([]*Ciphertext, len(trlwes))
 	var wg sync.WaitGroup
-	for i, trlweResult := range trlwes {
+	for _, i := range indices {
 		wg.Add(1)
-		go func(idx int, t *trlwe.TRLWELv1) {
+		go func(i int) {
 			defer wg.Done()
-			tlweLv1 := trlwe.SampleExtractIndex(t, 0)
-			results[idx] = trgsw.IdentityKeySwitching(tlweLv1, ck.KeySwitchingKey)
-		}(i, trlweResult)
+			eval := pool.Get().(*evaluator.Evaluator)
+			defer pool.Put(eval)
+			results[i] = gate(&Evaluator{CloudKey: ck, eval: eval}, i)
+		}(i)
 	}
 	wg.Wait()
-
-	return results
 }
 
-// BatchOR performs batch OR operations in parallel
-func BatchOR(inputs [][2]*Ciphertext, ck *cloudkey.CloudKey) []*Ciphertext {
-	prepared := make([]*Ciphertext, len(inputs))
-	for i, pair := range inputs {
-		tlweOR := pair[0].Add(pair[1])
-		tlweOR.SetB(tlweOR.B() + utils.F64ToTorus(0.125))
-		prepared[i] = tlweOR
+// bootstrapAll bootstraps the prepared ciphertexts in parallel and stores
+// them in results at the given indices
+func bootstrapAll(prepared []*Ciphertext, indices []int, ck *cloudkey.CloudKey, results []*Ciphertext) {
+	if len(prepared) == 0 {
+		return
+	}
+
+	for _, ct := range prepared {
+		evaluator.CheckBootstrapInput(ck.Params, ct, evaluator.GateNoiseMargin)
 	}
 
+	// Step 2: Batch blind rotate (bottleneck - parallelized)
 	trlwes := trgsw.BatchBlindRotate(prepared, ck.BlindRotateTestvec, ck.BootstrappingKey, ck.DecompositionOffset)
 
-	results := make([]*Ciphertext, len(trlwes))
+	// Step 3: Post-process (sample extract + key switching, parallel)
 	var wg sync.WaitGroup
 	for i, trlweResult := range trlwes {
 		wg.Add(1)
@@ -226,85 +252,195 @@ func BatchOR(inputs [][2]*Ciphertext, ck *cloudkey.CloudKey) []*Ciphertext {
 			defer wg.Done()
 			tlweLv1 := trlwe.SampleExtractIndex(t, 0)
 			results[idx] = trgsw.IdentityKeySwitching(tlweLv1, ck.KeySwitchingKey)
-		}(i, trlweResult)
+			results[idx].Variance = ck.Params.BootstrapVariance()
+		}(indices[i], trlweResult)
 	}
 	wg.Wait()
+}
 
-	return results
+// BatchNAND performs batch NAND operations in parallel
+func BatchNAND(inputs [][2]*Ciphertext, ck *cloudkey.CloudKey) []*Ciphertext {
+	return batchBootstrap(inputs, ck, clearNAND, func(a, b *Ciphertext) *Ciphertext {
+		tlweNAND := a.Add(b).Neg()
+		tlweNAND.SetB(tlweNAND.B() + utils.F64ToTorus(0.125))
+		return tlweNAND
+	})
+}
+
+// BatchAND performs batch AND operations in parallel
+func BatchAND(inputs [][2]*Ciphertext, ck *cloudkey.CloudKey) []*Ciphertext {
+	return batchBootstrap(inputs, ck, clearAND, func(a, b *Ciphertext) *Ciphertext {
+		tlweAND := a.Add(b)
+		tlweAND.SetB(tlweAND.B() + utils.F64ToTorus(-0.125))
+		return tlweAND
+	})
+}
+
+// BatchOR performs batch OR operations in parallel
+func BatchOR(inputs [][2]*Ciphertext, ck *cloudkey.CloudKey) []*Ciphertext {
+	return batchBootstrap(inputs, ck, clearOR, func(a, b *Ciphertext) *Ciphertext {
+		tlweOR := a.Add(b)
+		tlweOR.SetB(tlweOR.B() + utils.F64ToTorus(0.125))
+		return tlweOR
+	})
 }
 
 // BatchXOR performs batch XOR operations in parallel
 func BatchXOR(inputs [][2]*Ciphertext, ck *cloudkey.CloudKey) []*Ciphertext {
-	prepared := make([]*Ciphertext, len(inputs))
-	for i, pair := range inputs {
-		tlweXOR := pair[0].AddMul(pair[1], 2)
+	return batchBootstrap(inputs, ck, clearXOR, func(a, b *Ciphertext) *Ciphertext {
+		tlweXOR := a.AddMul(b, 2)
 		tlweXOR.SetB(tlweXOR.B() + utils.F64ToTorus(0.25))
-		prepared[i] = tlweXOR
-	}
-
-	trlwes := trgsw.BatchBlindRotate(prepared, ck.BlindRotateTestvec, ck.BootstrappingKey, ck.DecompositionOffset)
-
-	results := make([]*Ciphertext, len(trlwes))
-	var wg sync.WaitGroup
-	for i, trlweResult := range trlwes {
-		wg.Add(1)
-		go func(idx int, t *trlwe.TRLWELv1) {
-			defer wg.Done()
-			tlweLv1 := trlwe.SampleExtractIndex(t, 0)
-			results[idx] = trgsw.IdentityKeySwitching(tlweLv1, ck.KeySwitchingKey)
-		}(i, trlweResult)
-	}
-	wg.Wait()
-
-	return results
+		return tlweXOR
+	})
 }
 
 // BatchNOR performs batch NOR operations in parallel
 func BatchNOR(inputs [][2]*Ciphertext, ck *cloudkey.CloudKey) []*Ciphertext {
-	prepared := make([]*Ciphertext, len(inputs))
-	for i, pair := range inputs {
-		tlweNOR := pair[0].Add(pair[1]).Neg()
+	return batchBootstrap(inputs, ck, clearNOR, func(a, b *Ciphertext) *Ciphertext {
+		tlweNOR := a.Add(b).Neg()
 		tlweNOR.SetB(tlweNOR.B() + utils.F64ToTorus(-0.125))
-		prepared[i] = tlweNOR
-	}
+		return tlweNOR
+	})
+}
 
-	trlwes := trgsw.BatchBlindRotate(prepared, ck.BlindRotateTestvec, ck.BootstrappingKey, ck.DecompositionOffset)
+// BatchXNOR performs batch XNOR operations in parallel
+func BatchXNOR(inputs [][2]*Ciphertext, ck *cloudkey.CloudKey) []*Ciphertext {
+	return batchBootstrap(inputs, ck, clearXNOR, func(a, b *Ciphertext) *Ciphertext {
+		tlweXNOR := a.SubMul(b, 2)
+		tlweXNOR.SetB(tlweXNOR.B() + utils.F64ToTorus(0.25))
+		return tlweXNOR
+	})
+}
 
-	results := make([]*Ciphertext, len(trlwes))
-	var wg sync.WaitGroup
-	for i, trlweResult := range trlwes {
-		wg.Add(1)
-		go func(idx int, t *trlwe.TRLWELv1) {
-			defer wg.Done()
-			tlweLv1 := trlwe.SampleExtractIndex(t, 0)
-			results[idx] = trgsw.IdentityKeySwitching(tlweLv1, ck.KeySwitchingKey)
-		}(i, trlweResult)
-	}
-	wg.Wait()
+// BatchANDNY performs batch NOT(a) AND b operations in parallel
+func BatchANDNY(inputs [][2]*Ciphertext, ck *cloudkey.CloudKey) []*Ciphertext {
+	return batchBootstrap(inputs, ck, clearANDNY, func(a, b *Ciphertext) *Ciphertext {
+		tlweANDNY := a.Neg().Add(b)
+		tlweANDNY.SetB(tlweANDNY.B() + utils.F64ToTorus(-0.125))
+		return tlweANDNY
+	})
+}
 
-	return results
+// BatchANDYN performs batch a AND NOT(b) operations in parallel
+func BatchANDYN(inputs [][2]*Ciphertext, ck *cloudkey.CloudKey) []*Ciphertext {
+	return batchBootstrap(inputs, ck, clearANDYN, func(a, b *Ciphertext) *Ciphertext {
+		tlweANDYN := a.Sub(b)
+		tlweANDYN.SetB(tlweANDYN.B() + utils.F64ToTorus(-0.125))
+		return tlweANDYN
+	})
 }
 
-// BatchXNOR performs batch XNOR operations in parallel
-func BatchXNOR(inputs [][2]*Ciphertext, ck *cloudkey.CloudKey) []*Ciphertext {
-	prepared := make([]*Ciphertext, len(inputs))
-	for i, pair := range inputs {
-		tlweXNOR := pair[0].SubMul(pair[1], 2)
-		tlweXNOR.SetB(tlweXNOR.B() + utils.F64ToTorus(-0.25))
-		prepared[i] = tlweXNOR
+// BatchORNY performs batch NOT(a) OR b operations in parallel
+func BatchORNY(inputs [][2]*Ciphertext, ck *cloudkey.CloudKey) []*Ciphertext {
+	return batchBootstrap(inputs, ck, clearORNY, func(a, b *Ciphertext) *Ciphertext {
+		tlweORNY := a.Neg().Add(b)
+		tlweORNY.SetB(tlweORNY.B() + utils.F64ToTorus(0.125))
+		return tlweORNY
+	})
+}
+
+// BatchORYN performs batch a OR NOT(b) operations in parallel
+func BatchORYN(inputs [][2]*Ciphertext, ck *cloudkey.CloudKey) []*Ciphertext {
+	return batchBootstrap(inputs, ck, clearORYN, func(a, b *Ciphertext) *Ciphertext {
+		tlweORYN := a.Sub(b)
+		tlweORYN.SetB(tlweORYN.B() + utils.F64ToTorus(0.125))
+		return tlweORYN
+	})
+}
+
+// BatchMAJ performs batch majority operations in parallel (one bootstrap each)
+func BatchMAJ(inputs [][3]*Ciphertext, ck *cloudkey.CloudKey) []*Ciphertext {
+	return batchBootstrap3(inputs, ck, (*Evaluator).MAJ, func(a, b, c *Ciphertext) *Ciphertext {
+		return a.Add(b).Add(c)
+	})
+}
+
+// BatchXOR3 performs batch three-input XOR operations in parallel (one
+// bootstrap each)
+func BatchXOR3(inputs [][3]*Ciphertext, ck *cloudkey.CloudKey) []*Ciphertext {
+	return batchBootstrap3(inputs, ck, (*Evaluator).XOR3, func(a, b, c *Ciphertext) *Ciphertext {
+		tlweXOR3 := a.Add(b).Add(c).Neg()
+		return tlweXOR3.Add(tlweXOR3)
+	})
+}
+
+// BatchAND3 performs batch three-input AND operations in parallel, as two
+// rounds of BatchAND
+func BatchAND3(inputs [][3]*Ciphertext, ck *cloudkey.CloudKey) []*Ciphertext {
+	return batchTwoRounds(inputs, ck, BatchAND)
+}
+
+// BatchOR3 performs batch three-input OR operations in parallel, as two
+// rounds of BatchOR
+func BatchOR3(inputs [][3]*Ciphertext, ck *cloudkey.CloudKey) []*Ciphertext {
+	return batchTwoRounds(inputs, ck, BatchOR)
+}
+
+// batchTwoRounds evaluates an associative gate on every triple as
+// batch(batch(a, b), c)
+func batchTwoRounds(inputs [][3]*Ciphertext, ck *cloudkey.CloudKey, batch func([][2]*Ciphertext, *cloudkey.CloudKey) []*Ciphertext) []*Ciphertext {
+	pairs := make([][2]*Ciphertext, len(inputs))
+	for i, in := range inputs {
+		pairs[i] = [2]*Ciphertext{in[0], in[1]}
+	}
+	for i, ab := range batch(pairs, ck) {
+		pairs[i] = [2]*Ciphertext{ab, inputs[i][2]}
+	}
+	return batch(pairs, ck)
+}
+
+// BatchMUX performs batch MUX operations in parallel.
+// Each input is {a, b, c} for a ? b : c; like MUX, every result costs two
+// blind rotations and one key switch.
+func BatchMUX(inputs [][3]*Ciphertext, ck *cloudkey.CloudKey) []*Ciphertext {
+	results := make([]*Ciphertext, len(inputs))
+
+	// a AND b and NOT(a) AND c for every input, rotated in one batch.
+	// Inputs with a trivial operand take the cheaper path of MUX instead.
+	var prepared []*Ciphertext
+	var indices []int
+	var trivial []int
+	for i, in := range inputs {
+		if in[0].IsTrivial() || in[1].IsTrivial() || in[2].IsTrivial() {
+			trivial = append(trivial, i)
+			continue
+		}
+		tlweAND := in[0].Add(in[1])
+		tlweAND.SetB(tlweAND.B() + utils.F64ToTorus(-0.125))
+
+		tlweANDNY := in[0].Neg().Add(in[2])
+		tlweANDNY.SetB(tlweANDNY.B() + utils.F64ToTorus(-0.125))
+
+		prepared = append(prepared, tlweAND, tlweANDNY)
+		indices = append(indices, i)
+	}
+	evalAll(trivial, ck, results, func(e *Evaluator, i int) *Ciphertext {
+		return e.MUX(inputs[i][0], inputs[i][1], inputs[i][2])
+	})
+	if len(prepared) == 0 {
+		return results
+	}
+	for _, ct := range prepared {
+		evaluator.CheckBootstrapInput(ck.Params, ct, evaluator.GateNoiseMargin)
 	}
 
 	trlwes := trgsw.BatchBlindRotate(prepared, ck.BlindRotateTestvec, ck.BootstrappingKey, ck.DecompositionOffset)
 
-	results := make([]*Ciphertext, len(trlwes))
+	// Sum the two level 1 samples, add 1/8 (OR) and key switch once
 	var wg sync.WaitGroup
-	for i, trlweResult := range trlwes {
+	for i, idx := range indices {
 		wg.Add(1)
-		go func(idx int, t *trlwe.TRLWELv1) {
+		go func(i, idx int) {
 			defer wg.Done()
-			tlweLv1 := trlwe.SampleExtractIndex(t, 0)
-			results[idx] = trgsw.IdentityKeySwitching(tlweLv1, ck.KeySwitchingKey)
-		}(i, trlweResult)
+			sum := trlwe.SampleExtractIndex(trlwes[2*i], 0)
+			other := trlwe.SampleExtractIndex(trlwes[2*i+1], 0)
+			for j := range sum.P {
+				sum.P[j] += other.P[j]
+			}
+			sum.SetB(sum.P[len(sum.P)-1] + utils.F64ToTorus(0.125))
+			results[idx] = trgsw.IdentityKeySwitching(sum, ck.KeySwitchingKey)
+			results[idx].Variance = 2*ck.Params.BlindRotateVariance() + ck.Params.KeySwitchVariance()
+		}(i, idx)
 	}
 	wg.Wait()
 
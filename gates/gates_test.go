@@ -1,9 +1,11 @@
 package gates_test
 
 import (
+	"sync"
 	"testing"
 
 	"github.com/thedonutfactory/go-tfhe/cloudkey"
+	"github.com/thedonutfactory/go-tfhe/evaluator"
 	"github.com/thedonutfactory/go-tfhe/gates"
 	"github.com/thedonutfactory/go-tfhe/key"
 	"github.com/thedonutfactory/go-tfhe/params"
@@ -479,6 +481,295 @@ func TestBatchXOR(t *testing.T) {
 	}
 }
 
+// TestBatchGates checks every two-input batch gate against its clear function
+func TestBatchGates(t *testing.T) {
+	sk := key.NewSecretKey()
+	ck := cloudkey.NewCloudKey(sk)
+
+	testCases := []struct {
+		name  string
+		batch func([][2]*gates.Ciphertext, *cloudkey.CloudKey) []*gates.Ciphertext
+		clear func(a, b bool) bool
+	}{
+		{"BatchNAND", gates.BatchNAND, func(a, b bool) bool { return !(a && b) }},
+		{"BatchNOR", gates.BatchNOR, func(a, b bool) bool { return !(a || b) }},
+		{"BatchXNOR", gates.BatchXNOR, func(a, b bool) bool { return a == b }},
+		{"BatchANDNY", gates.BatchANDNY, func(a, b bool) bool { return !a && b }},
+		{"BatchANDYN", gates.BatchANDYN, func(a, b bool) bool { return a && !b }},
+		{"BatchORNY", gates.BatchORNY, func(a, b bool) bool { return !a || b }},
+		{"BatchORYN", gates.BatchORYN, func(a, b bool) bool { return a || !b }},
+	}
+
+	inputs := make([][2]*gates.Ciphertext, 4)
+	for i := range inputs {
+		inputs[i] = [2]*gates.Ciphertext{encrypt(t, i&1 != 0, sk), encrypt(t, i&2 != 0, sk)}
+	}
+	for _, tc := range testCases {
+		for i, result := range tc.batch(inputs, ck) {
+			a, b := i&1 != 0, i&2 != 0
+			if dec := decrypt(t, result, sk); dec != tc.clear(a, b) {
+				t.Errorf("%s(%v, %v) = %v, expected %v", tc.name, a, b, dec, tc.clear(a, b))
+			}
+		}
+	}
+}
+
+// TestBatchMUX tests batch MUX on every input combination, then feeds the
+// results back in as selectors
+func TestBatchMUX(t *testing.T) {
+	sk := key.NewSecretKey()
+	ck := cloudkey.NewCloudKey(sk)
+
+	inputs := make([][3]*gates.Ciphertext, 8)
+	expected := make([]bool, 8)
+	for i := range inputs {
+		sel, a, b := i&4 != 0, i&2 != 0, i&1 != 0
+		inputs[i] = [3]*gates.Ciphertext{encrypt(t, sel, sk), encrypt(t, a, sk), encrypt(t, b, sk)}
+		expected[i] = b
+		if sel {
+			expected[i] = a
+		}
+	}
+
+	results := gates.BatchMUX(inputs, ck)
+	if len(results) != len(expected) {
+		t.Fatalf("BatchMUX returned %d results, expected %d", len(results), len(expected))
+	}
+	for i, result := range results {
+		if dec := decrypt(t, result, sk); dec != expected[i] {
+			t.Errorf("BatchMUX[%d] = %v, expected %v", i, dec, expected[i])
+		}
+	}
+
+	// Bootstrapped outputs must be usable as inputs
+	yes := gates.Constant(true)
+	no := gates.Constant(false)
+	for i, result := range results {
+		inputs[i] = [3]*gates.Ciphertext{result, no, yes}
+	}
+	for i, result := range gates.BatchMUX(inputs, ck) {
+		if dec := decrypt(t, result, sk); dec != !expected[i] {
+			t.Errorf("BatchMUX[%d] on a MUX output = %v, expected %v", i, dec, !expected[i])
+		}
+	}
+}
+
+// TestThreeInputGates tests MAJ, XOR3, AND3 and OR3 and their batch
+// versions on every input combination, with and without trivial inputs
+func TestThreeInputGates(t *testing.T) {
+	sk := key.NewSecretKey()
+	ck := cloudkey.NewCloudKey(sk)
+
+	type gate3 struct {
+		name  string
+		gate  func(a, b, c *gates.Ciphertext, ck *cloudkey.CloudKey) *gates.Ciphertext
+		batch func(inputs [][3]*gates.Ciphertext, ck *cloudkey.CloudKey) []*gates.Ciphertext
+		clear func(a, b, c bool) bool
+	}
+	testCases := []gate3{
+		{"MAJ", gates.MAJ, gates.BatchMAJ, func(a, b, c bool) bool { return (a && b) || (a && c) || (b && c) }},
+		{"XOR3", gates.XOR3, gates.BatchXOR3, func(a, b, c bool) bool { return a != b != c }},
+		{"AND3", gates.AND3, gates.BatchAND3, func(a, b, c bool) bool { return a && b && c }},
+		{"OR3", gates.OR3, gates.BatchOR3, func(a, b, c bool) bool { return a || b || c }},
+	}
+
+	// Every combination encrypted, then with the last input trivial
+	var inputs [][3]*gates.Ciphertext
+	var values [][3]bool
+	for i := 0; i < 16; i++ {
+		v := [3]bool{i&1 != 0, i&2 != 0, i&4 != 0}
+		in := [3]*gates.Ciphertext{encrypt(t, v[0], sk), encrypt(t, v[1], sk), encrypt(t, v[2], sk)}
+		if i >= 8 {
+			in[2] = gates.Constant(v[2])
+		}
+		inputs = append(inputs, in)
+		values = append(values, v)
+	}
+
+	for _, tc := range testCases {
+		batch := tc.batch(inputs, ck)
+		for i, in := range inputs {
+			v := values[i]
+			expected := tc.clear(v[0], v[1], v[2])
+			if dec := decrypt(t, tc.gate(in[0], in[1], in[2], ck), sk); dec != expected {
+				t.Errorf("%s(%v, %v, %v) = %v, expected %v (trivial c: %v)", tc.name, v[0], v[1], v[2], dec, expected, i >= 8)
+			}
+			if dec := decrypt(t, batch[i], sk); dec != expected {
+				t.Errorf("Batch%s[%d] = %v, expected %v", tc.name, i, dec, expected)
+			}
+		}
+	}
+}
+
+// TestTrivialInputs checks that gates with a trivial (Constant) input skip
+// bootstrapping and still compute the right value
+func TestTrivialInputs(t *testing.T) {
+	sk := key.NewSecretKey()
+	ck := cloudkey.NewCloudKey(sk)
+	yes := gates.Constant(true)
+	no := gates.Constant(false)
+
+	if v, ok := gates.TrivialValue(yes); !ok || !v {
+		t.Errorf("TrivialValue(Constant(true)) = %v, %v", v, ok)
+	}
+	if v, ok := gates.TrivialValue(gates.NOT(no)); !ok || !v {
+		t.Errorf("TrivialValue(NOT(Constant(false))) = %v, %v", v, ok)
+	}
+
+	for _, x := range []bool{false, true} {
+		ct := encrypt(t, x, sk)
+		if _, ok := gates.TrivialValue(ct); ok {
+			t.Fatalf("encryption of %v is trivial", x)
+		}
+
+		testCases := []struct {
+			name     string
+			result   *gates.Ciphertext
+			expected bool
+			trivial  bool
+		}{
+			{"AND(x, false)", gates.AND(ct, no, ck), false, true},
+			{"OR(true, x)", gates.OR(yes, ct, ck), true, true},
+			{"AND(x, true)", gates.AND(ct, yes, ck), x, false},
+			{"XOR(x, true)", gates.XOR(ct, yes, ck), !x, false},
+			{"NAND(false, true)", gates.NAND(no, yes, ck), true, true},
+			{"ANDNY(x, true)", gates.ANDNY(ct, yes, ck), !x, false},
+			{"MUX(true, x, false)", gates.MUX(yes, ct, no, ck), x, false},
+			{"MUX(x, true, false)", gates.MUX(ct, yes, no, ck), x, false},
+			{"MUX(x, false, true)", gates.MUX(ct, no, yes, ck), !x, false},
+			{"MUX(x, true, x)", gates.MUX(ct, yes, ct, ck), x, false},
+			{"MUX(x, x, false)", gates.MUX(ct, ct, no, ck), x, false},
+		}
+		for _, tc := range testCases {
+			if _, ok := gates.TrivialValue(tc.result); ok != tc.trivial {
+				t.Errorf("x=%v: %s trivial = %v, expected %v", x, tc.name, ok, tc.trivial)
+			}
+			if dec := decrypt(t, tc.result, sk); dec != tc.expected {
+				t.Errorf("x=%v: %s = %v, expected %v", x, tc.name, dec, tc.expected)
+			}
+		}
+	}
+
+	// Batches mixing trivial and encrypted pairs
+	inputs := [][2]*gates.Ciphertext{
+		{encrypt(t, true, sk), encrypt(t, true, sk)},
+		{encrypt(t, true, sk), no},
+		{yes, encrypt(t, true, sk)},
+		{yes, yes},
+	}
+	expected := []bool{true, false, true, true}
+	for i, result := range gates.BatchAND(inputs, ck) {
+		if dec := decrypt(t, result, sk); dec != expected[i] {
+			t.Errorf("BatchAND[%d] = %v, expected %v", i, dec, expected[i])
+		}
+	}
+}
+
+// TestMixedParameterSets holds a boolean key set and a Uint2 key set at the
+// same time without touching params.CurrentSecurityLevel
+func TestMixedParameterSets(t *testing.T) {
+	boolParams := params.GetParameters(params.Security80Bit)
+	uintParams := params.GetParameters(params.SecurityUint2)
+
+	boolSK := key.NewSecretKeyWithParams(boolParams)
+	boolCK := cloudkey.NewCloudKey(boolSK)
+	uintSK := key.NewSecretKeyWithParams(uintParams)
+	uintCK := cloudkey.NewCloudKey(uintSK)
+	eval := evaluator.NewEvaluatorWithParams(uintParams)
+
+	encryptBool := func(val bool) *gates.Ciphertext {
+		return tlwe.NewTLWELv0WithParams(boolParams).EncryptBool(val, boolParams.TLWELv0.ALPHA, boolSK.KeyLv0)
+	}
+
+	for _, tc := range []struct{ a, b bool }{{false, true}, {true, true}} {
+		and := gates.AND(encryptBool(tc.a), encryptBool(tc.b), boolCK)
+		if dec := and.DecryptBool(boolSK.KeyLv0); dec != (tc.a && tc.b) {
+			t.Errorf("AND(%v, %v) = %v under 80-bit params", tc.a, tc.b, dec)
+		}
+
+		for x := 0; x < 4; x++ {
+			ct := tlwe.NewTLWELv0WithParams(uintParams).EncryptLWEMessage(x, 4, uintParams.TLWELv0.ALPHA, uintSK.KeyLv0)
+			res := eval.BootstrapFunc(ct, func(m int) int { return (m + 1) % 4 }, 4, uintCK.BootstrappingKey, uintCK.KeySwitchingKey, uintCK.DecompositionOffset)
+			if got := res.DecryptLWEMessage(4, uintSK.KeyLv0); got != (x+1)%4 {
+				t.Errorf("(%d + 1) %% 4 = %d under Uint2 params", x, got)
+			}
+		}
+	}
+}
+
+// TestEvaluator checks the gate methods of a gates.Evaluator against the truth tables
+func TestEvaluator(t *testing.T) {
+	sk := key.NewSecretKey()
+	eval := gates.NewEvaluator(cloudkey.NewCloudKey(sk))
+
+	gatesUnderTest := []struct {
+		name string
+		gate func(a, b *gates.Ciphertext) *gates.Ciphertext
+		want func(a, b bool) bool
+	}{
+		{"NAND", eval.NAND, func(a, b bool) bool { return !(a && b) }},
+		{"OR", eval.OR, func(a, b bool) bool { return a || b }},
+		{"XNOR", eval.XNOR, func(a, b bool) bool { return a == b }},
+		{"ANDYN", eval.ANDYN, func(a, b bool) bool { return a && !b }},
+		{"ORNY", eval.ORNY, func(a, b bool) bool { return !a || b }},
+	}
+
+	for _, g := range gatesUnderTest {
+		for _, tc := range []struct{ a, b bool }{{false, false}, {false, true}, {true, false}, {true, true}} {
+			dec := decrypt(t, g.gate(encrypt(t, tc.a, sk), encrypt(t, tc.b, sk)), sk)
+			if dec != g.want(tc.a, tc.b) {
+				t.Errorf("Evaluator.%s(%v, %v) = %v", g.name, tc.a, tc.b, dec)
+			}
+		}
+	}
+
+	mux := eval.MUX(encrypt(t, false, sk), eval.Constant(true), eval.Constant(false))
+	if decrypt(t, mux, sk) {
+		t.Errorf("Evaluator.MUX(false, true, false) = true")
+	}
+}
+
+// TestConcurrentGates runs gates from several goroutines, both through forked
+// evaluators and through the package-level functions
+func TestConcurrentGates(t *testing.T) {
+	sk := key.NewSecretKey()
+	ck := cloudkey.NewCloudKey(sk)
+	base := gates.NewEvaluator(ck)
+
+	const workers = 4
+	inputs := make([][2]bool, workers)
+	ctInputs := make([][2]*gates.Ciphertext, workers)
+	for i := range inputs {
+		inputs[i] = [2]bool{i&1 == 1, i&2 == 2}
+		ctInputs[i] = [2]*gates.Ciphertext{encrypt(t, inputs[i][0], sk), encrypt(t, inputs[i][1], sk)}
+	}
+
+	forked := make([]*gates.Ciphertext, workers)
+	shared := make([]*gates.Ciphertext, workers)
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(2)
+		go func(i int, e *gates.Evaluator) {
+			defer wg.Done()
+			forked[i] = e.XOR(ctInputs[i][0], ctInputs[i][1])
+		}(i, base.ShallowCopy())
+		go func(i int) {
+			defer wg.Done()
+			shared[i] = gates.AND(ctInputs[i][0], ctInputs[i][1], ck)
+		}(i)
+	}
+	wg.Wait()
+
+	for i, in := range inputs {
+		if dec := decrypt(t, forked[i], sk); dec != (in[0] != in[1]) {
+			t.Errorf("concurrent Evaluator.XOR(%v, %v) = %v", in[0], in[1], dec)
+		}
+		if dec := decrypt(t, shared[i], sk); dec != (in[0] && in[1]) {
+			t.Errorf("concurrent AND(%v, %v) = %v", in[0], in[1], dec)
+		}
+	}
+}
+
 // ============================================================================
 // BENCHMARK TESTS
 // ============================================================================
@@ -566,6 +857,23 @@ func BenchmarkBootstrapMUX(b *testing.B) {
 	}
 }
 
+// BenchmarkBootstrapMAJ benchmarks the three-input majority gate
+func BenchmarkBootstrapMAJ(b *testing.B) {
+	sk := key.NewSecretKey()
+	ck := cloudkey.NewCloudKey(sk)
+
+	ctA := encrypt(nil, true, sk)
+	ctB := encrypt(nil, false, sk)
+	ctC := encrypt(nil, true, sk)
+
+	b.ResetTimer()
+	b.ReportAllocs()
+
+	for i := 0; i < b.N; i++ {
+		_ = gates.MAJ(ctA, ctB, ctC, ck)
+	}
+}
+
 // BenchmarkBatchBootstrap benchmarks batch bootstrap operations
 func BenchmarkBatchBootstrap(b *testing.B) {
 	sizes := []int{1, 2, 4, 8, 16}
@@ -0,0 +1,128 @@
+package gates
+
+// truthTable is the clear function computed by a two-input gate
+type truthTable func(a, b bool) bool
+
+// Clear functions of the two-input gates
+var (
+	clearAND   truthTable = func(a, b bool) bool { return a && b }
+	clearOR    truthTable = func(a, b bool) bool { return a || b }
+	clearNAND  truthTable = func(a, b bool) bool { return !(a && b) }
+	clearNOR   truthTable = func(a, b bool) bool { return !(a || b) }
+	clearXOR   truthTable = func(a, b bool) bool { return a != b }
+	clearXNOR  truthTable = func(a, b bool) bool { return a == b }
+	clearANDNY truthTable = func(a, b bool) bool { return !a && b }
+	clearANDYN truthTable = func(a, b bool) bool { return a && !b }
+	clearORNY  truthTable = func(a, b bool) bool { return !a || b }
+	clearORYN  truthTable = func(a, b bool) bool { return a || !b }
+)
+
+// TrivialValue returns the value of a trivial ciphertext (see
+// tlwe.TLWELv0.IsTrivial), such as one made by Constant. ok is false if
+// ct is a real encryption.
+func TrivialValue(ct *Ciphertext) (value, ok bool) {
+	if !ct.IsTrivial() {
+		return false, false
+	}
+	return int32(ct.B()) >= 0, true
+}
+
+// shortCircuit evaluates a two-input gate without bootstrapping when an
+// input is trivial: with one operand known the gate reduces to a constant,
+// a copy or a NOT of the other operand
+func shortCircuit(clear truthTable, a, b *Ciphertext) (*Ciphertext, bool) {
+	va, ta := TrivialValue(a)
+	vb, tb := TrivialValue(b)
+	switch {
+	case ta && tb:
+		return ConstantWithParams(clear(va, vb), a.Params), true
+	case ta:
+		return unary(clear(va, false), clear(va, true), b), true
+	case tb:
+		return unary(clear(false, vb), clear(true, vb), a), true
+	}
+	return nil, false
+}
+
+// unary returns the function of x that is f0 when x is false and f1 when x is true
+func unary(f0, f1 bool, x *Ciphertext) *Ciphertext {
+	switch {
+	case f0 == f1:
+		return ConstantWithParams(f0, x.Params)
+	case f1:
+		return Copy(x)
+	default:
+		return NOT(x)
+	}
+}
+
+// equals reports whether f and g compute the same function
+func (f truthTable) equals(g truthTable) bool {
+	for x := 0; x < 4; x++ {
+		a, b := x&1 == 1, x&2 == 2
+		if f(a, b) != g(a, b) {
+			return false
+		}
+	}
+	return true
+}
+
+// twoInputGates are the gates on two inputs, which between them compute
+// every function depending on both
+var twoInputGates = []struct {
+	clear truthTable
+	gate  func(e *Evaluator, a, b *Ciphertext) *Ciphertext
+}{
+	{clearAND, (*Evaluator).AND},
+	{clearOR, (*Evaluator).OR},
+	{clearNAND, (*Evaluator).NAND},
+	{clearNOR, (*Evaluator).NOR},
+	{clearXOR, (*Evaluator).XOR},
+	{clearXNOR, (*Evaluator).XNOR},
+	{clearANDNY, (*Evaluator).ANDNY},
+	{clearANDYN, (*Evaluator).ANDYN},
+	{clearORNY, (*Evaluator).ORNY},
+	{clearORYN, (*Evaluator).ORYN},
+}
+
+// gate2 evaluates any function of two inputs with at most one bootstrap
+func (e *Evaluator) gate2(f truthTable, a, b *Ciphertext) *Ciphertext {
+	switch {
+	case f(false, false) == f(false, true) && f(true, false) == f(true, true):
+		return unary(f(false, false), f(true, false), a)
+	case f(false, false) == f(true, false) && f(false, true) == f(true, true):
+		return unary(f(false, false), f(false, true), b)
+	}
+	for _, g := range twoInputGates {
+		if g.clear.equals(f) {
+			return g.gate(e, a, b)
+		}
+	}
+	panic("gates: no gate for truth table")
+}
+
+// truthTable3 is the clear function computed by a three-input gate
+type truthTable3 func(a, b, c bool) bool
+
+// Clear functions of the three-input gates
+var (
+	clearMAJ  truthTable3 = func(a, b, c bool) bool { return (a && b) || (a && c) || (b && c) }
+	clearAND3 truthTable3 = func(a, b, c bool) bool { return a && b && c }
+	clearOR3  truthTable3 = func(a, b, c bool) bool { return a || b || c }
+	clearXOR3 truthTable3 = func(a, b, c bool) bool { return a != b != c }
+)
+
+// shortCircuit3 evaluates a three-input gate with a trivial input as the
+// two-input gate it reduces to on the other inputs
+func (e *Evaluator) shortCircuit3(clear truthTable3, a, b, c *Ciphertext) (*Ciphertext, bool) {
+	if v, ok := TrivialValue(a); ok {
+		return e.gate2(func(y, z bool) bool { return clear(v, y, z) }, b, c), true
+	}
+	if v, ok := TrivialValue(b); ok {
+		return e.gate2(func(x, z bool) bool { return clear(x, v, z) }, a, c), true
+	}
+	if v, ok := TrivialValue(c); ok {
+		return e.gate2(func(x, y bool) bool { return clear(x, y, v) }, a, b), true
+	}
+	return nil, false
+}
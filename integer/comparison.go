@@ -0,0 +1,267 @@
+package integer
+
+import (
+	"github.com/thedonutfactory/go-tfhe/cloudkey"
+	"github.com/thedonutfactory/go-tfhe/gates"
+	"github.com/thedonutfactory/go-tfhe/lut"
+	"github.com/thedonutfactory/go-tfhe/params"
+	"github.com/thedonutfactory/go-tfhe/tlwe"
+	"github.com/thedonutfactory/go-tfhe/utils"
+)
+
+// Outcome of comparing two blocks (or two integers), stored in one block
+const (
+	signLess    = 0
+	signEqual   = 1
+	signGreater = 2
+)
+
+// compareTables holds the lookup tables used by the comparison operators
+type compareTables struct {
+	// Block-wise compare, applied to a_i - b_i + MessageModulus - 1
+	sign     *lut.LookUpTable // sign of a_i - b_i
+	notEqual *lut.LookUpTable // 1 if a_i != b_i
+
+	// Tree reduction
+	nonZero    *lut.LookUpTable // 1 if a sum of flags is non-zero
+	mergeSigns *lut.LookUpTable // 3*high + low -> high, or low if high is equal; nil if two signs do not fit in a block
+
+	// Min/Max: the condition is pre-multiplied by MessageModulus and added to a digit
+	isLess        *lut.LookUpTable
+	isGreater     *lut.LookUpTable
+	selectIfSet   *lut.LookUpTable // digit if the condition is 1, else 0
+	selectIfClear *lut.LookUpTable // digit if the condition is 0, else 0
+
+	// Encrypted booleans in the gate encoding (±1/8)
+	boolZero    *lut.LookUpTable
+	boolLess    *lut.LookUpTable
+	boolGreater *lut.LookUpTable
+}
+
+// newCompareTables generates the comparison lookup tables for cfg
+func newCompareTables(gen *lut.Generator, cfg Config) compareTables {
+	msg := cfg.MessageModulus
+	boolTable := func(pred func(x int) bool) *lut.LookUpTable {
+		return gen.GenLookUpTableFull(func(x int) params.Torus {
+			if pred(x) {
+				return utils.F64ToTorus(0.125)
+			}
+			return utils.F64ToTorus(-0.125)
+		})
+	}
+	indicator := func(pred func(x int) bool) *lut.LookUpTable {
+		return gen.GenLookUpTable(func(x int) int {
+			if pred(x) {
+				return 1
+			}
+			return 0
+		})
+	}
+
+	t := compareTables{
+		sign: gen.GenLookUpTable(func(d int) int {
+			switch {
+			case d < msg-1:
+				return signLess
+			case d == msg-1:
+				return signEqual
+			default:
+				return signGreater
+			}
+		}),
+		notEqual:    indicator(func(d int) bool { return d != msg-1 }),
+		nonZero:     indicator(func(x int) bool { return x != 0 }),
+		isLess:      indicator(func(s int) bool { return s == signLess }),
+		isGreater:   indicator(func(s int) bool { return s == signGreater }),
+		selectIfSet: gen.GenLookUpTable(func(x int) int { return max(x-msg, 0) }),
+		selectIfClear: gen.GenLookUpTable(func(x int) int {
+			if x < msg {
+				return x
+			}
+			return 0
+		}),
+		boolZero:    boolTable(func(x int) bool { return x == 0 }),
+		boolLess:    boolTable(func(s int) bool { return s == signLess }),
+		boolGreater: boolTable(func(s int) bool { return s == signGreater }),
+	}
+	if cfg.PlaintextModulus() >= 9 {
+		t.mergeSigns = gen.GenLookUpTable(func(x int) int {
+			if high := x / 3; high != signEqual {
+				return high
+			}
+			return x % 3
+		})
+	}
+	return t
+}
+
+// blockDiffs returns a_i - b_i + MessageModulus - 1 for every block, which
+// lies in [0, 2*MessageModulus-2] and is MessageModulus - 1 exactly when the
+// digits are equal. a and b must be clean.
+func blockDiffs(a, b *radix) []*tlwe.TLWELv0 {
+	checkCompatible(a, b)
+	msg := a.config.MessageModulus
+	result := make([]*tlwe.TLWELv0, len(a.blocks))
+	for i := range a.blocks {
+		if a.degrees[i] >= msg || b.degrees[i] >= msg {
+			panic("integer: comparing a block with unpropagated carries")
+		}
+		d := a.blocks[i].Sub(b.blocks[i])
+		d.SetB(d.B() + a.config.encode(msg-1))
+		result[i] = d
+	}
+	return result
+}
+
+// sum returns the sum of blocks
+func sum(blocks []*tlwe.TLWELv0) *tlwe.TLWELv0 {
+	result := tlwe.NewTLWELv0WithParams(blocks[0].Params)
+	for _, b := range blocks {
+		result.AddAssign(b, result)
+	}
+	return result
+}
+
+// anyNonZero reduces 0/1 flags to a sum that is zero exactly when every flag
+// is zero. Flags are summed in groups that fit in a block, and each group is
+// folded back to a single flag until one group remains.
+func (s *server) anyNonZero(flags []*tlwe.TLWELv0, ck *cloudkey.CloudKey) *tlwe.TLWELv0 {
+	group := s.config.PlaintextModulus() - 1
+	for len(flags) > group {
+		next := make([]*tlwe.TLWELv0, 0, (len(flags)+group-1)/group)
+		for i := 0; i < len(flags); i += group {
+			next = append(next, s.bootstrap(sum(flags[i:min(i+group, len(flags))]), s.compare.nonZero, ck))
+		}
+		flags = next
+	}
+	return sum(flags)
+}
+
+// notEqual returns a block that is zero exactly when a == b
+func (s *server) notEqual(a, b *radix, ck *cloudkey.CloudKey) *tlwe.TLWELv0 {
+	diffs := blockDiffs(a, b)
+	for i, d := range diffs {
+		diffs[i] = s.bootstrap(d, s.compare.notEqual, ck)
+	}
+	return s.anyNonZero(diffs, ck)
+}
+
+// sign returns a block holding signLess, signEqual or signGreater for a
+// compared to b
+func (s *server) sign(a, b *radix, ck *cloudkey.CloudKey) *tlwe.TLWELv0 {
+	if s.compare.mergeSigns == nil {
+		return s.signByBorrow(a, b, ck)
+	}
+
+	signs := blockDiffs(a, b)
+	for i, d := range signs {
+		signs[i] = s.bootstrap(d, s.compare.sign, ck)
+	}
+
+	// Merge adjacent signs pairwise: the more significant block decides
+	// unless its digits are equal
+	for len(signs) > 1 {
+		next := make([]*tlwe.TLWELv0, 0, (len(signs)+1)/2)
+		for i := 0; i+1 < len(signs); i += 2 {
+			packed := signs[i+1].Add(signs[i+1]).Add(signs[i+1]).Add(signs[i])
+			next = append(next, s.bootstrap(packed, s.compare.mergeSigns, ck))
+		}
+		if len(signs)%2 == 1 {
+			next = append(next, signs[len(signs)-1])
+		}
+		signs = next
+	}
+	return signs[0]
+}
+
+// signByBorrow computes the sign for configurations whose block cannot hold
+// two packed signs. a >= b exactly when a + (MessageModulus^n - b) carries
+// out of the top block, and combining that carry with the not-equal flag
+// gives 2*ge + ne - 1 = sign.
+func (s *server) signByBorrow(a, b *radix, ck *cloudkey.CloudKey) *tlwe.TLWELv0 {
+	ne := s.bootstrap(s.notEqual(a, b, ck), s.compare.nonZero, ck)
+
+	diff := add(a, neg(b))
+	var carry *tlwe.TLWELv0
+	for _, block := range diff.blocks {
+		if carry != nil {
+			block = block.Add(carry)
+		}
+		carry = s.bootstrap(block, s.carry, ck)
+	}
+
+	result := carry.Add(carry).Add(ne)
+	result.SetB(result.B() - s.config.encode(1))
+	return result
+}
+
+// selectRadix returns a if cond (a 0/1 block) is 1 and b otherwise.
+// a and b must be clean.
+func (s *server) selectRadix(cond *tlwe.TLWELv0, a, b *radix, ck *cloudkey.CloudKey) *radix {
+	checkCompatible(a, b)
+	msg := s.config.MessageModulus
+	scaled := tlwe.NewTLWELv0WithParams(cond.Params)
+	for j, c := range cond.P {
+		scaled.P[j] = c * params.Torus(msg)
+	}
+	scaled.Variance = tlwe.ScaledVariance(cond.Variance, params.Torus(msg))
+
+	result := a.copy()
+	for i := range result.blocks {
+		fromA := s.bootstrap(scaled.Add(a.blocks[i]), s.compare.selectIfSet, ck)
+		fromB := s.bootstrap(scaled.Add(b.blocks[i]), s.compare.selectIfClear, ck)
+		// Only one of the two is non-zero, so the sum is still one digit
+		result.blocks[i] = fromA.Add(fromB)
+		result.degrees[i] = msg - 1
+	}
+	return result
+}
+
+// Eq returns an encrypted boolean that is true if a == b.
+// The result uses the gate encoding, so it can be combined with the
+// functions of the gates package (for example as the condition of gates.MUX).
+func (a *FheUint[T]) Eq(b *FheUint[T], ck *cloudkey.CloudKey) *gates.Ciphertext {
+	s := serverFor(ck)
+	return s.bootstrap(s.notEqual(&a.radix, &b.radix, ck), s.compare.boolZero, ck)
+}
+
+// Ne returns an encrypted boolean that is true if a != b
+func (a *FheUint[T]) Ne(b *FheUint[T], ck *cloudkey.CloudKey) *gates.Ciphertext {
+	return gates.NOT(a.Eq(b, ck))
+}
+
+// Lt returns an encrypted boolean that is true if a < b
+func (a *FheUint[T]) Lt(b *FheUint[T], ck *cloudkey.CloudKey) *gates.Ciphertext {
+	s := serverFor(ck)
+	return s.bootstrap(s.sign(&a.radix, &b.radix, ck), s.compare.boolLess, ck)
+}
+
+// Le returns an encrypted boolean that is true if a <= b
+func (a *FheUint[T]) Le(b *FheUint[T], ck *cloudkey.CloudKey) *gates.Ciphertext {
+	return gates.NOT(a.Gt(b, ck))
+}
+
+// Gt returns an encrypted boolean that is true if a > b
+func (a *FheUint[T]) Gt(b *FheUint[T], ck *cloudkey.CloudKey) *gates.Ciphertext {
+	s := serverFor(ck)
+	return s.bootstrap(s.sign(&a.radix, &b.radix, ck), s.compare.boolGreater, ck)
+}
+
+// Ge returns an encrypted boolean that is true if a >= b
+func (a *FheUint[T]) Ge(b *FheUint[T], ck *cloudkey.CloudKey) *gates.Ciphertext {
+	return gates.NOT(a.Lt(b, ck))
+}
+
+// Min returns the smaller of a and b
+func (a *FheUint[T]) Min(b *FheUint[T], ck *cloudkey.CloudKey) *FheUint[T] {
+	s := serverFor(ck)
+	cond := s.bootstrap(s.sign(&a.radix, &b.radix, ck), s.compare.isLess, ck)
+	return &FheUint[T]{*s.selectRadix(cond, &a.radix, &b.radix, ck)}
+}
+
+// Max returns the larger of a and b
+func (a *FheUint[T]) Max(b *FheUint[T], ck *cloudkey.CloudKey) *FheUint[T] {
+	s := serverFor(ck)
+	cond := s.bootstrap(s.sign(&a.radix, &b.radix, ck), s.compare.isGreater, ck)
+	return &FheUint[T]{*s.selectRadix(cond, &a.radix, &b.radix, ck)}
+}
@@ -0,0 +1,86 @@
+package integer
+
+import (
+	"errors"
+	"fmt"
+	"math/bits"
+
+	"github.com/thedonutfactory/go-tfhe/evaluator"
+	"github.com/thedonutfactory/go-tfhe/params"
+)
+
+// ErrUnsupportedParams is returned when a parameter set has no integer message space
+var ErrUnsupportedParams = errors.New("integer: parameter set does not support radix integers")
+
+// Config describes how an integer is split into radix blocks.
+//
+// Each block encrypts one digit in base MessageModulus. The block message
+// space is MessageModulus * CarryModulus, so a block can absorb carries
+// (or the sum of several digits) before they have to be propagated.
+type Config struct {
+	MessageModulus int // Base of the radix digits
+	CarryModulus   int // Headroom above a digit for carries that are not yet propagated
+}
+
+// ConfigFor returns the radix decomposition for a Uint parameter set.
+//
+// The 2^K message space of UintK is split into a digit of D bits, D the
+// largest power of two not above K/2, and K-D carry bits:
+//
+//	Uint2: 1+1  Uint3: 1+2  Uint4: 2+2  Uint5: 2+3
+//	Uint6: 2+4  Uint7: 2+5  Uint8: 4+4
+//
+// Digits are a power of two that divides 8, so every FheUint width is a
+// whole number of blocks, and the carry space is at least as large as a digit,
+// which is what subtraction, scalar multiplication and the bivariate
+// bootstraps of multiplication need.
+func ConfigFor(p params.Parameters) (Config, error) {
+	if p.Level < params.SecurityUint2 || p.Level > params.SecurityUint8 {
+		return Config{}, fmt.Errorf("%w: %s", ErrUnsupportedParams, p.SecurityInfo())
+	}
+
+	k := int(p.Level)
+	messageBits := 1
+	for messageBits*2 <= k/2 {
+		messageBits *= 2
+	}
+
+	cfg := Config{
+		MessageModulus: 1 << messageBits,
+		CarryModulus:   1 << (k - messageBits),
+	}
+	if err := evaluator.CheckBivariate(p, cfg.MessageModulus, cfg.PlaintextModulus()); err != nil {
+		return Config{}, fmt.Errorf("%w: %v", ErrUnsupportedParams, err)
+	}
+	return cfg, nil
+}
+
+// mustConfig is ConfigFor for callers that cannot return an error
+func mustConfig(p params.Parameters) Config {
+	cfg, err := ConfigFor(p)
+	if err != nil {
+		panic(err)
+	}
+	return cfg
+}
+
+// PlaintextModulus returns the message space of one block
+func (c Config) PlaintextModulus() int {
+	return c.MessageModulus * c.CarryModulus
+}
+
+// MessageBits returns the number of bits in one digit
+func (c Config) MessageBits() int {
+	return bits.Len(uint(c.MessageModulus)) - 1
+}
+
+// BlockCount returns the number of blocks needed for an integer of the given bit width
+func (c Config) BlockCount(width int) int {
+	return width / c.MessageBits()
+}
+
+// encode returns the torus value of message m in a block (m * 2^31 / PlaintextModulus).
+// This matches tlwe.EncryptLWEMessage and lut.Encoder.
+func (c Config) encode(m int) params.Torus {
+	return params.Torus(m) * params.Torus(uint64(1)<<31/uint64(c.PlaintextModulus()))
+}
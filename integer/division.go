@@ -0,0 +1,89 @@
+package integer
+
+import (
+	"github.com/thedonutfactory/go-tfhe/cloudkey"
+)
+
+// divRem returns the quotient and remainder of num / d by long division, one
+// digit of num at a time. num and d must be clean.
+//
+// For each digit, from the most significant, the partial remainder is shifted
+// up by one digit and the digit of num is brought down. The quotient digit is
+// then found by MessageModulus-1 conditional subtractions of d: each one
+// computes rem - d, whose carry out of the top block is 1 exactly when
+// rem >= d, keeps the difference when it is, and adds the carry to the
+// quotient digit. The partial remainder stays below d*MessageModulus, so it
+// is kept with one extra block.
+//
+// Like the other operations this is data oblivious. Dividing by zero returns
+// a quotient with every bit set and the remainder num.
+func (s *server) divRem(num, d *radix, ck *cloudkey.CloudKey) (*radix, *radix) {
+	checkCompatible(num, d)
+	n := len(num.blocks)
+	negDivisor := neg(resize(d, n+1))
+	rem := newRadix(num.params(), num.config, n+1)
+	quotient := newRadix(num.params(), num.config, n)
+
+	for k := n - 1; k >= 0; k-- {
+		rem = shiftBlocks(rem, 1)
+		rem.blocks[0].CopyFrom(num.blocks[k])
+		rem.degrees[0] = num.degrees[k]
+
+		digit := quotient.blocks[k]
+		for try := 1; try < s.config.MessageModulus; try++ {
+			diff := add(rem, negDivisor)
+			ge := s.propagateCarriesOut(diff, ck)
+			rem = s.selectRadix(ge, diff, rem, ck)
+			digit = digit.Add(ge)
+		}
+		quotient.blocks[k] = digit
+		quotient.degrees[k] = s.config.MessageModulus - 1
+	}
+	return quotient, resize(rem, n)
+}
+
+// scalarRadix returns a trivial (noiseless) encryption of a clear value shaped like a
+func scalarRadix(a *radix, value uint64) *radix {
+	return scalarAdd(newRadix(a.params(), a.config, len(a.blocks)), value)
+}
+
+// DivRem returns a / b and a % b.
+// Division by an encrypted zero cannot be detected; it returns the maximum
+// value of T as the quotient and a as the remainder.
+func (a *FheUint[T]) DivRem(b *FheUint[T], ck *cloudkey.CloudKey) (*FheUint[T], *FheUint[T]) {
+	q, r := serverFor(ck).divRem(&a.radix, &b.radix, ck)
+	return &FheUint[T]{*q}, &FheUint[T]{*r}
+}
+
+// Div returns a / b (see DivRem for division by zero)
+func (a *FheUint[T]) Div(b *FheUint[T], ck *cloudkey.CloudKey) *FheUint[T] {
+	q, _ := a.DivRem(b, ck)
+	return q
+}
+
+// Rem returns a % b (see DivRem for division by zero)
+func (a *FheUint[T]) Rem(b *FheUint[T], ck *cloudkey.CloudKey) *FheUint[T] {
+	_, r := a.DivRem(b, ck)
+	return r
+}
+
+// ScalarDivRem returns a / c and a % c for a clear c. It panics if c is zero.
+func (a *FheUint[T]) ScalarDivRem(c T, ck *cloudkey.CloudKey) (*FheUint[T], *FheUint[T]) {
+	if c == 0 {
+		panic("integer: division by zero")
+	}
+	q, r := serverFor(ck).divRem(&a.radix, scalarRadix(&a.radix, uint64(c)), ck)
+	return &FheUint[T]{*q}, &FheUint[T]{*r}
+}
+
+// ScalarDiv returns a / c for a clear c. It panics if c is zero.
+func (a *FheUint[T]) ScalarDiv(c T, ck *cloudkey.CloudKey) *FheUint[T] {
+	q, _ := a.ScalarDivRem(c, ck)
+	return q
+}
+
+// ScalarRem returns a % c for a clear c. It panics if c is zero.
+func (a *FheUint[T]) ScalarRem(c T, ck *cloudkey.CloudKey) *FheUint[T] {
+	_, r := a.ScalarDivRem(c, ck)
+	return r
+}
@@ -0,0 +1,145 @@
+// Package integer provides encrypted unsigned integers built on programmable
+// bootstrapping.
+//
+// An integer is split into radix blocks. Each block is an LWE encryption of
+// one digit in base Config.MessageModulus, with Config.CarryModulus of extra
+// room above the digit so that additions run without bootstrapping. Carries
+// are then propagated with two lookup tables per block (digit and carry
+// extraction), so every operation returns a clean result.
+//
+// Blocks use the Uint parameter sets; the radix decomposition is derived from
+// the key's parameter set by ConfigFor. For example Uint4 (messageModulus 16)
+// gives 2-bit digits with 2 carry bits, so an FheUint8 is four blocks.
+//
+// # Example
+//
+//	p := params.GetParameters(params.SecurityUint4)
+//	sk := key.NewSecretKeyWithParams(p)
+//	ck := cloudkey.NewCloudKey(sk)
+//
+//	a := integer.Encrypt(uint8(200), sk)
+//	b := integer.Encrypt(uint8(100), sk)
+//	sum := a.Add(b, ck)
+//	fmt.Println(sum.Decrypt(sk)) // 44 (mod 256)
+package integer
+
+import (
+	"math/bits"
+
+	"github.com/thedonutfactory/go-tfhe/cloudkey"
+	"github.com/thedonutfactory/go-tfhe/key"
+	"github.com/thedonutfactory/go-tfhe/proxyreenc"
+	"github.com/thedonutfactory/go-tfhe/tlwe"
+)
+
+// Unsigned is the set of clear types an FheUint can encrypt
+type Unsigned interface {
+	~uint8 | ~uint16 | ~uint32 | ~uint64
+}
+
+// FheUint is an encrypted unsigned integer with the width of T.
+// Arithmetic wraps modulo 2^width like Go's unsigned integers.
+type FheUint[T Unsigned] struct {
+	radix
+}
+
+// Encrypted unsigned integers of the usual widths
+type (
+	FheUint8  = FheUint[uint8]
+	FheUint16 = FheUint[uint16]
+	FheUint32 = FheUint[uint32]
+	FheUint64 = FheUint[uint64]
+)
+
+// width returns the bit width of T
+func width[T Unsigned]() int {
+	var zero T
+	return bits.Len64(uint64(^zero))
+}
+
+// Encrypt encrypts value under sk. sk must use a Uint parameter set (see ConfigFor).
+func Encrypt[T Unsigned](value T, sk *key.SecretKey) *FheUint[T] {
+	cfg := mustConfig(sk.Params)
+	return &FheUint[T]{*encryptRadix(uint64(value), cfg.BlockCount(width[T]()), sk)}
+}
+
+// EncryptPublic encrypts value with a public key for a Uint parameter set,
+// so data producers do not need the secret key
+func EncryptPublic[T Unsigned](value T, pk proxyreenc.PublicKey) *FheUint[T] {
+	cfg := mustConfig(pk.KeyParams())
+	return &FheUint[T]{*encryptRadixPublic(uint64(value), cfg.BlockCount(width[T]()), pk)}
+}
+
+// Decrypt decrypts ct with sk
+func (ct *FheUint[T]) Decrypt(sk *key.SecretKey) T {
+	return T(ct.decrypt(sk))
+}
+
+// Blocks returns the block ciphertexts, least significant digit first
+func (ct *FheUint[T]) Blocks() []*tlwe.TLWELv0 {
+	return ct.blocks
+}
+
+// Config returns the radix decomposition of ct
+func (ct *FheUint[T]) Config() Config {
+	return ct.config
+}
+
+// Copy returns a deep copy of ct
+func (ct *FheUint[T]) Copy() *FheUint[T] {
+	return &FheUint[T]{*ct.copy()}
+}
+
+// finish propagates the carries of r and wraps it as an FheUint
+func finish[T Unsigned](r *radix, ck *cloudkey.CloudKey) *FheUint[T] {
+	serverFor(ck).propagateCarries(r, ck)
+	return &FheUint[T]{*r}
+}
+
+// Add returns a + b
+func (a *FheUint[T]) Add(b *FheUint[T], ck *cloudkey.CloudKey) *FheUint[T] {
+	return finish[T](add(&a.radix, &b.radix), ck)
+}
+
+// Sub returns a - b
+func (a *FheUint[T]) Sub(b *FheUint[T], ck *cloudkey.CloudKey) *FheUint[T] {
+	return finish[T](add(&a.radix, neg(&b.radix)), ck)
+}
+
+// Neg returns -a (the two's complement of a)
+func (a *FheUint[T]) Neg(ck *cloudkey.CloudKey) *FheUint[T] {
+	return finish[T](neg(&a.radix), ck)
+}
+
+// ScalarAdd returns a + c for a clear c
+func (a *FheUint[T]) ScalarAdd(c T, ck *cloudkey.CloudKey) *FheUint[T] {
+	return finish[T](scalarAdd(&a.radix, uint64(c)), ck)
+}
+
+// ScalarSub returns a - c for a clear c
+func (a *FheUint[T]) ScalarSub(c T, ck *cloudkey.CloudKey) *FheUint[T] {
+	return a.ScalarAdd(-c, ck)
+}
+
+// ScalarMul returns a * c for a clear c.
+//
+// The product is the sum over the digits c_j of c of (a * c_j) shifted by j
+// blocks, so it costs one carry propagation per non-zero digit of c.
+func (a *FheUint[T]) ScalarMul(c T, ck *cloudkey.CloudKey) *FheUint[T] {
+	return &FheUint[T]{*serverFor(ck).scalarMul(&a.radix, uint64(c), ck)}
+}
+
+// scalarMul implements ScalarMul for every integer type
+func (s *server) scalarMul(a *radix, c uint64, ck *cloudkey.CloudKey) *radix {
+	result := newRadix(a.params(), a.config, len(a.blocks))
+	for j, digit := range a.config.digits(c, len(a.blocks)) {
+		if digit == 0 {
+			continue
+		}
+		partial := scalarMulDigit(shiftBlocks(a, j), digit)
+		s.propagateCarries(partial, ck)
+		result = add(result, partial)
+		s.propagateCarries(result, ck)
+	}
+	return result
+}
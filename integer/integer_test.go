@@ -0,0 +1,426 @@
+package integer_test
+
+import (
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/thedonutfactory/go-tfhe/cloudkey"
+	"github.com/thedonutfactory/go-tfhe/gates"
+	"github.com/thedonutfactory/go-tfhe/integer"
+	"github.com/thedonutfactory/go-tfhe/key"
+	"github.com/thedonutfactory/go-tfhe/params"
+	"github.com/thedonutfactory/go-tfhe/proxyreenc"
+)
+
+// newKeys generates keys for the given Uint parameter set
+func newKeys(t *testing.T, level params.SecurityLevel) (*key.SecretKey, *cloudkey.CloudKey) {
+	t.Helper()
+	sk := key.NewSecretKeyWithParams(params.GetParameters(level))
+	return sk, cloudkey.NewCloudKey(sk)
+}
+
+func TestConfigFor(t *testing.T) {
+	testCases := []struct {
+		level          params.SecurityLevel
+		messageModulus int
+		carryModulus   int
+		blocksPerByte  int
+	}{
+		{params.SecurityUint2, 2, 2, 8},
+		{params.SecurityUint3, 2, 4, 8},
+		{params.SecurityUint4, 4, 4, 4},
+		{params.SecurityUint5, 4, 8, 4},
+		{params.SecurityUint6, 4, 16, 4},
+		{params.SecurityUint7, 4, 32, 4},
+		{params.SecurityUint8, 16, 16, 2},
+	}
+
+	for _, tc := range testCases {
+		cfg, err := integer.ConfigFor(params.GetParameters(tc.level))
+		if err != nil {
+			t.Fatalf("ConfigFor(Uint%d) failed: %v", tc.level, err)
+		}
+		if cfg.MessageModulus != tc.messageModulus || cfg.CarryModulus != tc.carryModulus {
+			t.Errorf("ConfigFor(Uint%d) = %+v, want message %d carry %d", tc.level, cfg, tc.messageModulus, tc.carryModulus)
+		}
+		if got := cfg.BlockCount(8); got != tc.blocksPerByte {
+			t.Errorf("Uint%d BlockCount(8) = %d, want %d", tc.level, got, tc.blocksPerByte)
+		}
+	}
+
+	for _, level := range []params.SecurityLevel{params.Security128Bit, params.SecurityUint1} {
+		if _, err := integer.ConfigFor(params.GetParameters(level)); !errors.Is(err, integer.ErrUnsupportedParams) {
+			t.Errorf("ConfigFor(%d): got %v, want ErrUnsupportedParams", level, err)
+		}
+	}
+}
+
+func TestEncryptDecrypt(t *testing.T) {
+	sk, _ := newKeys(t, params.SecurityUint2)
+
+	for _, v := range []uint8{0, 1, 42, 128, 255} {
+		if got := integer.Encrypt(v, sk).Decrypt(sk); got != v {
+			t.Errorf("FheUint8 Encrypt/Decrypt(%d) = %d", v, got)
+		}
+	}
+	if got := integer.Encrypt(uint16(0xBEEF), sk).Decrypt(sk); got != 0xBEEF {
+		t.Errorf("FheUint16 Encrypt/Decrypt(0xBEEF) = %#x", got)
+	}
+	if got := integer.Encrypt(uint32(0xDEADBEEF), sk).Decrypt(sk); got != 0xDEADBEEF {
+		t.Errorf("FheUint32 Encrypt/Decrypt(0xDEADBEEF) = %#x", got)
+	}
+	if got := integer.Encrypt(uint64(1)<<63|12345, sk).Decrypt(sk); got != uint64(1)<<63|12345 {
+		t.Errorf("FheUint64 Encrypt/Decrypt = %#x", got)
+	}
+}
+
+func TestArithmetic(t *testing.T) {
+	sk, ck := newKeys(t, params.SecurityUint2)
+
+	testCases := []struct{ a, b uint8 }{
+		{0, 0},
+		{42, 137},
+		{200, 100}, // sum wraps
+		{3, 250},   // difference wraps
+		{255, 255},
+	}
+
+	for _, tc := range testCases {
+		ctA := integer.Encrypt(tc.a, sk)
+		ctB := integer.Encrypt(tc.b, sk)
+
+		if got := ctA.Add(ctB, ck).Decrypt(sk); got != tc.a+tc.b {
+			t.Errorf("%d + %d = %d, want %d", tc.a, tc.b, got, tc.a+tc.b)
+		}
+		if got := ctA.Sub(ctB, ck).Decrypt(sk); got != tc.a-tc.b {
+			t.Errorf("%d - %d = %d, want %d", tc.a, tc.b, got, tc.a-tc.b)
+		}
+		if got := ctB.Neg(ck).Decrypt(sk); got != -tc.b {
+			t.Errorf("-%d = %d, want %d", tc.b, got, -tc.b)
+		}
+	}
+}
+
+func TestScalarArithmetic(t *testing.T) {
+	sk, ck := newKeys(t, params.SecurityUint2)
+
+	testCases := []struct{ a, c uint8 }{
+		{42, 0},
+		{42, 1},
+		{200, 100},
+		{13, 27},
+		{255, 255},
+	}
+
+	for _, tc := range testCases {
+		ct := integer.Encrypt(tc.a, sk)
+
+		if got := ct.ScalarAdd(tc.c, ck).Decrypt(sk); got != tc.a+tc.c {
+			t.Errorf("%d + %d = %d, want %d", tc.a, tc.c, got, tc.a+tc.c)
+		}
+		if got := ct.ScalarSub(tc.c, ck).Decrypt(sk); got != tc.a-tc.c {
+			t.Errorf("%d - %d = %d, want %d", tc.a, tc.c, got, tc.a-tc.c)
+		}
+		if got := ct.ScalarMul(tc.c, ck).Decrypt(sk); got != tc.a*tc.c {
+			t.Errorf("%d * %d = %d, want %d", tc.a, tc.c, got, tc.a*tc.c)
+		}
+	}
+}
+
+func TestChainedOperations(t *testing.T) {
+	// Uint4 blocks carry 2-bit digits, so this also covers multi-bit carries
+	sk, ck := newKeys(t, params.SecurityUint4)
+
+	x, y := uint16(40000), uint16(30000)
+	a := integer.Encrypt(x, sk)
+	b := integer.Encrypt(y, sk)
+
+	// (a + b) * 3 - a, all modulo 2^16
+	got := a.Add(b, ck).ScalarMul(3, ck).Sub(a, ck).Decrypt(sk)
+	want := (x+y)*3 - x
+	if got != want {
+		t.Errorf("(a + b) * 3 - a = %d, want %d", got, want)
+	}
+}
+
+func TestComparisons(t *testing.T) {
+	testCases := []struct {
+		name  string
+		level params.SecurityLevel
+	}{
+		{"Uint2 (borrow)", params.SecurityUint2},
+		{"Uint4 (sign tree)", params.SecurityUint4},
+	}
+	pairs := []struct{ a, b uint8 }{
+		{0, 0},
+		{42, 42},
+		{41, 42},
+		{200, 13},
+		{0x10, 0x01}, // decided by a high block
+		{0x01, 0x10},
+		{255, 254},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			sk, ck := newKeys(t, tc.level)
+			for _, p := range pairs {
+				ctA := integer.Encrypt(p.a, sk)
+				ctB := integer.Encrypt(p.b, sk)
+
+				checks := []struct {
+					op   string
+					got  bool
+					want bool
+				}{
+					{"==", ctA.Eq(ctB, ck).DecryptBool(sk.KeyLv0), p.a == p.b},
+					{"!=", ctA.Ne(ctB, ck).DecryptBool(sk.KeyLv0), p.a != p.b},
+					{"<", ctA.Lt(ctB, ck).DecryptBool(sk.KeyLv0), p.a < p.b},
+					{"<=", ctA.Le(ctB, ck).DecryptBool(sk.KeyLv0), p.a <= p.b},
+					{">", ctA.Gt(ctB, ck).DecryptBool(sk.KeyLv0), p.a > p.b},
+					{">=", ctA.Ge(ctB, ck).DecryptBool(sk.KeyLv0), p.a >= p.b},
+				}
+				for _, c := range checks {
+					if c.got != c.want {
+						t.Errorf("%d %s %d = %v, want %v", p.a, c.op, p.b, c.got, c.want)
+					}
+				}
+
+				if got := ctA.Min(ctB, ck).Decrypt(sk); got != min(p.a, p.b) {
+					t.Errorf("Min(%d, %d) = %d", p.a, p.b, got)
+				}
+				if got := ctA.Max(ctB, ck).Decrypt(sk); got != max(p.a, p.b) {
+					t.Errorf("Max(%d, %d) = %d", p.a, p.b, got)
+				}
+			}
+		})
+	}
+}
+
+func TestComparisonWithGates(t *testing.T) {
+	sk, ck := newKeys(t, params.SecurityUint4)
+
+	a := integer.Encrypt(uint16(1000), sk)
+	b := integer.Encrypt(uint16(999), sk)
+
+	// The comparison result is an ordinary gate ciphertext
+	yes := gates.ConstantWithParams(true, sk.Params)
+	no := gates.ConstantWithParams(false, sk.Params)
+	if got := gates.MUX(a.Gt(b, ck), yes, no, ck).DecryptBool(sk.KeyLv0); !got {
+		t.Errorf("MUX(1000 > 999, true, false) = false")
+	}
+	if got := gates.AND(a.Ge(b, ck), a.Eq(b, ck), ck).DecryptBool(sk.KeyLv0); got {
+		t.Errorf("1000 >= 999 AND 1000 == 999 = true")
+	}
+}
+
+func TestMultiplication(t *testing.T) {
+	testCases := []struct {
+		name  string
+		level params.SecurityLevel
+	}{
+		{"Uint2", params.SecurityUint2},
+		{"Uint4", params.SecurityUint4},
+	}
+	pairs := []struct{ a, b uint8 }{
+		{0, 77},
+		{1, 255},
+		{12, 11},
+		{200, 3}, // wraps
+		{255, 255},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			sk, ck := newKeys(t, tc.level)
+			for _, p := range pairs {
+				got := integer.Encrypt(p.a, sk).Mul(integer.Encrypt(p.b, sk), ck).Decrypt(sk)
+				if got != p.a*p.b {
+					t.Errorf("%d * %d = %d, want %d", p.a, p.b, got, p.a*p.b)
+				}
+			}
+		})
+	}
+}
+
+// TestConcurrentOperations runs additions and multiplications on one cloud
+// key from several goroutines; run it with -race (make test-race)
+func TestConcurrentOperations(t *testing.T) {
+	sk, ck := newKeys(t, params.SecurityUint2)
+
+	const workers = 4
+	sums := make([]uint8, workers)
+	products := make([]uint8, workers)
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		a := integer.Encrypt(uint8(10*i+7), sk)
+		b := integer.Encrypt(uint8(3*i+5), sk)
+		wg.Add(2)
+		go func(i int) {
+			defer wg.Done()
+			sums[i] = a.Add(b, ck).Decrypt(sk)
+		}(i)
+		go func(i int) {
+			defer wg.Done()
+			products[i] = a.Mul(b, ck).Decrypt(sk)
+		}(i)
+	}
+	wg.Wait()
+
+	for i := 0; i < workers; i++ {
+		x, y := uint8(10*i+7), uint8(3*i+5)
+		if sums[i] != x+y {
+			t.Errorf("concurrent %d + %d = %d, want %d", x, y, sums[i], x+y)
+		}
+		if products[i] != x*y {
+			t.Errorf("concurrent %d * %d = %d, want %d", x, y, products[i], x*y)
+		}
+	}
+}
+
+func TestDivision(t *testing.T) {
+	sk, ck := newKeys(t, params.SecurityUint4)
+
+	testCases := []struct {
+		a, b   uint8
+		scalar bool
+	}{
+		{200, 7, false},
+		{5, 9, false},
+		{255, 16, true},
+		{171, 171, true},
+	}
+
+	for _, tc := range testCases {
+		ctA := integer.Encrypt(tc.a, sk)
+
+		var q, r *integer.FheUint8
+		if tc.scalar {
+			q, r = ctA.ScalarDivRem(tc.b, ck)
+		} else {
+			q, r = ctA.DivRem(integer.Encrypt(tc.b, sk), ck)
+		}
+		if got := q.Decrypt(sk); got != tc.a/tc.b {
+			t.Errorf("%d / %d = %d, want %d (scalar %v)", tc.a, tc.b, got, tc.a/tc.b, tc.scalar)
+		}
+		if got := r.Decrypt(sk); got != tc.a%tc.b {
+			t.Errorf("%d %% %d = %d, want %d (scalar %v)", tc.a, tc.b, got, tc.a%tc.b, tc.scalar)
+		}
+	}
+
+	// Division by an encrypted zero
+	q, r := integer.Encrypt(uint8(42), sk).DivRem(integer.Encrypt(uint8(0), sk), ck)
+	if got := q.Decrypt(sk); got != 255 {
+		t.Errorf("42 / 0 = %d, want 255", got)
+	}
+	if got := r.Decrypt(sk); got != 42 {
+		t.Errorf("42 %% 0 = %d, want 42", got)
+	}
+}
+
+func TestScalarDivByZeroPanics(t *testing.T) {
+	sk, ck := newKeys(t, params.SecurityUint2)
+	defer func() {
+		if recover() == nil {
+			t.Error("ScalarDiv by zero did not panic")
+		}
+	}()
+	integer.Encrypt(uint8(1), sk).ScalarDiv(0, ck)
+}
+
+func TestSignedIntegers(t *testing.T) {
+	sk, ck := newKeys(t, params.SecurityUint2)
+
+	for _, v := range []int8{0, -1, 100, -128} {
+		if got := integer.EncryptInt(v, sk).Decrypt(sk); got != v {
+			t.Errorf("FheInt8 Encrypt/Decrypt(%d) = %d", v, got)
+		}
+	}
+
+	x, y := int8(-100), int8(50)
+	a := integer.EncryptInt(x, sk)
+	b := integer.EncryptInt(y, sk)
+
+	arith := []struct {
+		op        string
+		got, want int8
+	}{
+		{"a + b", a.Add(b, ck).Decrypt(sk), x + y},
+		{"a - b", a.Sub(b, ck).Decrypt(sk), x - y},
+		{"-a", a.Neg(ck).Decrypt(sk), -x},
+		{"a * -3", a.ScalarMul(-3, ck).Decrypt(sk), x * -3},
+		{"|a|", a.Abs(ck).Decrypt(sk), 100},
+		{"|b|", b.Abs(ck).Decrypt(sk), 50},
+		{"a >> 3", a.Sar(3, ck).Decrypt(sk), x >> 3},
+		{"b >> 9", b.Sar(9, ck).Decrypt(sk), 0},
+		{"Min(a, b)", a.Min(b, ck).Decrypt(sk), x},
+		{"Max(a, b)", a.Max(b, ck).Decrypt(sk), y},
+	}
+	for _, c := range arith {
+		if c.got != c.want {
+			t.Errorf("%s = %d, want %d", c.op, c.got, c.want)
+		}
+	}
+
+	// Signed order differs from the unsigned order of the same bits
+	checks := []struct {
+		op   string
+		got  bool
+		want bool
+	}{
+		{"a < b", a.Lt(b, ck).DecryptBool(sk.KeyLv0), true},
+		{"a >= b", a.Ge(b, ck).DecryptBool(sk.KeyLv0), false},
+		{"b > a", b.Gt(a, ck).DecryptBool(sk.KeyLv0), true},
+		{"a < 0", a.IsNegative(ck).DecryptBool(sk.KeyLv0), true},
+		{"b < 0", b.IsNegative(ck).DecryptBool(sk.KeyLv0), false},
+	}
+	for _, c := range checks {
+		if c.got != c.want {
+			t.Errorf("%s = %v, want %v", c.op, c.got, c.want)
+		}
+	}
+
+	if got := integer.CastInt[int16](a, ck).Decrypt(sk); got != -100 {
+		t.Errorf("int16(-100) = %d", got)
+	}
+	// -300 is 0xFED4; narrowing keeps 0xD4
+	if got := integer.CastInt[int8](integer.EncryptInt(int16(-300), sk), ck).Decrypt(sk); got != -44 {
+		t.Errorf("int8(int16(-300)) = %d, want -44", got)
+	}
+}
+
+func TestSignedShiftMultiBitDigits(t *testing.T) {
+	// Uint4 digits hold 2 bits, so odd shifts need the bivariate table
+	sk, ck := newKeys(t, params.SecurityUint4)
+
+	a := integer.EncryptInt(int8(-93), sk)
+	for _, k := range []int{1, 3} {
+		if got := a.Sar(k, ck).Decrypt(sk); got != -93>>k {
+			t.Errorf("-93 >> %d = %d, want %d", k, got, -93>>k)
+		}
+	}
+}
+
+func TestPublicKeyEncryption(t *testing.T) {
+	sk, ck := newKeys(t, params.SecurityUint2)
+	keys := []struct {
+		name string
+		pk   proxyreenc.PublicKey
+	}{
+		{"PublicKeyLv0", proxyreenc.NewPublicKeyLv0ForKey(sk)},
+		{"CompactPublicKey", proxyreenc.NewCompactPublicKey(sk)},
+	}
+
+	for _, k := range keys {
+		// The blocks go through carry propagation like secret key encryptions
+		a := integer.EncryptPublic(uint8(200), k.pk)
+		b := integer.Encrypt(uint8(100), sk)
+		if got := a.Add(b, ck).Decrypt(sk); got != 44 {
+			t.Errorf("%s: 200 + 100 = %d, want 44", k.name, got)
+		}
+		if got := integer.EncryptIntPublic(int16(-1234), k.pk).Decrypt(sk); got != -1234 {
+			t.Errorf("%s: EncryptIntPublic(-1234) decrypted to %d", k.name, got)
+		}
+	}
+}
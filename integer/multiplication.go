@@ -0,0 +1,51 @@
+package integer
+
+import (
+	"github.com/thedonutfactory/go-tfhe/cloudkey"
+)
+
+// mul returns the schoolbook product of a and b modulo MessageModulus^n.
+// a and b must be clean.
+//
+// Every block product a_i*b_j is evaluated with two bivariate bootstraps on
+// a_i*MessageModulus + b_j, one for the low digit (added at position i+j) and
+// one for the high digit (added at i+j+1). Products that land above the top
+// block are skipped. Each row i is accumulated into the result, and carries
+// are only propagated when the next row could overflow a block.
+func (s *server) mul(a, b *radix, ck *cloudkey.CloudKey) *radix {
+	checkCompatible(a, b)
+	msg := s.config.MessageModulus
+	for i := range a.blocks {
+		if a.degrees[i] >= msg || b.degrees[i] >= msg {
+			panic("integer: multiplying a block with unpropagated carries")
+		}
+	}
+
+	n := len(a.blocks)
+	highDegree := (msg - 1) * (msg - 1) / msg
+	result := newRadix(a.params(), a.config, n)
+	for i := 0; i < n; i++ {
+		row := newRadix(a.params(), a.config, n)
+		for j := 0; i+j < n; j++ {
+			k := i + j
+			low := s.bivariate(a.blocks[i], b.blocks[j], s.mulLow, ck)
+			row.blocks[k].AddAssign(low, row.blocks[k])
+			row.addDegree(k, msg-1)
+
+			// With 1-bit digits the high digit is always zero
+			if k+1 < n && highDegree > 0 {
+				high := s.bivariate(a.blocks[i], b.blocks[j], s.mulHigh, ck)
+				row.blocks[k+1].AddAssign(high, row.blocks[k+1])
+				row.addDegree(k+1, highDegree)
+			}
+		}
+		result = s.addPropagating(result, row, ck)
+	}
+	s.propagateCarries(result, ck)
+	return result
+}
+
+// Mul returns a * b
+func (a *FheUint[T]) Mul(b *FheUint[T], ck *cloudkey.CloudKey) *FheUint[T] {
+	return &FheUint[T]{*serverFor(ck).mul(&a.radix, &b.radix, ck)}
+}
@@ -0,0 +1,212 @@
+package integer
+
+import (
+	"github.com/thedonutfactory/go-tfhe/csprng"
+	"github.com/thedonutfactory/go-tfhe/key"
+	"github.com/thedonutfactory/go-tfhe/params"
+	"github.com/thedonutfactory/go-tfhe/proxyreenc"
+	"github.com/thedonutfactory/go-tfhe/tlwe"
+)
+
+// radix is the width independent representation shared by all integer types:
+// a little-endian list of blocks, each an LWE encryption of one digit plus
+// whatever carries have not been propagated yet
+type radix struct {
+	blocks []*tlwe.TLWELv0
+
+	// degrees[i] is the largest value block i can currently hold.
+	// A block is clean when its degree is below MessageModulus.
+	degrees []int
+
+	config Config
+}
+
+// newRadix allocates a radix with n trivial zero blocks for parameter set p
+func newRadix(p params.Parameters, cfg Config, n int) *radix {
+	r := &radix{
+		blocks:  make([]*tlwe.TLWELv0, n),
+		degrees: make([]int, n),
+		config:  cfg,
+	}
+	for i := range r.blocks {
+		r.blocks[i] = tlwe.NewTLWELv0WithParams(p)
+	}
+	return r
+}
+
+// encryptRadix encrypts the n least significant digits of value
+func encryptRadix(value uint64, n int, sk *key.SecretKey) *radix {
+	cfg := mustConfig(sk.Params)
+	r := &radix{
+		blocks:  make([]*tlwe.TLWELv0, n),
+		degrees: make([]int, n),
+		config:  cfg,
+	}
+	src := csprng.Fork()
+	for i, digit := range cfg.digits(value, n) {
+		r.blocks[i] = tlwe.NewTLWELv0WithParams(sk.Params).EncryptLWEMessageWithSource(digit, cfg.PlaintextModulus(), sk.Params.TLWELv0.ALPHA, sk.KeyLv0, src)
+		r.degrees[i] = cfg.MessageModulus - 1
+	}
+	return r
+}
+
+// encryptRadixPublic encrypts the n least significant digits of value with
+// a public key
+func encryptRadixPublic(value uint64, n int, pk proxyreenc.PublicKey) *radix {
+	cfg := mustConfig(pk.KeyParams())
+	digits := cfg.digits(value, n)
+	encoded := make([]params.Torus, n)
+	for i, digit := range digits {
+		encoded[i] = cfg.encode(digit)
+	}
+	r := &radix{
+		blocks:  pk.EncryptTorusLv0(encoded),
+		degrees: make([]int, n),
+		config:  cfg,
+	}
+	for i := range r.degrees {
+		r.degrees[i] = cfg.MessageModulus - 1
+	}
+	return r
+}
+
+// decrypt returns sum(block_i * MessageModulus^i), wrapping modulo 2^64.
+// Unpropagated carries are included, so this is correct for any degrees.
+func (r *radix) decrypt(sk *key.SecretKey) uint64 {
+	msg := uint64(r.config.MessageModulus)
+	var value uint64
+	for i := len(r.blocks) - 1; i >= 0; i-- {
+		value = value*msg + uint64(r.blocks[i].DecryptLWEMessage(r.config.PlaintextModulus(), sk.KeyLv0))
+	}
+	return value
+}
+
+// digits splits value into n base-MessageModulus digits, least significant first
+func (c Config) digits(value uint64, n int) []int {
+	msg := uint64(c.MessageModulus)
+	result := make([]int, n)
+	for i := range result {
+		result[i] = int(value % msg)
+		value /= msg
+	}
+	return result
+}
+
+// params returns the parameter set of the blocks
+func (r *radix) params() params.Parameters {
+	return r.blocks[0].Params
+}
+
+// copy returns a deep copy of r
+func (r *radix) copy() *radix {
+	result := &radix{
+		blocks:  make([]*tlwe.TLWELv0, len(r.blocks)),
+		degrees: append([]int(nil), r.degrees...),
+		config:  r.config,
+	}
+	for i, b := range r.blocks {
+		result.blocks[i] = &tlwe.TLWELv0{P: append([]params.Torus(nil), b.P...), Params: b.Params, Variance: b.Variance}
+	}
+	return result
+}
+
+// resize returns a copy of a with n blocks, dropping the most significant
+// blocks or padding with trivial zeros
+func resize(a *radix, n int) *radix {
+	result := newRadix(a.params(), a.config, n)
+	for i := 0; i < min(n, len(a.blocks)); i++ {
+		result.blocks[i].CopyFrom(a.blocks[i])
+		result.degrees[i] = a.degrees[i]
+	}
+	return result
+}
+
+// addDegree raises the degree of block i by d.
+// It panics if the block could exceed its message space; the
+// operations in this package propagate carries before that can happen.
+func (r *radix) addDegree(i, d int) {
+	r.degrees[i] += d
+	if r.degrees[i] >= r.config.PlaintextModulus() {
+		panic("integer: block message space overflow")
+	}
+}
+
+// checkCompatible panics unless a and b can be combined
+func checkCompatible(a, b *radix) {
+	if a.config != b.config || a.params() != b.params() || len(a.blocks) != len(b.blocks) {
+		panic("integer: operands use different parameter sets")
+	}
+}
+
+// add returns the blockwise sum of a and b without propagating carries
+func add(a, b *radix) *radix {
+	checkCompatible(a, b)
+	result := a.copy()
+	for i := range result.blocks {
+		result.blocks[i].AddAssign(b.blocks[i], result.blocks[i])
+		result.addDegree(i, b.degrees[i])
+	}
+	return result
+}
+
+// neg returns the blocks of -a modulo MessageModulus^n without propagating
+// carries. a must be clean.
+//
+// Negating a block directly would leave the padded message space, so block 0
+// holds MessageModulus - a_0 and block i > 0 holds MessageModulus - 1 - a_i:
+// the added MessageModulus at each position is cancelled by the borrow taken
+// from the next one, and the last one wraps away modulo MessageModulus^n.
+func neg(a *radix) *radix {
+	msg := a.config.MessageModulus
+	result := a.copy()
+	borrow := 0
+	for i, b := range result.blocks {
+		if a.degrees[i] >= msg {
+			panic("integer: negating a block with unpropagated carries")
+		}
+		for j := range b.P {
+			b.P[j] = -b.P[j]
+		}
+		b.SetB(b.B() + a.config.encode(msg-borrow))
+		result.degrees[i] = msg - borrow
+		borrow = 1
+	}
+	return result
+}
+
+// scalarAdd adds the base-MessageModulus digits of a clear value without propagating carries
+func scalarAdd(a *radix, value uint64) *radix {
+	result := a.copy()
+	for i, digit := range a.config.digits(value, len(a.blocks)) {
+		b := result.blocks[i]
+		b.SetB(b.B() + a.config.encode(digit))
+		result.addDegree(i, digit)
+	}
+	return result
+}
+
+// scalarMulDigit multiplies every block of a by a clear digit without propagating carries
+func scalarMulDigit(a *radix, digit int) *radix {
+	result := a.copy()
+	for i, b := range result.blocks {
+		for j := range b.P {
+			b.P[j] *= params.Torus(digit)
+		}
+		b.Variance = tlwe.ScaledVariance(b.Variance, params.Torus(digit))
+		result.degrees[i] = 0
+		result.addDegree(i, a.degrees[i]*digit)
+	}
+	return result
+}
+
+// shiftBlocks multiplies a by MessageModulus^k by moving blocks up and
+// filling the low blocks with trivial zeros
+func shiftBlocks(a *radix, k int) *radix {
+	result := newRadix(a.params(), a.config, len(a.blocks))
+	for i := k; i < len(a.blocks); i++ {
+		src := a.blocks[i-k]
+		result.blocks[i].CopyFrom(src)
+		result.degrees[i] = a.degrees[i-k]
+	}
+	return result
+}
@@ -0,0 +1,148 @@
+package integer
+
+import (
+	"sync"
+
+	"github.com/thedonutfactory/go-tfhe/cloudkey"
+	"github.com/thedonutfactory/go-tfhe/evaluator"
+	"github.com/thedonutfactory/go-tfhe/lut"
+	"github.com/thedonutfactory/go-tfhe/params"
+	"github.com/thedonutfactory/go-tfhe/tlwe"
+)
+
+// server holds the evaluators and lookup tables shared by every integer
+// operation on one parameter set. The tables are read-only; each bootstrap
+// takes an evaluator from the pool, so operations may run concurrently.
+type server struct {
+	evals  sync.Pool
+	config Config
+	gen    *lut.Generator
+
+	message *lut.LookUpTable // x mod MessageModulus
+	carry   *lut.LookUpTable // x / MessageModulus
+
+	// Block products, on x*MessageModulus + y
+	mulLow  *lut.LookUpTable // x*y mod MessageModulus
+	mulHigh *lut.LookUpTable // x*y / MessageModulus
+
+	compare compareTables
+
+	// Signed integers, on the most significant block
+	signBit      *lut.LookUpTable // 1 if the top bit of the digit is set
+	signFill     *lut.LookUpTable // MessageModulus-1 if the top bit is set, else 0
+	boolNegative *lut.LookUpTable // gate-encoded top bit
+
+	// shiftRight[r] shifts two adjacent digits right by r bits, on
+	// x*MessageModulus + y for the higher digit x and the lower digit y
+	shiftRight []*lut.LookUpTable
+}
+
+// Shared servers, one per parameter set, created on first use
+var (
+	serversMu sync.Mutex
+	servers   = make(map[params.Parameters]*server)
+)
+
+// serverFor returns the shared server for the cloud key's parameter set
+func serverFor(ck *cloudkey.CloudKey) *server {
+	serversMu.Lock()
+	defer serversMu.Unlock()
+	s, ok := servers[ck.Params]
+	if !ok {
+		cfg := mustConfig(ck.Params)
+		gen := lut.NewGeneratorWithParams(ck.Params, cfg.PlaintextModulus())
+		s = &server{
+			config:  cfg,
+			gen:     gen,
+			message: gen.GenLookUpTable(func(x int) int { return x % cfg.MessageModulus }),
+			carry:   gen.GenLookUpTable(func(x int) int { return x / cfg.MessageModulus }),
+			mulLow:  gen.GenBivariateLookUpTable(func(x, y int) int { return x * y % cfg.MessageModulus }, cfg.MessageModulus),
+			mulHigh: gen.GenBivariateLookUpTable(func(x, y int) int { return x * y / cfg.MessageModulus }, cfg.MessageModulus),
+			compare: newCompareTables(gen, cfg),
+		}
+		p := ck.Params
+		s.evals.New = func() any { return evaluator.NewEvaluatorWithParams(p) }
+		s.genSignedTables()
+		servers[ck.Params] = s
+	}
+	return s
+}
+
+// bootstrap evaluates a lookup table on one block
+func (s *server) bootstrap(ct *tlwe.TLWELv0, table *lut.LookUpTable, ck *cloudkey.CloudKey) *tlwe.TLWELv0 {
+	eval := s.evals.Get().(*evaluator.Evaluator)
+	defer s.evals.Put(eval)
+	return eval.BootstrapLUT(ct, table, ck.BootstrappingKey, ck.KeySwitchingKey, ck.DecompositionOffset)
+}
+
+// bivariate evaluates table on x*MessageModulus + y for two clean blocks.
+// ConfigFor has checked that the packed value fits in a block.
+func (s *server) bivariate(x, y *tlwe.TLWELv0, table *lut.LookUpTable, ck *cloudkey.CloudKey) *tlwe.TLWELv0 {
+	eval := s.evals.Get().(*evaluator.Evaluator)
+	defer s.evals.Put(eval)
+	return eval.BootstrapBivariateLUT(x, y, table, s.config.MessageModulus, ck.BootstrappingKey, ck.KeySwitchingKey, ck.DecompositionOffset)
+}
+
+// addPropagating returns a + b, first propagating the carries of a and b
+// if their sum would leave no room for the carries of a later propagation
+func (s *server) addPropagating(a, b *radix, ck *cloudkey.CloudKey) *radix {
+	// A block of degree at most this can absorb the largest incoming carry
+	maxDegree := s.config.PlaintextModulus() - 1 - (s.config.PlaintextModulus()-1)/s.config.MessageModulus
+	for i := range a.degrees {
+		if a.degrees[i]+b.degrees[i] > maxDegree {
+			s.propagateCarries(a, ck)
+			s.propagateCarries(b, ck)
+			break
+		}
+	}
+	return add(a, b)
+}
+
+// propagateCarries ripples the carries of r from the least significant
+// block upwards, leaving every block holding a single digit.
+// The carry out of the most significant block is dropped (arithmetic is
+// modulo 2^width).
+func (s *server) propagateCarries(r *radix, ck *cloudkey.CloudKey) {
+	s.ripple(r, false, ck)
+}
+
+// propagateCarriesOut is propagateCarries, but returns the carry out of the
+// most significant block instead of dropping it
+func (s *server) propagateCarriesOut(r *radix, ck *cloudkey.CloudKey) *tlwe.TLWELv0 {
+	if carry := s.ripple(r, true, ck); carry != nil {
+		return carry
+	}
+	return tlwe.NewTLWELv0WithParams(r.params())
+}
+
+// ripple implements carry propagation. The carry out of the top block is
+// only computed when keepCarry is set; nil means it is zero.
+func (s *server) ripple(r *radix, keepCarry bool, ck *cloudkey.CloudKey) *tlwe.TLWELv0 {
+	if r.params() != ck.Params {
+		panic("integer: ciphertext and cloud key use different parameter sets")
+	}
+	msg := s.config.MessageModulus
+
+	var carry *tlwe.TLWELv0
+	carryDegree := 0
+	for i, block := range r.blocks {
+		if carry != nil {
+			block = block.Add(carry)
+			r.addDegree(i, carryDegree)
+		}
+
+		// A block that already holds one digit and received no carry is clean
+		if carry == nil && r.degrees[i] < msg {
+			continue
+		}
+
+		carry = nil
+		if (keepCarry || i < len(r.blocks)-1) && r.degrees[i] >= msg {
+			carry = s.bootstrap(block, s.carry, ck)
+			carryDegree = r.degrees[i] / msg
+		}
+		r.blocks[i] = s.bootstrap(block, s.message, ck)
+		r.degrees[i] = min(r.degrees[i], msg-1)
+	}
+	return carry
+}
@@ -0,0 +1,298 @@
+package integer
+
+import (
+	"fmt"
+	"unsafe"
+
+	"github.com/thedonutfactory/go-tfhe/cloudkey"
+	"github.com/thedonutfactory/go-tfhe/gates"
+	"github.com/thedonutfactory/go-tfhe/key"
+	"github.com/thedonutfactory/go-tfhe/lut"
+	"github.com/thedonutfactory/go-tfhe/params"
+	"github.com/thedonutfactory/go-tfhe/proxyreenc"
+	"github.com/thedonutfactory/go-tfhe/tlwe"
+	"github.com/thedonutfactory/go-tfhe/utils"
+)
+
+// Signed is the set of clear types an FheInt can encrypt
+type Signed interface {
+	~int8 | ~int16 | ~int32 | ~int64
+}
+
+// FheInt is an encrypted two's complement signed integer with the width of T.
+//
+// Its blocks are those of the FheUint of the same width, so addition,
+// subtraction, negation and multiplication are the unsigned operations;
+// comparisons, Abs, Sar and CastInt treat the top bit as the sign.
+// Arithmetic wraps like Go's signed integers.
+type FheInt[T Signed] struct {
+	radix
+}
+
+// Encrypted signed integers of the usual widths
+type (
+	FheInt8  = FheInt[int8]
+	FheInt16 = FheInt[int16]
+	FheInt32 = FheInt[int32]
+	FheInt64 = FheInt[int64]
+)
+
+// signedWidth returns the bit width of T
+func signedWidth[T Signed]() int {
+	var zero T
+	return int(unsafe.Sizeof(zero)) * 8
+}
+
+// EncryptInt encrypts value under sk. sk must use a Uint parameter set (see ConfigFor).
+func EncryptInt[T Signed](value T, sk *key.SecretKey) *FheInt[T] {
+	cfg := mustConfig(sk.Params)
+	return &FheInt[T]{*encryptRadix(uint64(value), cfg.BlockCount(signedWidth[T]()), sk)}
+}
+
+// EncryptIntPublic encrypts value with a public key for a Uint parameter set
+func EncryptIntPublic[T Signed](value T, pk proxyreenc.PublicKey) *FheInt[T] {
+	cfg := mustConfig(pk.KeyParams())
+	return &FheInt[T]{*encryptRadixPublic(uint64(value), cfg.BlockCount(signedWidth[T]()), pk)}
+}
+
+// Decrypt decrypts ct with sk
+func (ct *FheInt[T]) Decrypt(sk *key.SecretKey) T {
+	return T(ct.decrypt(sk))
+}
+
+// Blocks returns the block ciphertexts, least significant digit first
+func (ct *FheInt[T]) Blocks() []*tlwe.TLWELv0 {
+	return ct.blocks
+}
+
+// Config returns the radix decomposition of ct
+func (ct *FheInt[T]) Config() Config {
+	return ct.config
+}
+
+// Copy returns a deep copy of ct
+func (ct *FheInt[T]) Copy() *FheInt[T] {
+	return &FheInt[T]{*ct.copy()}
+}
+
+// finishInt propagates the carries of r and wraps it as an FheInt
+func finishInt[T Signed](r *radix, ck *cloudkey.CloudKey) *FheInt[T] {
+	serverFor(ck).propagateCarries(r, ck)
+	return &FheInt[T]{*r}
+}
+
+// Add returns a + b
+func (a *FheInt[T]) Add(b *FheInt[T], ck *cloudkey.CloudKey) *FheInt[T] {
+	return finishInt[T](add(&a.radix, &b.radix), ck)
+}
+
+// Sub returns a - b
+func (a *FheInt[T]) Sub(b *FheInt[T], ck *cloudkey.CloudKey) *FheInt[T] {
+	return finishInt[T](add(&a.radix, neg(&b.radix)), ck)
+}
+
+// Neg returns -a
+func (a *FheInt[T]) Neg(ck *cloudkey.CloudKey) *FheInt[T] {
+	return finishInt[T](neg(&a.radix), ck)
+}
+
+// ScalarAdd returns a + c for a clear c
+func (a *FheInt[T]) ScalarAdd(c T, ck *cloudkey.CloudKey) *FheInt[T] {
+	return finishInt[T](scalarAdd(&a.radix, uint64(c)), ck)
+}
+
+// ScalarSub returns a - c for a clear c
+func (a *FheInt[T]) ScalarSub(c T, ck *cloudkey.CloudKey) *FheInt[T] {
+	return a.ScalarAdd(-c, ck)
+}
+
+// ScalarMul returns a * c for a clear c (see FheUint.ScalarMul)
+func (a *FheInt[T]) ScalarMul(c T, ck *cloudkey.CloudKey) *FheInt[T] {
+	return &FheInt[T]{*serverFor(ck).scalarMul(&a.radix, uint64(c), ck)}
+}
+
+// Mul returns a * b
+func (a *FheInt[T]) Mul(b *FheInt[T], ck *cloudkey.CloudKey) *FheInt[T] {
+	return &FheInt[T]{*serverFor(ck).mul(&a.radix, &b.radix, ck)}
+}
+
+// IsNegative returns an encrypted boolean that is true if a < 0, with one
+// bootstrap of the most significant block
+func (a *FheInt[T]) IsNegative(ck *cloudkey.CloudKey) *gates.Ciphertext {
+	s := serverFor(ck)
+	return s.bootstrap(a.top(), s.boolNegative, ck)
+}
+
+// Eq returns an encrypted boolean that is true if a == b
+func (a *FheInt[T]) Eq(b *FheInt[T], ck *cloudkey.CloudKey) *gates.Ciphertext {
+	s := serverFor(ck)
+	return s.bootstrap(s.notEqual(&a.radix, &b.radix, ck), s.compare.boolZero, ck)
+}
+
+// Ne returns an encrypted boolean that is true if a != b
+func (a *FheInt[T]) Ne(b *FheInt[T], ck *cloudkey.CloudKey) *gates.Ciphertext {
+	return gates.NOT(a.Eq(b, ck))
+}
+
+// Lt returns an encrypted boolean that is true if a < b
+func (a *FheInt[T]) Lt(b *FheInt[T], ck *cloudkey.CloudKey) *gates.Ciphertext {
+	s := serverFor(ck)
+	return s.bootstrap(s.signedSign(&a.radix, &b.radix, ck), s.compare.boolLess, ck)
+}
+
+// Le returns an encrypted boolean that is true if a <= b
+func (a *FheInt[T]) Le(b *FheInt[T], ck *cloudkey.CloudKey) *gates.Ciphertext {
+	return gates.NOT(a.Gt(b, ck))
+}
+
+// Gt returns an encrypted boolean that is true if a > b
+func (a *FheInt[T]) Gt(b *FheInt[T], ck *cloudkey.CloudKey) *gates.Ciphertext {
+	s := serverFor(ck)
+	return s.bootstrap(s.signedSign(&a.radix, &b.radix, ck), s.compare.boolGreater, ck)
+}
+
+// Ge returns an encrypted boolean that is true if a >= b
+func (a *FheInt[T]) Ge(b *FheInt[T], ck *cloudkey.CloudKey) *gates.Ciphertext {
+	return gates.NOT(a.Lt(b, ck))
+}
+
+// Min returns the smaller of a and b
+func (a *FheInt[T]) Min(b *FheInt[T], ck *cloudkey.CloudKey) *FheInt[T] {
+	s := serverFor(ck)
+	cond := s.bootstrap(s.signedSign(&a.radix, &b.radix, ck), s.compare.isLess, ck)
+	return &FheInt[T]{*s.selectRadix(cond, &a.radix, &b.radix, ck)}
+}
+
+// Max returns the larger of a and b
+func (a *FheInt[T]) Max(b *FheInt[T], ck *cloudkey.CloudKey) *FheInt[T] {
+	s := serverFor(ck)
+	cond := s.bootstrap(s.signedSign(&a.radix, &b.radix, ck), s.compare.isGreater, ck)
+	return &FheInt[T]{*s.selectRadix(cond, &a.radix, &b.radix, ck)}
+}
+
+// Abs returns the absolute value of a. Like Go's integers, the most
+// negative value of T is its own absolute value.
+func (a *FheInt[T]) Abs(ck *cloudkey.CloudKey) *FheInt[T] {
+	s := serverFor(ck)
+	negated := neg(&a.radix)
+	s.propagateCarries(negated, ck)
+	cond := s.bootstrap(a.top(), s.signBit, ck)
+	return &FheInt[T]{*s.selectRadix(cond, negated, &a.radix, ck)}
+}
+
+// Sar returns a shifted right by k bits, filling with copies of the sign bit
+// (Go's >> on signed integers). It panics if k is negative.
+func (a *FheInt[T]) Sar(k int, ck *cloudkey.CloudKey) *FheInt[T] {
+	if k < 0 {
+		panic(fmt.Sprintf("integer: negative shift %d", k))
+	}
+	return &FheInt[T]{*serverFor(ck).sar(&a.radix, k, ck)}
+}
+
+// CastInt converts a to the signed type U like a Go conversion: a wider
+// type is sign extended and a narrower one keeps the low bits.
+//
+// Sign extension bootstraps the most significant block once; narrowing is free.
+func CastInt[U, T Signed](a *FheInt[T], ck *cloudkey.CloudKey) *FheInt[U] {
+	n := a.config.BlockCount(signedWidth[U]())
+	result := resize(&a.radix, n)
+	if n > len(a.blocks) {
+		s := serverFor(ck)
+		fill := s.bootstrap(a.top(), s.signFill, ck)
+		for i := len(a.blocks); i < n; i++ {
+			result.blocks[i].CopyFrom(fill)
+			result.degrees[i] = s.config.MessageModulus - 1
+		}
+	}
+	return &FheInt[U]{*result}
+}
+
+// top returns the most significant block, which holds the sign bit
+func (r *radix) top() *tlwe.TLWELv0 {
+	return r.blocks[len(r.blocks)-1]
+}
+
+// genSignedTables generates the lookup tables used by FheInt
+func (s *server) genSignedTables() {
+	msg := s.config.MessageModulus
+	digitBits := s.config.MessageBits()
+	negative := func(x int) bool { return x%msg >= msg/2 }
+
+	s.signBit = s.gen.GenLookUpTable(func(x int) int {
+		if negative(x) {
+			return 1
+		}
+		return 0
+	})
+	s.signFill = s.gen.GenLookUpTable(func(x int) int {
+		if negative(x) {
+			return msg - 1
+		}
+		return 0
+	})
+	s.boolNegative = s.gen.GenLookUpTableFull(func(x int) params.Torus {
+		if negative(x) {
+			return utils.F64ToTorus(0.125)
+		}
+		return utils.F64ToTorus(-0.125)
+	})
+
+	s.shiftRight = make([]*lut.LookUpTable, digitBits)
+	for r := 1; r < digitBits; r++ {
+		r := r
+		s.shiftRight[r] = s.gen.GenBivariateLookUpTable(func(x, y int) int {
+			return (y>>r | x<<(digitBits-r)) % msg
+		}, msg)
+	}
+}
+
+// signedSign returns sign(a, b) for signed operands. Adding 2^(width-1) to
+// both flips their sign bits, which maps signed order onto unsigned order.
+func (s *server) signedSign(a, b *radix, ck *cloudkey.CloudKey) *tlwe.TLWELv0 {
+	return s.sign(s.flipSign(a, ck), s.flipSign(b, ck), ck)
+}
+
+// flipSign returns a + 2^(width-1) for a clean a: half a digit is added to
+// the top block, and the carry out of it is dropped by one bootstrap
+func (s *server) flipSign(a *radix, ck *cloudkey.CloudKey) *radix {
+	half := s.config.MessageModulus / 2
+	result := a.copy()
+	top := result.top()
+	top.SetB(top.B() + s.config.encode(half))
+	result.addDegree(len(result.blocks)-1, half)
+	s.propagateCarries(result, ck)
+	return result
+}
+
+// sar shifts a clean a right by k bits with sign fill. Whole digits are
+// moved without bootstrapping; the remaining r bits are shifted with one
+// bivariate bootstrap per block, combining each digit with the one above.
+func (s *server) sar(a *radix, k int, ck *cloudkey.CloudKey) *radix {
+	n := len(a.blocks)
+	msg := s.config.MessageModulus
+	digitBits := s.config.MessageBits()
+	k = min(k, n*digitBits-1)
+	q, r := k/digitBits, k%digitBits
+
+	fill := s.bootstrap(a.top(), s.signFill, ck)
+	// src[i] is the digit moved to position i, with the fill above the top
+	src := make([]*tlwe.TLWELv0, n+1)
+	for i := range src {
+		if i+q < n {
+			src[i] = a.blocks[i+q]
+		} else {
+			src[i] = fill
+		}
+	}
+
+	result := newRadix(a.params(), a.config, n)
+	for i := range result.blocks {
+		if r == 0 || i+q >= n {
+			result.blocks[i].CopyFrom(src[i])
+		} else {
+			result.blocks[i] = s.bivariate(src[i+1], src[i], s.shiftRight[r], ck)
+		}
+		result.degrees[i] = msg - 1
+	}
+	return result
+}
@@ -1,32 +1,64 @@
 package key
 
 import (
-	"math/rand"
+	"errors"
 
+	"github.com/thedonutfactory/go-tfhe/csprng"
 	"github.com/thedonutfactory/go-tfhe/params"
 )
 
+// ErrNotBlockBinary is returned for a level 0 key with more than one
+// non-zero coefficient in a block of a parameter set using block blind
+// rotation, which would bootstrap it to wrong results
+var ErrNotBlockBinary = errors.New("key: level 0 key is not block binary")
+
 // SecretKey contains the secret keys for both levels
 type SecretKey struct {
 	KeyLv0 []params.Torus
 	KeyLv1 []params.Torus
+	Params params.Parameters // Parameter set the key was generated for
 }
 
-// NewSecretKey generates a new secret key
+// NewSecretKey generates a new secret key for the current security level
+// using the default randomness source
 func NewSecretKey() *SecretKey {
-	rng := rand.New(rand.NewSource(rand.Int63()))
+	return NewSecretKeyWithParams(params.Current())
+}
+
+// NewSecretKeyWithParams generates a new secret key for parameter set p
+// using the default randomness source
+func NewSecretKeyWithParams(p params.Parameters) *SecretKey {
+	return NewSecretKeyWithSource(p, csprng.Fork())
+}
 
-	lv0N := params.GetTLWELv0().N
-	lv1N := params.GetTLWELv1().N
+// NewSecretKeyWithSource generates a new secret key for parameter set p
+// drawing randomness from src
+func NewSecretKeyWithSource(p params.Parameters, src csprng.Source) *SecretKey {
+	rng := csprng.Rand(src)
+
+	lv0N := p.TLWELv0.N
+	lv1N := p.TLWELv1.N
 
 	keyLv0 := make([]params.Torus, lv0N)
 	keyLv1 := make([]params.Torus, lv1N)
 
-	for i := 0; i < lv0N; i++ {
-		if rng.Intn(2) == 1 {
-			keyLv0[i] = 1
-		} else {
-			keyLv0[i] = 0
+	if p.UseBlockBlindRotation() {
+		// Block binary key: at most one non-zero coefficient per block,
+		// as required by block blind rotation
+		blockSize := p.TRGSWLv1.BlockSize
+		for start := 0; start < lv0N; start += blockSize {
+			size := min(blockSize, lv0N-start)
+			if idx := rng.Intn(size + 1); idx < size {
+				keyLv0[start+idx] = 1
+			}
+		}
+	} else {
+		for i := 0; i < lv0N; i++ {
+			if rng.Intn(2) == 1 {
+				keyLv0[i] = 1
+			} else {
+				keyLv0[i] = 0
+			}
 		}
 	}
 
@@ -41,5 +73,28 @@ func NewSecretKey() *SecretKey {
 	return &SecretKey{
 		KeyLv0: keyLv0,
 		KeyLv1: keyLv1,
+		Params: p,
+	}
+}
+
+// CheckBlockBinary verifies that the level 0 key suits the blind rotation of
+// its parameter set: with BlockSize > 1, every block of BlockSize
+// coefficients must hold at most one non-zero coefficient
+func (sk *SecretKey) CheckBlockBinary() error {
+	if !sk.Params.UseBlockBlindRotation() {
+		return nil
+	}
+	blockSize := sk.Params.TRGSWLv1.BlockSize
+	for start := 0; start < len(sk.KeyLv0); start += blockSize {
+		var weight int
+		for _, k := range sk.KeyLv0[start:min(start+blockSize, len(sk.KeyLv0))] {
+			if k != 0 {
+				weight++
+			}
+		}
+		if weight > 1 {
+			return ErrNotBlockBinary
+		}
 	}
+	return nil
 }
@@ -0,0 +1,72 @@
+package key
+
+import (
+	"bytes"
+	"io"
+
+	"github.com/thedonutfactory/go-tfhe/params"
+	"github.com/thedonutfactory/go-tfhe/utils"
+)
+
+// WriteTo writes the secret key in binary form to w.
+// The output starts with a params.Header followed by KeyLv0 and KeyLv1.
+func (sk *SecretKey) WriteTo(w io.Writer) (int64, error) {
+	if sk.Params == (params.Parameters{}) {
+		return 0, params.ErrNoParams
+	}
+	n, err := params.NewHeader(params.ObjectSecretKey, sk.Params).WriteTo(w)
+	if err != nil {
+		return n, err
+	}
+	m, err := utils.WriteTorusVec(w, sk.KeyLv0)
+	n += m
+	if err != nil {
+		return n, err
+	}
+	m, err = utils.WriteTorusVec(w, sk.KeyLv1)
+	return n + m, err
+}
+
+// ReadFrom reads a secret key written by WriteTo from r.
+// The key's parameter set is restored from the header, and a level 0 key
+// that does not suit its blind rotation returns ErrNotBlockBinary.
+func (sk *SecretKey) ReadFrom(r io.Reader) (int64, error) {
+	var h params.Header
+	n, err := h.ReadFrom(r)
+	if err != nil {
+		return n, err
+	}
+	if err := h.Check(params.ObjectSecretKey); err != nil {
+		return n, err
+	}
+	sk.Params = h.Params
+	sk.KeyLv0 = make([]params.Torus, h.Params.TLWELv0.N)
+	sk.KeyLv1 = make([]params.Torus, h.Params.TLWELv1.N)
+	m, err := utils.ReadTorusVec(r, sk.KeyLv0)
+	n += m
+	if err != nil {
+		return n, err
+	}
+	m, err = utils.ReadTorusVec(r, sk.KeyLv1)
+	n += m
+	if err != nil {
+		return n, err
+	}
+	return n, sk.CheckBlockBinary()
+}
+
+// MarshalBinary implements encoding.BinaryMarshaler
+func (sk *SecretKey) MarshalBinary() ([]byte, error) {
+	var buf bytes.Buffer
+	buf.Grow(params.HeaderSize + (len(sk.KeyLv0)+len(sk.KeyLv1))*4)
+	if _, err := sk.WriteTo(&buf); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// UnmarshalBinary implements encoding.BinaryUnmarshaler
+func (sk *SecretKey) UnmarshalBinary(data []byte) error {
+	_, err := sk.ReadFrom(bytes.NewReader(data))
+	return err
+}
@@ -10,49 +10,53 @@ import (
 type Generator struct {
 	Encoder         *Encoder
 	PolyDegree      int
-	LookUpTableSize int // For binary: equals PolyDegree (not 2*PolyDegree!)
+	LookUpTableSize int // PolyDegree * polyExtendFactor (not 2*PolyDegree!)
+	Params          params.Parameters
 }
 
-// NewGenerator creates a new LUT generator
+// NewGenerator creates a new LUT generator for the current security level
 func NewGenerator(messageModulus int) *Generator {
-	polyDegree := params.GetTRGSWLv1().N
-	// CRITICAL: For standard TFHE, lookUpTableSize = polyDegree (polyExtendFactor = 1)
-	// Only for extended configurations is lookUpTableSize > polyDegree
-	lookUpTableSize := polyDegree
+	return NewGeneratorWithParams(params.Current(), messageModulus)
+}
 
+// NewGeneratorWithParams creates a new LUT generator for parameter set p
+func NewGeneratorWithParams(p params.Parameters, messageModulus int) *Generator {
+	// For standard TFHE, lookUpTableSize = polyDegree (polyExtendFactor = 1).
+	// Only for extended configurations (Uint6-8) is lookUpTableSize > polyDegree
 	return &Generator{
 		Encoder:         NewEncoder(messageModulus),
-		PolyDegree:      polyDegree,
-		LookUpTableSize: lookUpTableSize,
+		PolyDegree:      p.TRGSWLv1.N,
+		LookUpTableSize: p.LookUpTableSize(),
+		Params:          p,
 	}
 }
 
-// NewGeneratorWithScale creates a new LUT generator with custom scale
+// NewGeneratorWithScale creates a new LUT generator with custom scale for the current security level
 func NewGeneratorWithScale(messageModulus int, scale float64) *Generator {
-	polyDegree := params.GetTRGSWLv1().N
+	p := params.Current()
 	return &Generator{
 		Encoder:         NewEncoderWithScale(messageModulus, scale),
-		PolyDegree:      polyDegree,
-		LookUpTableSize: polyDegree, // Standard: lookUpTableSize = polyDegree
+		PolyDegree:      p.TRGSWLv1.N,
+		LookUpTableSize: p.LookUpTableSize(),
+		Params:          p,
 	}
 }
 
 // GenLookUpTable generates a lookup table from a function f: int -> int
 func (g *Generator) GenLookUpTable(f func(int) int) *LookUpTable {
-	lut := NewLookUpTable()
+	lut := NewLookUpTableWithParams(g.Params)
 	g.GenLookUpTableAssign(f, lut)
 	return lut
 }
 
 // GenLookUpTableAssign generates a lookup table and writes to lutOut
 //
-// Algorithm from tfhe-go reference implementation (bootstrap_lut.go:111-132)
-// For standard TFHE with polyExtendFactor=1 (lookUpTableSize = polyDegree):
+// Algorithm from tfhe-go reference implementation (bootstrap_lut.go:111-132):
 // 1. Create lutRaw[lookUpTableSize]
 // 2. For each message x, fill range with encoded f(x)
 // 3. Rotate by offset
 // 4. Negate tail
-// 5. Store in polynomial
+// 5. Store in polynomial(s), interleaved across the polyExtendFactor polynomials
 func (g *Generator) GenLookUpTableAssign(f func(int) int, lutOut *LookUpTable) {
 	messageModulus := g.Encoder.MessageModulus
 
@@ -91,17 +95,13 @@ func (g *Generator) GenLookUpTableAssign(f func(int) int, lutOut *LookUpTable) {
 		rotated[i] = -rotated[i]
 	}
 
-	// Store in polynomial
-	// For polyExtendFactor=1: just copy all lookUpTableSize coefficients
-	for i := 0; i < g.LookUpTableSize; i++ {
-		lutOut.Poly.B[i] = rotated[i]
-		lutOut.Poly.A[i] = 0
-	}
+	g.storeAssign(rotated, lutOut)
+	lutOut.MessageModulus = messageModulus
 }
 
 // GenLookUpTableFull generates a lookup table from a function f: int -> Torus
 func (g *Generator) GenLookUpTableFull(f func(int) params.Torus) *LookUpTable {
-	lut := NewLookUpTable()
+	lut := NewLookUpTableWithParams(g.Params)
 	g.GenLookUpTableFullAssign(f, lut)
 	return lut
 }
@@ -134,15 +134,88 @@ func (g *Generator) GenLookUpTableFullAssign(f func(int) params.Torus, lutOut *L
 		rotated[i] = -rotated[i]
 	}
 
-	for i := 0; i < g.LookUpTableSize; i++ {
-		lutOut.Poly.B[i] = rotated[i]
-		lutOut.Poly.A[i] = 0
+	g.storeAssign(rotated, lutOut)
+	lutOut.MessageModulus = messageModulus
+}
+
+// storeAssign writes the raw table into the lookup table polynomials.
+// Coefficient k goes to polynomial k % polyExtendFactor at index k / polyExtendFactor;
+// for polyExtendFactor=1 this is a plain copy.
+func (g *Generator) storeAssign(lutRaw []params.Torus, lutOut *LookUpTable) {
+	polyExtendFactor := len(lutOut.Polys)
+	for i, p := range lutOut.Polys {
+		for j := 0; j < g.PolyDegree; j++ {
+			p.B[j] = lutRaw[j*polyExtendFactor+i]
+			p.A[j] = 0
+		}
+	}
+}
+
+// GenBivariateLookUpTable generates a lookup table for f(x, y) with x and y
+// in [0, messageModulus), evaluated on the packed input x*messageModulus + y
+// (see evaluator.BootstrapBivariate). The generator's message modulus must be
+// at least messageModulus^2; packed values above that map to 0.
+func (g *Generator) GenBivariateLookUpTable(f func(x, y int) int, messageModulus int) *LookUpTable {
+	return g.GenLookUpTable(func(v int) int {
+		x, y := v/messageModulus, v%messageModulus
+		if x >= messageModulus {
+			return 0
+		}
+		return f(x, y)
+	})
+}
+
+// GenManyLookUpTable generates a lookup table evaluating every function in fs
+// on the same input with one blind rotation (see evaluator.BootstrapManyLUT).
+//
+// After rounding, the rotation of the table is a multiple of k = len(fs), so
+// each aligned group of k coefficients holds f_0(x), ..., f_(k-1)(x) for the
+// message x whose box contains the group. k must divide the lookup table size
+// and be at most its size divided by the message modulus.
+func (g *Generator) GenManyLookUpTable(fs []func(int) int) *ManyLookUpTable {
+	lut := NewLookUpTableWithParams(g.Params)
+	g.GenManyLookUpTableAssign(fs, lut)
+	return &ManyLookUpTable{Table: lut, Count: len(fs)}
+}
+
+// GenManyLookUpTableAssign generates a many-function lookup table and writes to lutOut
+func (g *Generator) GenManyLookUpTableAssign(fs []func(int) int, lutOut *LookUpTable) {
+	messageModulus := g.Encoder.MessageModulus
+	k := len(fs)
+	if k == 0 || g.LookUpTableSize%k != 0 || k*messageModulus > g.LookUpTableSize {
+		panic("lut: function count does not fit the lookup table")
 	}
+
+	// Message of each coefficient of the unrotated table
+	messageOf := make([]int, g.LookUpTableSize)
+	for x := 0; x < messageModulus; x++ {
+		start := divRound(x*g.LookUpTableSize, messageModulus)
+		end := divRound((x+1)*g.LookUpTableSize, messageModulus)
+		for i := start; i < end; i++ {
+			messageOf[i] = x
+		}
+	}
+
+	// Same rotation and negated tail as GenLookUpTableAssign, but every
+	// coefficient takes the message of the first coefficient in its group
+	offset := divRound(g.LookUpTableSize, 2*messageModulus)
+	rotated := make([]params.Torus, g.LookUpTableSize)
+	for i := range rotated {
+		j := i % k
+		if src := i - j + offset; src < g.LookUpTableSize {
+			rotated[i] = g.Encoder.Encode(fs[j](messageOf[src]))
+		} else {
+			rotated[i] = -g.Encoder.Encode(fs[j](0))
+		}
+	}
+
+	g.storeAssign(rotated, lutOut)
+	lutOut.MessageModulus = messageModulus
 }
 
 // GenLookUpTableCustom generates a lookup table with custom message modulus and scale
 func (g *Generator) GenLookUpTableCustom(f func(int) int, messageModulus int, scale float64) *LookUpTable {
-	lut := NewLookUpTable()
+	lut := NewLookUpTableWithParams(g.Params)
 
 	oldEncoder := g.Encoder
 	g.Encoder = NewEncoderWithScale(messageModulus, scale)
@@ -11,37 +11,74 @@ import (
 // for programmable bootstrapping.
 // During blind rotation, the LUT is rotated based on the encrypted value,
 // effectively evaluating the function on the encrypted data.
+//
+// Parameter sets with LookUpTableSize > N use extended lookup tables made of
+// polyExtendFactor polynomials. Coefficient k of the full table is stored in
+// Polys[k % polyExtendFactor] at index k / polyExtendFactor.
 type LookUpTable struct {
-	// Polynomial encoding the function values
+	// Polynomial encoding the function values (Polys[0])
 	Poly *trlwe.TRLWELv1
+
+	// Polys holds all polyExtendFactor polynomials of the table.
+	// For standard parameter sets it contains only Poly.
+	Polys []*trlwe.TRLWELv1
+
+	// MessageModulus is the number of input messages the table was generated
+	// for, which sets the noise margin of its inputs. 0 means unknown.
+	MessageModulus int
+}
+
+// ManyLookUpTable packs several functions of the same input into one lookup
+// table, so that a single blind rotation evaluates all of them (multi-value
+// bootstrapping). Function j is read from coefficient j of the rotated table.
+type ManyLookUpTable struct {
+	Table *LookUpTable
+
+	// Count is the number of functions. Inputs are rounded to a multiple of
+	// Count before the blind rotation, which costs log2(Count) bits of the
+	// modulus switch precision.
+	Count int
 }
 
-// NewLookUpTable creates a new lookup table
+// NewLookUpTable creates a new lookup table for the current security level
 func NewLookUpTable() *LookUpTable {
+	return NewLookUpTableWithParams(params.Current())
+}
+
+// NewLookUpTableWithParams creates a new lookup table for parameter set p
+func NewLookUpTableWithParams(p params.Parameters) *LookUpTable {
+	polys := make([]*trlwe.TRLWELv1, p.PolyExtendFactor())
+	for i := range polys {
+		polys[i] = trlwe.NewTRLWELv1WithParams(p)
+	}
 	return &LookUpTable{
-		Poly: trlwe.NewTRLWELv1(),
+		Poly:  polys[0],
+		Polys: polys,
 	}
 }
 
 // Copy returns a deep copy of the lookup table
 func (lut *LookUpTable) Copy() *LookUpTable {
-	result := NewLookUpTable()
-	copy(result.Poly.A, lut.Poly.A)
-	copy(result.Poly.B, lut.Poly.B)
+	result := NewLookUpTableWithParams(lut.Poly.Params)
+	result.CopyFrom(lut)
 	return result
 }
 
 // CopyFrom copies values from another lookup table
 func (lut *LookUpTable) CopyFrom(other *LookUpTable) {
-	copy(lut.Poly.A, other.Poly.A)
-	copy(lut.Poly.B, other.Poly.B)
+	for i, p := range lut.Polys {
+		copy(p.A, other.Polys[i].A)
+		copy(p.B, other.Polys[i].B)
+	}
+	lut.MessageModulus = other.MessageModulus
 }
 
 // Clear clears the lookup table (sets all coefficients to 0)
 func (lut *LookUpTable) Clear() {
-	n := params.GetTRGSWLv1().N
-	for i := 0; i < n; i++ {
-		lut.Poly.A[i] = 0
-		lut.Poly.B[i] = 0
+	for _, p := range lut.Polys {
+		for i := range p.A {
+			p.A[i] = 0
+			p.B[i] = 0
+		}
 	}
 }
@@ -160,6 +160,32 @@ func TestGeneratorCustomModulus(t *testing.T) {
 	}
 }
 
+func TestGeneratorExtended(t *testing.T) {
+	// Uint6 uses LookUpTableSize = 2 * N, split over two polynomials
+	p := params.GetParameters(params.SecurityUint6)
+	const messageModulus = 64
+	gen := NewGeneratorWithParams(p, messageModulus)
+
+	if gen.LookUpTableSize != 2*p.TRGSWLv1.N {
+		t.Fatalf("LookUpTableSize = %d, want %d", gen.LookUpTableSize, 2*p.TRGSWLv1.N)
+	}
+
+	f := func(x int) int { return (messageModulus - 1) - x }
+	lut := gen.GenLookUpTable(f)
+	if len(lut.Polys) != 2 || lut.Poly != lut.Polys[0] {
+		t.Fatalf("extended LUT has %d polynomials, want 2 with Poly == Polys[0]", len(lut.Polys))
+	}
+
+	// Coefficient k of the full table lives in Polys[k%2] at index k/2.
+	// After the half-slot rotation, index x*L/m is the centre of message x.
+	for x := 0; x < messageModulus; x++ {
+		k := x * gen.LookUpTableSize / messageModulus
+		if got, want := lut.Polys[k%2].B[k/2], gen.Encoder.Encode(f(x)); got != want {
+			t.Errorf("LUT[%d] for message %d = %d, want %d", k, x, got, want)
+		}
+	}
+}
+
 func TestModSwitch(t *testing.T) {
 	gen := NewGenerator(2)
 	n := params.GetTRGSWLv1().N
@@ -0,0 +1,94 @@
+// Package noise analyzes the noise of TFHE parameter sets.
+//
+// Analyze derives the noise of every step of a bootstrap from the
+// parameters alone (see params.Parameters.BootstrapVariance) and turns it
+// into failure probabilities for gates and lookup tables. Sample measures
+// the same quantities by decrypting the phase errors of real ciphertexts
+// with the secret key, to cross-check the analysis.
+package noise
+
+import (
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/thedonutfactory/go-tfhe/evaluator"
+	"github.com/thedonutfactory/go-tfhe/params"
+)
+
+// Analysis holds the noise estimates of a parameter set as standard
+// deviations, in fractions of the torus
+type Analysis struct {
+	Params params.Parameters
+
+	Fresh       float64 // Level 0 encryption with the secret key
+	BlindRotate float64 // Sample extracted after a blind rotation
+	KeySwitch   float64 // Added by the key switch from level 1 to level 0
+	Bootstrap   float64 // Output of a bootstrap: blind rotation and key switch
+	ModSwitch   float64 // Rounding to the lookup table modulus at the start of a bootstrap
+}
+
+// Analyze computes the noise estimates of parameter set p
+func Analyze(p params.Parameters) Analysis {
+	return Analysis{
+		Params:      p,
+		Fresh:       math.Sqrt(p.FreshVariance()),
+		BlindRotate: math.Sqrt(p.BlindRotateVariance()),
+		KeySwitch:   math.Sqrt(p.KeySwitchVariance()),
+		Bootstrap:   math.Sqrt(p.BootstrapVariance()),
+		ModSwitch:   math.Sqrt(p.ModSwitchVariance()),
+	}
+}
+
+// GateFailure returns the failure probability of a two-input gate on
+// bootstrapped inputs. XOR is the worst case: PrepareXOR doubles one of its
+// inputs, so the bootstrap sees five times the bootstrap variance.
+func (a Analysis) GateFailure() float64 {
+	variance := 5*a.Bootstrap*a.Bootstrap + a.ModSwitch*a.ModSwitch
+	return evaluator.FailureProbability(variance, evaluator.GateNoiseMargin)
+}
+
+// LUTFailure returns the failure probability of a lookup table bootstrap
+// on a bootstrapped input encoded with the given message modulus
+func (a Analysis) LUTFailure(messageModulus int) float64 {
+	variance := a.Bootstrap*a.Bootstrap + a.ModSwitch*a.ModSwitch
+	return evaluator.FailureProbability(variance, evaluator.LUTNoiseMargin(messageModulus))
+}
+
+// BivariateFailure returns the failure probability of a bivariate lookup
+// table on two bootstrapped inputs: the first is scaled by messageModulus
+// and added to the second, and the sum is encoded with packedModulus (see
+// evaluator.Evaluator.BootstrapBivariate).
+func (a Analysis) BivariateFailure(messageModulus, packedModulus int) float64 {
+	m := float64(messageModulus)
+	variance := (m*m+1)*a.Bootstrap*a.Bootstrap + a.ModSwitch*a.ModSwitch
+	return evaluator.FailureProbability(variance, evaluator.LUTNoiseMargin(packedModulus))
+}
+
+// MaxMessageModulus returns the largest power-of-two message modulus whose
+// lookup tables fail with a probability of at most threshold, or 0 if none
+// does. It is bounded by half the lookup table size, which leaves one
+// coefficient per message.
+func (a Analysis) MaxMessageModulus(threshold float64) int {
+	best := 0
+	for m := 2; 2*m <= a.Params.LookUpTableSize(); m *= 2 {
+		if a.LUTFailure(m) > threshold {
+			break
+		}
+		best = m
+	}
+	return best
+}
+
+// String formats the estimates as log2 of the standard deviations, with the
+// failure probabilities of gates and of the widest safe lookup table
+func (a Analysis) String() string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "fresh 2^%.1f, blind rotation 2^%.1f, key switch 2^%.1f, bootstrap 2^%.1f, modulus switch 2^%.1f\n",
+		math.Log2(a.Fresh), math.Log2(a.BlindRotate), math.Log2(a.KeySwitch), math.Log2(a.Bootstrap), math.Log2(a.ModSwitch))
+	fmt.Fprintf(&sb, "gate failure %.3g", a.GateFailure())
+	if m := a.MaxMessageModulus(evaluator.DefaultNoiseThreshold); m > 0 {
+		fmt.Fprintf(&sb, ", lookup tables up to messageModulus=%d (failure %.3g)", m, a.LUTFailure(m))
+	}
+	return sb.String()
+}
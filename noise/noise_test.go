@@ -0,0 +1,77 @@
+package noise_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/thedonutfactory/go-tfhe/cloudkey"
+	"github.com/thedonutfactory/go-tfhe/evaluator"
+	"github.com/thedonutfactory/go-tfhe/key"
+	"github.com/thedonutfactory/go-tfhe/noise"
+	"github.com/thedonutfactory/go-tfhe/params"
+)
+
+func TestAnalyzeUintProfiles(t *testing.T) {
+	// With a uniform binary key, the modulus switch noise of Uint5-Uint8
+	// leaves half of their width safe; the lower weight of block binary keys
+	// makes the full width safe
+	testCases := []struct {
+		level          params.SecurityLevel
+		messageModulus int
+		uniformModulus int // Widest safe message modulus with a uniform binary key
+		blockSize      int // Block size making messageModulus safe
+	}{
+		{params.SecurityUint1, 2, 2, 1},
+		{params.SecurityUint2, 4, 4, 1},
+		{params.SecurityUint3, 8, 8, 1},
+		{params.SecurityUint4, 16, 16, 1},
+		{params.SecurityUint5, 32, 16, 7},
+		{params.SecurityUint6, 64, 32, 7},
+		{params.SecurityUint7, 128, 64, 8},
+		{params.SecurityUint8, 256, 128, 8},
+	}
+	for _, tc := range testCases {
+		p := params.GetParameters(tc.level)
+		if m := noise.Analyze(p).MaxMessageModulus(evaluator.DefaultNoiseThreshold); m != tc.uniformModulus {
+			t.Errorf("Uint%d: MaxMessageModulus = %d with a uniform binary key, want %d", tc.level, m, tc.uniformModulus)
+		}
+
+		p.TRGSWLv1.BlockSize = tc.blockSize
+		a := noise.Analyze(p)
+		if got := math.Hypot(a.BlindRotate, a.KeySwitch); math.Abs(got-a.Bootstrap) > 1e-12 {
+			t.Errorf("Uint%d: bootstrap noise %g, want %g", tc.level, a.Bootstrap, got)
+		}
+		if m := a.MaxMessageModulus(evaluator.DefaultNoiseThreshold); m < tc.messageModulus {
+			t.Errorf("Uint%d: MaxMessageModulus = %d, want at least %d", tc.level, m, tc.messageModulus)
+		}
+		if a.LUTFailure(tc.messageModulus) >= a.LUTFailure(2*tc.messageModulus) {
+			t.Errorf("Uint%d: LUTFailure does not grow with the message modulus", tc.level)
+		}
+		if a.BivariateFailure(2, tc.messageModulus) < a.LUTFailure(tc.messageModulus) {
+			t.Errorf("Uint%d: BivariateFailure below LUTFailure", tc.level)
+		}
+	}
+}
+
+func TestSampleMatchesAnalysis(t *testing.T) {
+	p := params.GetParameters(params.Security80Bit)
+	sk := key.NewSecretKeyWithParams(p)
+	ck := cloudkey.NewCloudKey(sk)
+
+	a := noise.Analyze(p)
+	m := noise.Sample(sk, ck, 40)
+	testCases := []struct {
+		name               string
+		estimate, measured float64
+	}{
+		{"fresh", a.Fresh, m.Fresh},
+		{"blind rotation", a.BlindRotate, m.BlindRotate},
+		{"key switch", a.KeySwitch, m.KeySwitch},
+		{"bootstrap", a.Bootstrap, m.Bootstrap},
+	}
+	for _, tc := range testCases {
+		if ratio := tc.measured / tc.estimate; ratio < 0.5 || ratio > 2 {
+			t.Errorf("%s noise measured %.3g, estimated %.3g", tc.name, tc.measured, tc.estimate)
+		}
+	}
+}
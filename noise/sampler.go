@@ -0,0 +1,89 @@
+package noise
+
+import (
+	"math"
+
+	"github.com/thedonutfactory/go-tfhe/cloudkey"
+	"github.com/thedonutfactory/go-tfhe/csprng"
+	"github.com/thedonutfactory/go-tfhe/evaluator"
+	"github.com/thedonutfactory/go-tfhe/key"
+	"github.com/thedonutfactory/go-tfhe/params"
+	"github.com/thedonutfactory/go-tfhe/tlwe"
+	"github.com/thedonutfactory/go-tfhe/trgsw"
+	"github.com/thedonutfactory/go-tfhe/trlwe"
+	"github.com/thedonutfactory/go-tfhe/utils"
+)
+
+// Measurement holds the noise measured by Sample as standard deviations, in
+// fractions of the torus, with the same meaning as the fields of Analysis
+type Measurement struct {
+	Samples int
+
+	Fresh       float64
+	BlindRotate float64
+	KeySwitch   float64
+	Bootstrap   float64
+}
+
+// Sample measures the noise of ck's parameter set from samples ciphertexts
+// of each kind, decrypting their phase errors with sk:
+//   - fresh level 0 encryptions of 1/8;
+//   - blind rotations of those with the gate test vector, extracted and
+//     decrypted with the level 1 key;
+//   - key switches of noiseless level 1 encryptions;
+//   - full gate bootstraps.
+//
+// The standard error of each measurement is about 1/sqrt(2*samples) of it.
+// ck must hold a key switching key.
+func Sample(sk *key.SecretKey, ck *cloudkey.CloudKey, samples int) Measurement {
+	p := ck.Params
+	eval := evaluator.NewEvaluatorWithParams(p)
+	mu := utils.F64ToTorus(0.125)
+
+	rotated := trlwe.NewTRLWELv1WithParams(p)
+	extracted := tlwe.NewTLWELv1WithParams(p)
+	switched := tlwe.NewTLWELv0WithParams(p)
+	bootstrapped := tlwe.NewTLWELv0WithParams(p)
+
+	src := csprng.Fork()
+	var fresh, blindRotate, keySwitch, bootstrap float64
+	for i := 0; i < samples; i++ {
+		ct := tlwe.NewTLWELv0WithParams(p).EncryptF64WithSource(0.125, p.TLWELv0.ALPHA, sk.KeyLv0, src)
+		fresh += square(phaseError(ct.P, sk.KeyLv0, mu))
+
+		eval.BlindRotateAssign(ct, ck.BlindRotateTestvec, ck.BootstrappingKey, ck.DecompositionOffset, rotated)
+		trlwe.SampleExtractIndexAssign(rotated, 0, extracted)
+		blindRotate += square(phaseError(extracted.P, sk.KeyLv1, mu))
+
+		lv1 := tlwe.NewTLWELv1WithParams(p).EncryptF64WithSource(0.125, 0, sk.KeyLv1, src)
+		trgsw.IdentityKeySwitchingAssign(lv1, ck.KeySwitchingKey, switched)
+		keySwitch += square(phaseError(switched.P, sk.KeyLv0, mu))
+
+		eval.BootstrapAssign(ct, ck.BlindRotateTestvec, ck.BootstrappingKey, ck.KeySwitchingKey, ck.DecompositionOffset, bootstrapped)
+		bootstrap += square(phaseError(bootstrapped.P, sk.KeyLv0, mu))
+	}
+
+	n := float64(samples)
+	return Measurement{
+		Samples:     samples,
+		Fresh:       math.Sqrt(fresh / n),
+		BlindRotate: math.Sqrt(blindRotate / n),
+		KeySwitch:   math.Sqrt(keySwitch / n),
+		Bootstrap:   math.Sqrt(bootstrap / n),
+	}
+}
+
+// phaseError returns the phase of the LWE sample ct (body last) under key,
+// minus the expected message mu, as a signed fraction of the torus
+func phaseError(ct []params.Torus, key []params.Torus, mu params.Torus) float64 {
+	n := len(ct) - 1
+	var innerProduct params.Torus
+	for i := 0; i < n; i++ {
+		innerProduct += ct[i] * key[i]
+	}
+	return float64(int32(ct[n]-innerProduct-mu)) / float64(uint64(1)<<32)
+}
+
+func square(x float64) float64 {
+	return x * x
+}
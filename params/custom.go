@@ -0,0 +1,182 @@
+package params
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"math/bits"
+)
+
+// ErrInvalidParams is returned when a parameter set breaks one of the
+// invariants checked by Validate
+var ErrInvalidParams = errors.New("params: invalid parameter set")
+
+// SecurityCustom is the level of custom parameter sets made by a Builder.
+// Register gives each registered set a level of its own above it.
+const SecurityCustom SecurityLevel = 1000
+
+// minPolyDegree is the smallest polynomial degree the FFT supports (poly.MinDegree)
+const minPolyDegree = 1 << 4
+
+const (
+	// fft64LimbBits bounds the balanced 16-bit limbs of poly.FourierPoly64
+	// to 2^15 in absolute value
+	fft64LimbBits = 15
+	// fft64ExactBits is the size of the sums of products the float64 FFT
+	// computes exactly
+	fft64ExactBits = 50
+)
+
+// Validate checks the invariants the rest of the library relies on:
+//   - positive dimensions and noise;
+//   - a power-of-two polynomial degree N, shared by level 1, with NBIT == log2(N);
+//   - BG == 1<<BGBIT and L*BGBIT <= 32, so the gadget decomposition fits the torus;
+//   - IKS_T*BASEBIT <= 32, so the key switching decomposition fits the torus;
+//   - a block size of at most the level 0 dimension;
+//   - a lookup table size of 0 or a multiple of N.
+func (p Parameters) Validate() error {
+	return p.validate(32)
+}
+
+// Validate64 checks the invariants of Validate for the 64-bit torus of the
+// torus64 package, on which the gadget and key switching decompositions may
+// use up to 64 bits, and the limits of that package:
+//   - (BGBIT-1) + 15 + log2(2*L*N) <= 50: an external product sums 2*L*N
+//     products of a digit and a 16-bit limb, which the float64 FFT only
+//     computes exactly up to about 50 bits (see poly.FourierPoly64);
+//   - a lookup table size of at most N, as extended tables are not supported.
+func (p Parameters) Validate64() error {
+	if err := p.validate(64); err != nil {
+		return err
+	}
+	trgsw := p.TRGSWLv1
+	productBits := float64(trgsw.BGBIT-1) + fft64LimbBits + math.Log2(float64(2*trgsw.L*trgsw.N))
+	switch {
+	case productBits > fft64ExactBits:
+		return invalid("(BGBIT-1) + %d + log2(2*L*N) = %.1f exceeds the %d bits the 64-bit FFT computes exactly",
+			fft64LimbBits, productBits, fft64ExactBits)
+	case p.LookUpTableSize() > trgsw.N:
+		return invalid("LookUpTableSize = %d exceeds N = %d: extended lookup tables are not supported on the 64-bit torus",
+			p.LookUpTableSize(), trgsw.N)
+	}
+	return nil
+}
+
+// validate checks the invariants of Validate on a torus of torusBits bits
+func (p Parameters) validate(torusBits int) error {
+	lv0, lv1, trgsw := p.TLWELv0, p.TLWELv1, p.TRGSWLv1
+	switch {
+	case lv0.N <= 0 || lv0.ALPHA <= 0:
+		return invalid("level 0 dimension %d and noise %g must be positive", lv0.N, lv0.ALPHA)
+	case lv1.ALPHA <= 0 || p.TRLWELv1.ALPHA <= 0 || trgsw.ALPHA <= 0:
+		return invalid("level 1 noise must be positive")
+	case trgsw.N < minPolyDegree || trgsw.N&(trgsw.N-1) != 0:
+		return invalid("N = %d is not a power of two of at least %d", trgsw.N, minPolyDegree)
+	case lv1.N != trgsw.N || p.TRLWELv1.N != trgsw.N:
+		return invalid("TLWELv1.N = %d and TRLWELv1.N = %d differ from N = %d", lv1.N, p.TRLWELv1.N, trgsw.N)
+	case trgsw.NBIT != bits.Len(uint(trgsw.N))-1:
+		return invalid("NBIT = %d, want log2(N) = %d", trgsw.NBIT, bits.Len(uint(trgsw.N))-1)
+	case trgsw.BGBIT == 0 || trgsw.BGBIT >= 32 || trgsw.BG != 1<<trgsw.BGBIT:
+		return invalid("BG = %d, want 1<<BGBIT with BGBIT = %d in [1, 31]", trgsw.BG, trgsw.BGBIT)
+	case trgsw.L <= 0 || trgsw.L*int(trgsw.BGBIT) > torusBits:
+		return invalid("L*BGBIT = %d*%d must be in [1, %d]", trgsw.L, trgsw.BGBIT, torusBits)
+	case trgsw.BASEBIT <= 0 || trgsw.IKS_T <= 0 || trgsw.IKS_T*trgsw.BASEBIT > torusBits:
+		return invalid("IKS_T*BASEBIT = %d*%d must be in [1, %d]", trgsw.IKS_T, trgsw.BASEBIT, torusBits)
+	case trgsw.BlockSize < 0 || trgsw.BlockSize > lv0.N:
+		return invalid("BlockSize = %d must be in [0, %d]", trgsw.BlockSize, lv0.N)
+	case trgsw.LookUpTableSize < 0 || trgsw.LookUpTableSize%trgsw.N != 0:
+		return invalid("LookUpTableSize = %d is not a multiple of N = %d", trgsw.LookUpTableSize, trgsw.N)
+	}
+	return nil
+}
+
+func invalid(msg string, args ...any) error {
+	return fmt.Errorf("%w: %s", ErrInvalidParams, fmt.Sprintf(msg, args...))
+}
+
+// Builder assembles a custom parameter set. The setters fill in the fields
+// that follow from others (BG from BGBIT, NBIT from N, the level 1 noise of
+// every level 1 structure), and Build validates the result:
+//
+//	p, err := params.NewBuilder(params.GetParameters(params.SecurityUint5)).
+//		Lv0(1100, 5e-8).
+//		KeySwitch(4, 8).
+//		Build()
+type Builder struct {
+	p Parameters
+}
+
+// NewBuilder starts a custom parameter set from base. Pass Parameters{} to
+// start from scratch.
+func NewBuilder(base Parameters) *Builder {
+	base.Level = SecurityCustom
+	return &Builder{p: base}
+}
+
+// Lv0 sets the level 0 dimension and noise, also used by the key switching key
+func (b *Builder) Lv0(n int, alpha float64) *Builder {
+	b.p.TLWELv0 = TLWELv0Params{N: n, ALPHA: alpha}
+	return b
+}
+
+// Lv1 sets the polynomial degree and the level 1 noise, also used by the
+// bootstrapping key
+func (b *Builder) Lv1(n int, alpha float64) *Builder {
+	b.p.TLWELv1 = TLWELv1Params{N: n, ALPHA: alpha}
+	b.p.TRLWELv1 = TRLWELv1Params{N: n, ALPHA: alpha}
+	b.p.TRGSWLv1.N = n
+	b.p.TRGSWLv1.NBIT = bits.Len(uint(n)) - 1
+	b.p.TRGSWLv1.ALPHA = alpha
+	return b
+}
+
+// Decomposition sets the gadget decomposition of the bootstrapping key:
+// l levels of base 2^bgbit
+func (b *Builder) Decomposition(bgbit uint32, l int) *Builder {
+	b.p.TRGSWLv1.BGBIT = bgbit
+	b.p.TRGSWLv1.BG = 0
+	if bgbit < 32 {
+		b.p.TRGSWLv1.BG = 1 << bgbit
+	}
+	b.p.TRGSWLv1.L = l
+	return b
+}
+
+// KeySwitch sets the decomposition of the key switching key: t levels of
+// base 2^basebit
+func (b *Builder) KeySwitch(basebit, t int) *Builder {
+	b.p.TRGSWLv1.BASEBIT = basebit
+	b.p.TRGSWLv1.IKS_T = t
+	return b
+}
+
+// BlockSize sets the block size of block blind rotation (1 for one CMux per
+// coefficient)
+func (b *Builder) BlockSize(size int) *Builder {
+	b.p.TRGSWLv1.BlockSize = size
+	return b
+}
+
+// LookUpTableSize sets the size of programmable bootstrapping lookup tables
+// (0 for N)
+func (b *Builder) LookUpTableSize(size int) *Builder {
+	b.p.TRGSWLv1.LookUpTableSize = size
+	return b
+}
+
+// Build validates the parameter set and returns it with level SecurityCustom
+func (b *Builder) Build() (Parameters, error) {
+	if err := b.p.Validate(); err != nil {
+		return Parameters{}, err
+	}
+	return b.p, nil
+}
+
+// Register builds the parameter set and registers it under name (see Register)
+func (b *Builder) Register(name string) (Parameters, error) {
+	p, err := b.Build()
+	if err != nil {
+		return Parameters{}, err
+	}
+	return Register(name, p)
+}
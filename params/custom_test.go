@@ -0,0 +1,214 @@
+package params_test
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/thedonutfactory/go-tfhe/params"
+)
+
+func TestValidate(t *testing.T) {
+	for _, level := range []params.SecurityLevel{
+		params.Security80Bit, params.Security110Bit, params.Security128Bit,
+		params.SecurityUint1, params.SecurityUint2, params.SecurityUint3, params.SecurityUint4,
+		params.SecurityUint5, params.SecurityUint6, params.SecurityUint7, params.SecurityUint8,
+	} {
+		if err := params.GetParameters(level).Validate(); err != nil {
+			t.Errorf("level %d: %v", level, err)
+		}
+	}
+
+	base := params.GetParameters(params.Security128Bit)
+	testCases := []struct {
+		name   string
+		modify func(p *params.Parameters)
+	}{
+		{"N not a power of two", func(p *params.Parameters) { p.TLWELv1.N, p.TRLWELv1.N, p.TRGSWLv1.N = 1000, 1000, 1000 }},
+		{"level 1 dimensions differ", func(p *params.Parameters) { p.TLWELv1.N = 2048 }},
+		{"NBIT", func(p *params.Parameters) { p.TRGSWLv1.NBIT = 11 }},
+		{"BG", func(p *params.Parameters) { p.TRGSWLv1.BG = 1000 }},
+		{"L*BGBIT", func(p *params.Parameters) { p.TRGSWLv1.L = 6 }},
+		{"IKS_T*BASEBIT", func(p *params.Parameters) { p.TRGSWLv1.IKS_T = 17 }},
+		{"no noise", func(p *params.Parameters) { p.TLWELv0.ALPHA = 0 }},
+		{"lookup table size", func(p *params.Parameters) { p.TRGSWLv1.LookUpTableSize = 1536 }},
+	}
+	for _, tc := range testCases {
+		p := base
+		tc.modify(&p)
+		if err := p.Validate(); !errors.Is(err, params.ErrInvalidParams) {
+			t.Errorf("%s: Validate() = %v, want ErrInvalidParams", tc.name, err)
+		}
+	}
+}
+
+func TestValidate64(t *testing.T) {
+	p := params.GetParameters(params.SecurityUint5)
+	p.TRGSWLv1.L = 2     // L*BGBIT = 44
+	p.TRGSWLv1.IKS_T = 8 // IKS_T*BASEBIT = 48
+	if err := p.Validate(); !errors.Is(err, params.ErrInvalidParams) {
+		t.Errorf("Validate() = %v, want ErrInvalidParams", err)
+	}
+	if err := p.Validate64(); err != nil {
+		t.Errorf("Validate64() = %v", err)
+	}
+
+	testCases := []struct {
+		name   string
+		modify func(p *params.Parameters)
+	}{
+		{"L*BGBIT = 66", func(p *params.Parameters) { p.TRGSWLv1.L = 3 }},
+		// 30 + 15 + log2(2*2*2048) = 58 bits of FFT products
+		{"FFT precision", func(p *params.Parameters) { p.TRGSWLv1.BGBIT, p.TRGSWLv1.BG = 31, 1<<31 }},
+		{"extended lookup table", func(p *params.Parameters) { p.TRGSWLv1.LookUpTableSize = 2 * p.TRGSWLv1.N }},
+	}
+	for _, tc := range testCases {
+		q := p
+		tc.modify(&q)
+		if err := q.Validate64(); !errors.Is(err, params.ErrInvalidParams) {
+			t.Errorf("%s: Validate64() = %v, want ErrInvalidParams", tc.name, err)
+		}
+	}
+
+	for _, level := range []params.SecurityLevel{params.SecurityUint2, params.SecurityUint4, params.SecurityUint5} {
+		if err := params.GetParameters(level).Validate64(); err != nil {
+			t.Errorf("security level %d: Validate64() = %v", level, err)
+		}
+	}
+	if err := params.GetParameters(params.SecurityUint6).Validate64(); !errors.Is(err, params.ErrInvalidParams) {
+		t.Errorf("Uint6: Validate64() = %v, want ErrInvalidParams for its extended lookup tables", err)
+	}
+}
+
+func TestBuilder(t *testing.T) {
+	p, err := params.NewBuilder(params.Parameters{}).
+		Lv0(600, 3.0e-5).
+		Lv1(1024, 2.0e-8).
+		Decomposition(8, 3).
+		KeySwitch(2, 8).
+		BlockSize(3).
+		Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p.Level != params.SecurityCustom || p.TRGSWLv1.BG != 256 || p.TRGSWLv1.NBIT != 10 || p.TRLWELv1.ALPHA != 2.0e-8 {
+		t.Errorf("Build() = %+v", p)
+	}
+
+	if _, err := params.NewBuilder(p).Decomposition(12, 3).Build(); !errors.Is(err, params.ErrInvalidParams) {
+		t.Errorf("L*BGBIT = 36: Build() = %v, want ErrInvalidParams", err)
+	}
+
+	registered, err := params.NewBuilder(p).Register("test-builder")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if registered.Level <= params.SecurityCustom {
+		t.Errorf("registered level %d, want above SecurityCustom", registered.Level)
+	}
+	if got := params.GetParameters(registered.Level); got != registered {
+		t.Errorf("GetParameters(%d) = %+v, want the registered set", registered.Level, got)
+	}
+	if got, err := params.Lookup("test-builder"); err != nil || got != registered {
+		t.Errorf("Lookup() = %+v, %v", got, err)
+	}
+	if info := registered.SecurityInfo(); !strings.Contains(info, `"test-builder"`) {
+		t.Errorf("SecurityInfo() = %q, want the registered name", info)
+	}
+	if _, err := params.Register("test-builder", p); !errors.Is(err, params.ErrNameTaken) {
+		t.Errorf("registering a name twice: %v, want ErrNameTaken", err)
+	}
+	if _, err := params.Register("uint5", p); !errors.Is(err, params.ErrNameTaken) {
+		t.Errorf("registering a predefined name: %v, want ErrNameTaken", err)
+	}
+	if _, err := params.Lookup("missing"); !errors.Is(err, params.ErrUnknownParams) {
+		t.Errorf("Lookup(missing) = %v, want ErrUnknownParams", err)
+	}
+}
+
+func TestLoadFile(t *testing.T) {
+	const yamlSpec = `# Uint3 with a larger level 0 key
+---
+name: "test-yaml"
+base: uint3
+lv0:
+  n: 900     # dimension
+  alpha: 2.5e-06
+key_switch:
+  basebit: 2
+  t: 9
+`
+	const jsonSpec = `{
+		"name": "test-json",
+		"base": "80bit",
+		"min_security": 80,
+		"decomposition": {"bgbit": 8, "l": 3},
+		"block_size": 4
+	}`
+
+	dir := t.TempDir()
+	write := func(name, data string) string {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte(data), 0o600); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+
+	p, err := params.LoadFile(write("p.yaml", yamlSpec))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := params.GetParameters(params.SecurityUint3)
+	want.Level = p.Level
+	want.TLWELv0 = params.TLWELv0Params{N: 900, ALPHA: 2.5e-06}
+	want.TRGSWLv1.BASEBIT, want.TRGSWLv1.IKS_T = 2, 9
+	if p != want {
+		t.Errorf("LoadFile(yaml) = %+v, want %+v", p, want)
+	}
+
+	p, err = params.LoadFile(write("p.json", jsonSpec))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p.TRGSWLv1.BG != 256 || p.TRGSWLv1.L != 3 || p.TRGSWLv1.BlockSize != 4 || p.TLWELv0.N != 550 {
+		t.Errorf("LoadFile(json) = %+v", p)
+	}
+	if got, err := params.Lookup("test-json"); err != nil || got != p {
+		t.Errorf("Lookup(test-json) = %+v, %v", got, err)
+	}
+
+	// Plain scalars that look like numbers are strings in string fields
+	p, err = params.LoadFile(write("n.yaml", "name: 2048\nbase: uint3\nlv0:\n  n: 1_024\n  alpha: 2.5e-06\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p.TLWELv0.N != 1024 {
+		t.Errorf("LoadFile(name: 2048) = %+v", p)
+	}
+	if got, err := params.Lookup("2048"); err != nil || got != p {
+		t.Errorf("Lookup(2048) = %+v, %v", got, err)
+	}
+
+	testCases := []struct {
+		name, file, data string
+		err              error
+	}{
+		{"unknown field", "a.json", `{"lv0": {"n": 600, "sigma": 1}}`, params.ErrParamsFile},
+		{"sequence", "b.yaml", "lv0:\n  - 600\n", params.ErrParamsFile},
+		{"bad indentation", "c.yaml", "lv0:\n    n: 600\n  alpha: 1e-5\n", params.ErrParamsFile},
+		{"tab", "d.yaml", "lv0:\n\tn: 600\n", params.ErrParamsFile},
+		{"invariant", "e.yaml", "base: 128bit\ndecomposition:\n  bgbit: 12\n  l: 3\n", params.ErrInvalidParams},
+		{"below min_security", "f.yml", "base: uint5\nmin_security: 80\n", params.ErrInsecure},
+		{"unknown base", "g.yaml", "base: uint9\n", params.ErrUnknownParams},
+		{"numeric base", "i.yaml", "base: 128\n", params.ErrUnknownParams},
+		{"extension", "h.toml", "", params.ErrParamsFile},
+	}
+	for _, tc := range testCases {
+		if _, err := params.LoadFile(write(tc.file, tc.data)); !errors.Is(err, tc.err) {
+			t.Errorf("%s: LoadFile() = %v, want %v", tc.name, err, tc.err)
+		}
+	}
+}
@@ -0,0 +1,207 @@
+package params
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// BinaryFormatVersion is the version of the binary serialization format.
+// It is bumped whenever the on-disk layout changes incompatibly. Version 2
+// added the lookup table size to the header.
+const BinaryFormatVersion uint16 = 2
+
+// binaryMagic identifies a go-tfhe binary blob
+var binaryMagic = [4]byte{'G', 'T', 'F', 'H'}
+
+// ObjectType identifies what kind of object follows a serialization header
+type ObjectType uint16
+
+const (
+	ObjectSecretKey ObjectType = iota + 1
+	ObjectCloudKey
+	ObjectTLWELv0
+	ObjectTLWELv1
+	ObjectTRLWELv1
+)
+
+// String returns a human readable name for the object type
+func (o ObjectType) String() string {
+	switch o {
+	case ObjectSecretKey:
+		return "SecretKey"
+	case ObjectCloudKey:
+		return "CloudKey"
+	case ObjectTLWELv0:
+		return "TLWELv0"
+	case ObjectTLWELv1:
+		return "TLWELv1"
+	case ObjectTRLWELv1:
+		return "TRLWELv1"
+	default:
+		return fmt.Sprintf("ObjectType(%d)", uint16(o))
+	}
+}
+
+var (
+	// ErrInvalidHeader is returned when a blob does not start with a valid go-tfhe header
+	ErrInvalidHeader = errors.New("params: invalid serialization header")
+	// ErrParamsMismatch is returned when a blob was produced with a different parameter set
+	ErrParamsMismatch = errors.New("params: serialized parameter set does not match")
+	// ErrNoParams is returned when serializing an object that has no parameter set
+	ErrNoParams = errors.New("params: object has no parameter set")
+)
+
+// Header is written in front of every serialized key or ciphertext.
+// It records the format version, the object type and the complete
+// parameter set the object was produced with, so that a blob can never be
+// silently loaded under incompatible parameters.
+type Header struct {
+	Version uint16
+	Object  ObjectType
+	Params  Parameters
+}
+
+// headerWire is the fixed-size little-endian layout of Header
+type headerWire struct {
+	Magic   [4]byte
+	Version uint16
+	Object  uint16
+	Level   int32
+
+	Lv0N     uint32
+	Lv0Alpha float64
+	Lv1N     uint32
+	Lv1Alpha float64
+
+	TRLWEN     uint32
+	TRLWEAlpha float64
+
+	TRGSWN         uint32
+	TRGSWNBit      uint32
+	TRGSWBGBit     uint32
+	TRGSWBG        uint32
+	TRGSWL         uint32
+	TRGSWBaseBit   uint32
+	TRGSWIKST      uint32
+	TRGSWAlpha     float64
+	TRGSWBlockSize uint32
+	TRGSWLUTSize   uint32
+}
+
+// HeaderSize is the encoded size of a Header in bytes
+var HeaderSize = binary.Size(headerWire{})
+
+// NewHeader creates a header for the given object type and parameter set
+func NewHeader(object ObjectType, p Parameters) Header {
+	return Header{
+		Version: BinaryFormatVersion,
+		Object:  object,
+		Params:  p,
+	}
+}
+
+// WriteTo writes the encoded header to w
+func (h Header) WriteTo(w io.Writer) (int64, error) {
+	p := h.Params
+	wire := headerWire{
+		Magic:   binaryMagic,
+		Version: h.Version,
+		Object:  uint16(h.Object),
+		Level:   int32(p.Level),
+
+		Lv0N:     uint32(p.TLWELv0.N),
+		Lv0Alpha: p.TLWELv0.ALPHA,
+		Lv1N:     uint32(p.TLWELv1.N),
+		Lv1Alpha: p.TLWELv1.ALPHA,
+
+		TRLWEN:     uint32(p.TRLWELv1.N),
+		TRLWEAlpha: p.TRLWELv1.ALPHA,
+
+		TRGSWN:         uint32(p.TRGSWLv1.N),
+		TRGSWNBit:      uint32(p.TRGSWLv1.NBIT),
+		TRGSWBGBit:     p.TRGSWLv1.BGBIT,
+		TRGSWBG:        p.TRGSWLv1.BG,
+		TRGSWL:         uint32(p.TRGSWLv1.L),
+		TRGSWBaseBit:   uint32(p.TRGSWLv1.BASEBIT),
+		TRGSWIKST:      uint32(p.TRGSWLv1.IKS_T),
+		TRGSWAlpha:     p.TRGSWLv1.ALPHA,
+		TRGSWBlockSize: uint32(p.TRGSWLv1.BlockSize),
+		TRGSWLUTSize:   uint32(p.TRGSWLv1.LookUpTableSize),
+	}
+	if err := binary.Write(w, binary.LittleEndian, &wire); err != nil {
+		return 0, err
+	}
+	return int64(HeaderSize), nil
+}
+
+// ReadFrom reads and decodes a header from r. The recorded parameter set
+// must be valid and equal to the predefined or registered set of its level,
+// so that a forged header cannot make readers allocate arbitrary sizes.
+// Register custom parameter sets before loading objects that use them.
+func (h *Header) ReadFrom(r io.Reader) (int64, error) {
+	var wire headerWire
+	if err := binary.Read(r, binary.LittleEndian, &wire); err != nil {
+		if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+			return 0, fmt.Errorf("%w: %v", ErrInvalidHeader, err)
+		}
+		return 0, err
+	}
+	if wire.Magic != binaryMagic {
+		return int64(HeaderSize), fmt.Errorf("%w: bad magic %q", ErrInvalidHeader, wire.Magic[:])
+	}
+	if wire.Version != BinaryFormatVersion {
+		return int64(HeaderSize), fmt.Errorf("%w: unsupported version %d", ErrInvalidHeader, wire.Version)
+	}
+
+	*h = Header{
+		Version: wire.Version,
+		Object:  ObjectType(wire.Object),
+		Params: Parameters{
+			Level:    SecurityLevel(wire.Level),
+			TLWELv0:  TLWELv0Params{N: int(wire.Lv0N), ALPHA: wire.Lv0Alpha},
+			TLWELv1:  TLWELv1Params{N: int(wire.Lv1N), ALPHA: wire.Lv1Alpha},
+			TRLWELv1: TRLWELv1Params{N: int(wire.TRLWEN), ALPHA: wire.TRLWEAlpha},
+			TRGSWLv1: TRGSWLv1Params{
+				N:               int(wire.TRGSWN),
+				NBIT:            int(wire.TRGSWNBit),
+				BGBIT:           wire.TRGSWBGBit,
+				BG:              wire.TRGSWBG,
+				L:               int(wire.TRGSWL),
+				BASEBIT:         int(wire.TRGSWBaseBit),
+				IKS_T:           int(wire.TRGSWIKST),
+				ALPHA:           wire.TRGSWAlpha,
+				BlockSize:       int(wire.TRGSWBlockSize),
+				LookUpTableSize: int(wire.TRGSWLUTSize),
+			},
+		},
+	}
+	if err := h.Params.Validate(); err != nil {
+		return int64(HeaderSize), fmt.Errorf("%w: %v", ErrInvalidHeader, err)
+	}
+	known, ok := knownParameters(h.Params.Level)
+	if !ok {
+		return int64(HeaderSize), fmt.Errorf("%w: unknown security level %d", ErrInvalidHeader, h.Params.Level)
+	}
+	if h.Params != known {
+		return int64(HeaderSize), fmt.Errorf("%w: blob parameters differ from those of security level %d", ErrParamsMismatch, h.Params.Level)
+	}
+	return int64(HeaderSize), nil
+}
+
+// Check verifies that the header describes the expected object type
+func (h Header) Check(object ObjectType) error {
+	if h.Object != object {
+		return fmt.Errorf("%w: expected %s, found %s", ErrInvalidHeader, object, h.Object)
+	}
+	return nil
+}
+
+// CheckParams verifies that the header was produced with parameter set p
+func (h Header) CheckParams(p Parameters) error {
+	if h.Params != p {
+		return fmt.Errorf("%w: blob uses security level %d, expected level %d", ErrParamsMismatch, h.Params.Level, p.Level)
+	}
+	return nil
+}
@@ -0,0 +1,80 @@
+package params
+
+import "math"
+
+// Noise variances of the parameter set, as fractions of the torus.
+//
+// These are the usual analytic estimates for binary keys and Gaussian
+// noise: every rounding error is taken to be uniform and every key bit to be
+// 1 with probability 1/2 (BlockSize/(BlockSize+1) per block for the level 0
+// keys of block blind rotation). The tlwe and evaluator packages use them to
+// track the noise of each ciphertext (see tlwe.TLWELv0.Variance), and the
+// noise package checks them against measurements.
+
+// FreshVariance returns the noise variance of a level 0 ciphertext encrypted
+// with the secret key
+func (p Parameters) FreshVariance() float64 {
+	return p.TLWELv0.ALPHA * p.TLWELv0.ALPHA
+}
+
+// BlindRotateVariance estimates the noise variance of the level 1 sample
+// extracted after a blind rotation. Every level 0 coefficient adds the noise
+// of its bootstrapping key times the decomposed digits (uniform in
+// [-Bg/2, Bg/2)), and every non-zero key coefficient adds the rounding of
+// the accumulator to L*BGBIT bits. Block blind rotation multiplies both by
+// X^a - 1, which doubles them.
+func (p Parameters) BlindRotateVariance() float64 {
+	n := float64(p.TLWELv0.N)
+	bigN := float64(p.TRGSWLv1.N)
+	l := float64(p.TRGSWLv1.L)
+	bg := float64(p.TRGSWLv1.BG)
+	alpha := p.BSKAlpha()
+
+	digits := 2 * l * bigN * (bg*bg + 2) / 12 * alpha * alpha
+	precision := math.Ldexp(1, -int(p.TRGSWLv1.BGBIT)*p.TRGSWLv1.L)
+	rounding := (1 + bigN/2) * precision * precision / 12
+
+	variance := n*digits + p.lv0KeyWeight()*rounding
+	if p.UseBlockBlindRotation() {
+		variance *= 2
+	}
+	return variance
+}
+
+// KeySwitchVariance estimates the noise variance added by the identity key
+// switch from level 1 to level 0: N*t key switching key samples plus the
+// rounding of each level 1 coefficient to BASEBIT*t bits
+func (p Parameters) KeySwitchVariance() float64 {
+	n := float64(p.TRGSWLv1.N)
+	t := float64(p.TRGSWLv1.IKS_T)
+	alpha := p.KSKAlpha()
+	precision := math.Ldexp(1, -p.TRGSWLv1.BASEBIT*p.TRGSWLv1.IKS_T)
+	return n*t*alpha*alpha + n/2*precision*precision/12
+}
+
+// BootstrapVariance estimates the noise variance of a bootstrapped level 0
+// ciphertext: a blind rotation followed by a key switch. It does not depend
+// on the noise of the input.
+func (p Parameters) BootstrapVariance() float64 {
+	return p.BlindRotateVariance() + p.KeySwitchVariance()
+}
+
+// ModSwitchVariance estimates the rounding noise of switching a level 0
+// ciphertext to the lookup table modulus at the start of a bootstrap. It
+// adds to the noise of the input when deciding whether the bootstrap
+// decrypts correctly.
+func (p Parameters) ModSwitchVariance() float64 {
+	step := 1 / float64(2*p.LookUpTableSize())
+	return (p.lv0KeyWeight() + 1) * step * step / 12
+}
+
+// lv0KeyWeight returns the expected number of non-zero level 0 key
+// coefficients: half of them for a binary key, and BlockSize/(BlockSize+1)
+// per block for the block binary keys of block blind rotation
+func (p Parameters) lv0KeyWeight() float64 {
+	n := float64(p.TLWELv0.N)
+	if p.UseBlockBlindRotation() {
+		return n / float64(p.TRGSWLv1.BlockSize+1)
+	}
+	return n / 2
+}
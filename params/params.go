@@ -23,9 +23,15 @@
 // - `BGBIT`: Decomposition base bits (smaller = more levels, more secure, slower)
 package params
 
+import "fmt"
+
 // Torus represents a 32-bit torus element
 type Torus uint32
 
+// Torus64 represents a 64-bit torus element, used by the torus64 package for
+// noise levels below the resolution of the 32-bit torus
+type Torus64 uint64
+
 // SecurityLevel represents the selected security level
 type SecurityLevel int
 
@@ -66,26 +72,48 @@ type TRLWELv1Params struct {
 
 // TRGSW Level 1 Parameters
 type TRGSWLv1Params struct {
-	N         int
-	NBIT      int
-	BGBIT     uint32
-	BG        uint32
-	L         int
-	BASEBIT   int
-	IKS_T     int
-	ALPHA     float64
-	BlockSize int // Block size for block blind rotation (1=original, >1=block algorithm)
+	N       int
+	NBIT    int
+	BGBIT   uint32
+	BG      uint32
+	L       int
+	BASEBIT int
+	IKS_T   int
+	ALPHA   float64
+
+	// BlockSize is the block size of block blind rotation (1 for one CMux
+	// per coefficient). Block blind rotation needs a block binary level 0
+	// key, with at most one non-zero coefficient per block, which has less
+	// entropy than a uniform binary key of the same dimension (see
+	// EstimateSecurity). The predefined parameter sets use 1; opt in with
+	// Builder.BlockSize.
+	BlockSize int
+
+	// LookUpTableSize is the size of programmable bootstrapping lookup tables.
+	// 0 means N. Larger sizes must be a multiple of N (polyExtendFactor = LookUpTableSize / N)
+	// and give each message a wider slice of the table, which tolerates more noise.
+	LookUpTableSize int
 }
 
-// ============================================================================
-// 80-BIT SECURITY PARAMETERS (Performance-Optimized)
-// ============================================================================
-var params80Bit = struct {
+// Parameters is a complete TFHE parameter set.
+//
+// Parameters is a plain value: every copy is independent, so a key, evaluator
+// or ciphertext holding one is never affected by later changes to
+// CurrentSecurityLevel. This allows one process to hold, for example, a Uint5
+// key set and a 128-bit boolean key set at the same time.
+type Parameters struct {
+	Level    SecurityLevel
 	TLWELv0  TLWELv0Params
 	TLWELv1  TLWELv1Params
 	TRLWELv1 TRLWELv1Params
 	TRGSWLv1 TRGSWLv1Params
-}{
+}
+
+// ============================================================================
+// 80-BIT SECURITY PARAMETERS (Performance-Optimized)
+// ============================================================================
+var params80Bit = Parameters{
+	Level: Security80Bit,
 	TLWELv0: TLWELv0Params{
 		N:     550,
 		ALPHA: 5.0e-5, // 2^-14.3 approximately
@@ -107,19 +135,15 @@ var params80Bit = struct {
 		BASEBIT:   2,
 		IKS_T:     7,
 		ALPHA:     3.73e-8,
-		BlockSize: 3, // Use block blind rotation (3-4x faster)
+		BlockSize: 1, // One CMux per coefficient (see TRGSWLv1Params.BlockSize)
 	},
 }
 
 // ============================================================================
 // 110-BIT SECURITY PARAMETERS (Original TFHE, Balanced)
 // ============================================================================
-var params110Bit = struct {
-	TLWELv0  TLWELv0Params
-	TLWELv1  TLWELv1Params
-	TRLWELv1 TRLWELv1Params
-	TRGSWLv1 TRGSWLv1Params
-}{
+var params110Bit = Parameters{
+	Level: Security110Bit,
 	TLWELv0: TLWELv0Params{
 		N:     630,
 		ALPHA: 3.0517578125e-05, // 2^-15 approximately
@@ -141,19 +165,15 @@ var params110Bit = struct {
 		BASEBIT:   2,
 		IKS_T:     8,
 		ALPHA:     2.980232238769531e-8,
-		BlockSize: 3, // Use block blind rotation (3-4x faster)
+		BlockSize: 1, // One CMux per coefficient (see TRGSWLv1Params.BlockSize)
 	},
 }
 
 // ============================================================================
 // 128-BIT SECURITY PARAMETERS (DEFAULT - High Security, Quantum-Resistant)
 // ============================================================================
-var params128Bit = struct {
-	TLWELv0  TLWELv0Params
-	TLWELv1  TLWELv1Params
-	TRLWELv1 TRLWELv1Params
-	TRGSWLv1 TRGSWLv1Params
-}{
+var params128Bit = Parameters{
+	Level: Security128Bit,
 	TLWELv0: TLWELv0Params{
 		N:     700,
 		ALPHA: 2.0e-5, // 2^-15.6 approximately
@@ -175,7 +195,7 @@ var params128Bit = struct {
 		BASEBIT:   2,
 		IKS_T:     9,
 		ALPHA:     2.0e-8,
-		BlockSize: 3, // Use block blind rotation (3-4x faster)
+		BlockSize: 1, // One CMux per coefficient (see TRGSWLv1Params.BlockSize)
 	},
 }
 
@@ -191,12 +211,8 @@ var params128Bit = struct {
 //
 // Note: This is essentially an alias for production binary operations.
 // Use this when you want consistent Uint naming, or use Security128Bit directly.
-var paramsUint1 = struct {
-	TLWELv0  TLWELv0Params
-	TLWELv1  TLWELv1Params
-	TRLWELv1 TRLWELv1Params
-	TRGSWLv1 TRGSWLv1Params
-}{
+var paramsUint1 = Parameters{
+	Level: SecurityUint1,
 	TLWELv0: TLWELv0Params{
 		N:     700,
 		ALPHA: 2.0e-05,
@@ -218,7 +234,7 @@ var paramsUint1 = struct {
 		BASEBIT:   2,
 		IKS_T:     8,
 		ALPHA:     2.0e-08,
-		BlockSize: 3,
+		BlockSize: 1,
 	},
 }
 
@@ -233,12 +249,8 @@ var paramsUint1 = struct {
 // - Lower noise for 2-bit precision
 //
 // Security: Comparable to standard parameters, optimized for 2-bit arithmetic.
-var paramsUint2 = struct {
-	TLWELv0  TLWELv0Params
-	TLWELv1  TLWELv1Params
-	TRLWELv1 TRLWELv1Params
-	TRGSWLv1 TRGSWLv1Params
-}{
+var paramsUint2 = Parameters{
+	Level: SecurityUint2,
 	TLWELv0: TLWELv0Params{
 		N:     687,
 		ALPHA: 0.00002120846893069971872305794214,
@@ -260,7 +272,7 @@ var paramsUint2 = struct {
 		BASEBIT:   4, // KeySwitch base bits
 		IKS_T:     3, // KeySwitch level
 		ALPHA:     0.00000000000231841227527049948463,
-		BlockSize: 3,
+		BlockSize: 1,
 	},
 }
 
@@ -274,12 +286,8 @@ var paramsUint2 = struct {
 // - Very low noise for 3-bit precision
 //
 // Security: Optimized for 3-bit arithmetic with good noise margin.
-var paramsUint3 = struct {
-	TLWELv0  TLWELv0Params
-	TLWELv1  TLWELv1Params
-	TRLWELv1 TRLWELv1Params
-	TRGSWLv1 TRGSWLv1Params
-}{
+var paramsUint3 = Parameters{
+	Level: SecurityUint3,
 	TLWELv0: TLWELv0Params{
 		N:     820,
 		ALPHA: 0.00000251676160959795544987084234,
@@ -301,7 +309,7 @@ var paramsUint3 = struct {
 		BASEBIT:   6, // KeySwitch base bits
 		IKS_T:     2, // KeySwitch level
 		ALPHA:     0.00000000000000022204460492503131,
-		BlockSize: 4,
+		BlockSize: 1,
 	},
 }
 
@@ -315,12 +323,8 @@ var paramsUint3 = struct {
 // - Very low noise for 4-bit precision
 //
 // Security: Optimized for 4-bit arithmetic, same noise as Uint3.
-var paramsUint4 = struct {
-	TLWELv0  TLWELv0Params
-	TLWELv1  TLWELv1Params
-	TRLWELv1 TRLWELv1Params
-	TRGSWLv1 TRGSWLv1Params
-}{
+var paramsUint4 = Parameters{
+	Level: SecurityUint4,
 	TLWELv0: TLWELv0Params{
 		N:     820,
 		ALPHA: 0.00000251676160959795544987084234,
@@ -342,7 +346,7 @@ var paramsUint4 = struct {
 		BASEBIT:   5, // KeySwitch base bits
 		IKS_T:     3, // KeySwitch level
 		ALPHA:     0.00000000000000022204460492503131,
-		BlockSize: 4,
+		BlockSize: 1,
 	},
 }
 
@@ -355,16 +359,12 @@ var paramsUint4 = struct {
 // - ~700x lower noise than standard 80-bit security
 // - Larger polynomial degree (2048 vs 1024)
 // - Supports messageModulus up to 32 reliably
-// - Enables 4-bootstrap nibble addition
+// - Enables 2-bootstrap nibble addition (examples/add_two_numbers)
 //
 // Security: Provides comparable security to 80-bit level but optimized
 // for precision rather than maximum cryptographic hardness.
-var paramsUint5 = struct {
-	TLWELv0  TLWELv0Params
-	TLWELv1  TLWELv1Params
-	TRLWELv1 TRLWELv1Params
-	TRGSWLv1 TRGSWLv1Params
-}{
+var paramsUint5 = Parameters{
+	Level: SecurityUint5,
 	TLWELv0: TLWELv0Params{
 		N:     1071,
 		ALPHA: 7.088226765410429399593757e-08,
@@ -386,7 +386,7 @@ var paramsUint5 = struct {
 		BASEBIT:   6,
 		IKS_T:     3,
 		ALPHA:     2.2204460492503131e-17,
-		BlockSize: 7,
+		BlockSize: 1,
 	},
 }
 
@@ -398,14 +398,8 @@ var paramsUint5 = struct {
 // - Same noise as Uint5 for reliable 6-bit operations
 // - LookUpTableSize = 4096 (polyExtendFactor = 2)
 // - Supports messageModulus=64
-//
-// Note: LookUpTableSize > PolyDegree requires extended LUT generation
-var paramsUint6 = struct {
-	TLWELv0  TLWELv0Params
-	TLWELv1  TLWELv1Params
-	TRLWELv1 TRLWELv1Params
-	TRGSWLv1 TRGSWLv1Params
-}{
+var paramsUint6 = Parameters{
+	Level: SecurityUint6,
 	TLWELv0: TLWELv0Params{
 		N:     1071,
 		ALPHA: 7.088226765410429399593757e-08,
@@ -419,15 +413,16 @@ var paramsUint6 = struct {
 		ALPHA: 2.2204460492503131e-17,
 	},
 	TRGSWLv1: TRGSWLv1Params{
-		N:         2048,
-		NBIT:      11,
-		BGBIT:     22,
-		BG:        1 << 22,
-		L:         1,
-		BASEBIT:   6,
-		IKS_T:     3,
-		ALPHA:     2.2204460492503131e-17,
-		BlockSize: 7,
+		N:               2048,
+		NBIT:            11,
+		BGBIT:           22,
+		BG:              1 << 22,
+		L:               1,
+		BASEBIT:         6,
+		IKS_T:           3,
+		ALPHA:           2.2204460492503131e-17,
+		BlockSize:       1,
+		LookUpTableSize: 4096,
 	},
 }
 
@@ -439,14 +434,8 @@ var paramsUint6 = struct {
 // - Larger LWE dimension (1160) for added security
 // - LookUpTableSize = 8192 (polyExtendFactor = 4)
 // - Supports messageModulus=128
-//
-// Note: Requires extended LUT generation with polyExtendFactor=4
-var paramsUint7 = struct {
-	TLWELv0  TLWELv0Params
-	TLWELv1  TLWELv1Params
-	TRLWELv1 TRLWELv1Params
-	TRGSWLv1 TRGSWLv1Params
-}{
+var paramsUint7 = Parameters{
+	Level: SecurityUint7,
 	TLWELv0: TLWELv0Params{
 		N:     1160,
 		ALPHA: 1.966220007498402695211596e-08,
@@ -460,15 +449,16 @@ var paramsUint7 = struct {
 		ALPHA: 2.2204460492503131e-17,
 	},
 	TRGSWLv1: TRGSWLv1Params{
-		N:         2048,
-		NBIT:      11,
-		BGBIT:     22,
-		BG:        1 << 22,
-		L:         1,
-		BASEBIT:   7,
-		IKS_T:     3,
-		ALPHA:     2.2204460492503131e-17,
-		BlockSize: 8,
+		N:               2048,
+		NBIT:            11,
+		BGBIT:           22,
+		BG:              1 << 22,
+		L:               1,
+		BASEBIT:         7,
+		IKS_T:           3,
+		ALPHA:           2.2204460492503131e-17,
+		BlockSize:       1,
+		LookUpTableSize: 8192,
 	},
 }
 
@@ -480,14 +470,8 @@ var paramsUint7 = struct {
 // - Same dimensions as Uint7
 // - LookUpTableSize = 18432 (polyExtendFactor = 9)
 // - Supports full 8-bit values (0-255)
-//
-// Note: Requires extended LUT generation with polyExtendFactor=9
-var paramsUint8 = struct {
-	TLWELv0  TLWELv0Params
-	TLWELv1  TLWELv1Params
-	TRLWELv1 TRLWELv1Params
-	TRGSWLv1 TRGSWLv1Params
-}{
+var paramsUint8 = Parameters{
+	Level: SecurityUint8,
 	TLWELv0: TLWELv0Params{
 		N:     1160,
 		ALPHA: 1.966220007498402695211596e-08,
@@ -501,144 +485,71 @@ var paramsUint8 = struct {
 		ALPHA: 2.2204460492503131e-17,
 	},
 	TRGSWLv1: TRGSWLv1Params{
-		N:         2048,
-		NBIT:      11,
-		BGBIT:     22,
-		BG:        1 << 22,
-		L:         1,
-		BASEBIT:   7,
-		IKS_T:     3,
-		ALPHA:     2.2204460492503131e-17,
-		BlockSize: 8,
+		N:               2048,
+		NBIT:            11,
+		BGBIT:           22,
+		BG:              1 << 22,
+		L:               1,
+		BASEBIT:         7,
+		IKS_T:           3,
+		ALPHA:           2.2204460492503131e-17,
+		BlockSize:       1,
+		LookUpTableSize: 18432,
 	},
 }
 
-// GetTLWELv0 returns the TLWE Level 0 parameters for the current security level
-func GetTLWELv0() TLWELv0Params {
-	switch CurrentSecurityLevel {
-	case Security80Bit:
-		return params80Bit.TLWELv0
-	case Security110Bit:
-		return params110Bit.TLWELv0
-	case SecurityUint1:
-		return paramsUint1.TLWELv0
-	case SecurityUint2:
-		return paramsUint2.TLWELv0
-	case SecurityUint3:
-		return paramsUint3.TLWELv0
-	case SecurityUint4:
-		return paramsUint4.TLWELv0
-	case SecurityUint5:
-		return paramsUint5.TLWELv0
-	case SecurityUint6:
-		return paramsUint6.TLWELv0
-	case SecurityUint7:
-		return paramsUint7.TLWELv0
-	case SecurityUint8:
-		return paramsUint8.TLWELv0
-	default:
-		return params128Bit.TLWELv0
-	}
-}
-
-// GetTLWELv1 returns the TLWE Level 1 parameters for the current security level
-func GetTLWELv1() TLWELv1Params {
-	switch CurrentSecurityLevel {
+// GetParameters returns the parameter set for the given security level,
+// including the levels of sets added with Register.
+// Unknown levels fall back to the 128-bit parameters.
+func GetParameters(level SecurityLevel) Parameters {
+	switch level {
 	case Security80Bit:
-		return params80Bit.TLWELv1
+		return params80Bit
 	case Security110Bit:
-		return params110Bit.TLWELv1
+		return params110Bit
 	case SecurityUint1:
-		return paramsUint1.TLWELv1
+		return paramsUint1
 	case SecurityUint2:
-		return paramsUint2.TLWELv1
+		return paramsUint2
 	case SecurityUint3:
-		return paramsUint3.TLWELv1
+		return paramsUint3
 	case SecurityUint4:
-		return paramsUint4.TLWELv1
+		return paramsUint4
 	case SecurityUint5:
-		return paramsUint5.TLWELv1
+		return paramsUint5
 	case SecurityUint6:
-		return paramsUint6.TLWELv1
+		return paramsUint6
 	case SecurityUint7:
-		return paramsUint7.TLWELv1
+		return paramsUint7
 	case SecurityUint8:
-		return paramsUint8.TLWELv1
-	default:
-		return params128Bit.TLWELv1
+		return paramsUint8
 	}
-}
-
-// GetTRLWELv1 returns the TRLWE Level 1 parameters for the current security level
-func GetTRLWELv1() TRLWELv1Params {
-	switch CurrentSecurityLevel {
-	case Security80Bit:
-		return params80Bit.TRLWELv1
-	case Security110Bit:
-		return params110Bit.TRLWELv1
-	case SecurityUint1:
-		return paramsUint1.TRLWELv1
-	case SecurityUint2:
-		return paramsUint2.TRLWELv1
-	case SecurityUint3:
-		return paramsUint3.TRLWELv1
-	case SecurityUint4:
-		return paramsUint4.TRLWELv1
-	case SecurityUint5:
-		return paramsUint5.TRLWELv1
-	case SecurityUint6:
-		return paramsUint6.TRLWELv1
-	case SecurityUint7:
-		return paramsUint7.TRLWELv1
-	case SecurityUint8:
-		return paramsUint8.TRLWELv1
-	default:
-		return params128Bit.TRLWELv1
+	if _, p, ok := registered(level); ok {
+		return p
 	}
+	return params128Bit
 }
 
-// GetTRGSWLv1 returns the TRGSW Level 1 parameters for the current security level
-func GetTRGSWLv1() TRGSWLv1Params {
-	switch CurrentSecurityLevel {
-	case Security80Bit:
-		return params80Bit.TRGSWLv1
-	case Security110Bit:
-		return params110Bit.TRGSWLv1
-	case SecurityUint1:
-		return paramsUint1.TRGSWLv1
-	case SecurityUint2:
-		return paramsUint2.TRGSWLv1
-	case SecurityUint3:
-		return paramsUint3.TRGSWLv1
-	case SecurityUint4:
-		return paramsUint4.TRGSWLv1
-	case SecurityUint5:
-		return paramsUint5.TRGSWLv1
-	case SecurityUint6:
-		return paramsUint6.TRGSWLv1
-	case SecurityUint7:
-		return paramsUint7.TRGSWLv1
-	case SecurityUint8:
-		return paramsUint8.TRGSWLv1
-	default:
-		return params128Bit.TRGSWLv1
-	}
+// Current returns the parameter set selected by CurrentSecurityLevel
+func Current() Parameters {
+	return GetParameters(CurrentSecurityLevel)
 }
 
-// KSKAlpha returns the key switching key alpha for the current security level
-func KSKAlpha() float64 {
-	return GetTLWELv0().ALPHA
+// KSKAlpha returns the key switching key alpha
+func (p Parameters) KSKAlpha() float64 {
+	return p.TLWELv0.ALPHA
 }
 
-// BSKAlpha returns the bootstrapping key alpha for the current security level
-func BSKAlpha() float64 {
-	return GetTLWELv1().ALPHA
+// BSKAlpha returns the bootstrapping key alpha
+func (p Parameters) BSKAlpha() float64 {
+	return p.TLWELv1.ALPHA
 }
 
-// SecurityInfo returns a description of the current security level
-func SecurityInfo() string {
+// SecurityInfo returns a description of the parameter set with its
+// estimated security (see EstimateSecurity)
+func (p Parameters) SecurityInfo() string {
 	var desc string
-	switch CurrentSecurityLevel {
+	switch p.Level {
 	case Security80Bit:
 		desc = "80-bit security (performance-optimized)"
 	case Security110Bit:
@@ -659,23 +570,87 @@ func SecurityInfo() string {
 		desc = "Uint7 parameters (7-bit messages, messageModulus=128, N=2048)"
 	case SecurityUint8:
 		desc = "Uint8 parameters (8-bit messages, messageModulus=256, N=2048)"
-	default:
+	case Security128Bit:
 		desc = "128-bit security (high security, quantum-resistant)"
+	default:
+		desc = fmt.Sprintf("custom parameters (n=%d, N=%d)", p.TLWELv0.N, p.TRGSWLv1.N)
+		if name, _, ok := registered(p.Level); ok {
+			desc = fmt.Sprintf("custom parameters %q (n=%d, N=%d)", name, p.TLWELv0.N, p.TRGSWLv1.N)
+		}
 	}
-	return desc
+	return desc + "; " + p.EstimateSecurity().String()
 }
 
-// GetBlockCount returns the number of blocks for block blind rotation
-func GetBlockCount() int {
-	lweDim := GetTLWELv0().N
-	blockSize := GetTRGSWLv1().BlockSize
+// BlockCount returns the number of blocks for block blind rotation
+func (p Parameters) BlockCount() int {
+	lweDim := p.TLWELv0.N
+	blockSize := p.TRGSWLv1.BlockSize
 	if blockSize <= 1 {
 		return lweDim // Original algorithm (no blocks)
 	}
 	return (lweDim + blockSize - 1) / blockSize // Ceiling division
 }
 
+// UseBlockBlindRotation returns true if block blind rotation should be used
+func (p Parameters) UseBlockBlindRotation() bool {
+	return p.TRGSWLv1.BlockSize > 1
+}
+
+// LookUpTableSize returns the size of programmable bootstrapping lookup tables
+func (p Parameters) LookUpTableSize() int {
+	if p.TRGSWLv1.LookUpTableSize == 0 {
+		return p.TRGSWLv1.N
+	}
+	return p.TRGSWLv1.LookUpTableSize
+}
+
+// PolyExtendFactor returns the number of degree-N polynomials making up a lookup table.
+// It is 1 unless the parameter set uses extended lookup tables.
+func (p Parameters) PolyExtendFactor() int {
+	return p.LookUpTableSize() / p.TRGSWLv1.N
+}
+
+// GetTLWELv0 returns the TLWE Level 0 parameters for the current security level
+func GetTLWELv0() TLWELv0Params {
+	return Current().TLWELv0
+}
+
+// GetTLWELv1 returns the TLWE Level 1 parameters for the current security level
+func GetTLWELv1() TLWELv1Params {
+	return Current().TLWELv1
+}
+
+// GetTRLWELv1 returns the TRLWE Level 1 parameters for the current security level
+func GetTRLWELv1() TRLWELv1Params {
+	return Current().TRLWELv1
+}
+
+// GetTRGSWLv1 returns the TRGSW Level 1 parameters for the current security level
+func GetTRGSWLv1() TRGSWLv1Params {
+	return Current().TRGSWLv1
+}
+
+// KSKAlpha returns the key switching key alpha for the current security level
+func KSKAlpha() float64 {
+	return Current().KSKAlpha()
+}
+
+// BSKAlpha returns the bootstrapping key alpha for the current security level
+func BSKAlpha() float64 {
+	return Current().BSKAlpha()
+}
+
+// SecurityInfo returns a description of the current security level
+func SecurityInfo() string {
+	return Current().SecurityInfo()
+}
+
+// GetBlockCount returns the number of blocks for block blind rotation
+func GetBlockCount() int {
+	return Current().BlockCount()
+}
+
 // UseBlockBlindRotation returns true if block blind rotation should be used
 func UseBlockBlindRotation() bool {
-	return GetTRGSWLv1().BlockSize > 1
+	return Current().UseBlockBlindRotation()
 }
@@ -0,0 +1,117 @@
+package params
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+)
+
+var (
+	// ErrUnknownParams is returned by Lookup for a name that is not registered
+	ErrUnknownParams = errors.New("params: unknown parameter set")
+	// ErrNameTaken is returned by Register for a name that is already registered
+	ErrNameTaken = errors.New("params: parameter set name already registered")
+)
+
+// builtinNames are the registry names of the predefined parameter sets
+var builtinNames = map[string]SecurityLevel{
+	"80bit":  Security80Bit,
+	"110bit": Security110Bit,
+	"128bit": Security128Bit,
+	"uint1":  SecurityUint1,
+	"uint2":  SecurityUint2,
+	"uint3":  SecurityUint3,
+	"uint4":  SecurityUint4,
+	"uint5":  SecurityUint5,
+	"uint6":  SecurityUint6,
+	"uint7":  SecurityUint7,
+	"uint8":  SecurityUint8,
+}
+
+// registry holds the custom parameter sets added with Register
+var registry = struct {
+	sync.RWMutex
+	byName  map[string]Parameters
+	byLevel map[SecurityLevel]string
+}{
+	byName:  make(map[string]Parameters),
+	byLevel: make(map[SecurityLevel]string),
+}
+
+// Register validates a custom parameter set and registers it under name,
+// for Lookup and GetParameters. The set is given a level of its own above
+// SecurityCustom, which is returned with it; serialized keys and
+// ciphertexts record that level.
+//
+// Levels are assigned in registration order, so programs that exchange
+// serialized objects must register their custom sets in the same order.
+func Register(name string, p Parameters) (Parameters, error) {
+	if name == "" {
+		return Parameters{}, fmt.Errorf("%w: empty name", ErrInvalidParams)
+	}
+	if err := p.Validate(); err != nil {
+		return Parameters{}, err
+	}
+
+	registry.Lock()
+	defer registry.Unlock()
+	if _, ok := builtinNames[name]; ok {
+		return Parameters{}, fmt.Errorf("%w: %q", ErrNameTaken, name)
+	}
+	if _, ok := registry.byName[name]; ok {
+		return Parameters{}, fmt.Errorf("%w: %q", ErrNameTaken, name)
+	}
+	p.Level = SecurityCustom + SecurityLevel(len(registry.byName)+1)
+	registry.byName[name] = p
+	registry.byLevel[p.Level] = name
+	return p, nil
+}
+
+// Lookup returns the parameter set registered under name: a custom set or
+// one of the predefined "80bit", "110bit", "128bit" and "uint1" to "uint8"
+func Lookup(name string) (Parameters, error) {
+	if level, ok := builtinNames[name]; ok {
+		return GetParameters(level), nil
+	}
+	registry.RLock()
+	defer registry.RUnlock()
+	if p, ok := registry.byName[name]; ok {
+		return p, nil
+	}
+	return Parameters{}, fmt.Errorf("%w: %q", ErrUnknownParams, name)
+}
+
+// Names returns the names of the predefined and registered parameter sets, sorted
+func Names() []string {
+	registry.RLock()
+	defer registry.RUnlock()
+	names := make([]string, 0, len(builtinNames)+len(registry.byName))
+	for name := range builtinNames {
+		names = append(names, name)
+	}
+	for name := range registry.byName {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// registered returns the name and parameter set registered with level
+func registered(level SecurityLevel) (string, Parameters, bool) {
+	registry.RLock()
+	defer registry.RUnlock()
+	name, ok := registry.byLevel[level]
+	return name, registry.byName[name], ok
+}
+
+// knownParameters returns the parameter set of a predefined or registered level
+func knownParameters(level SecurityLevel) (Parameters, bool) {
+	for _, builtin := range builtinNames {
+		if builtin == level {
+			return GetParameters(level), true
+		}
+	}
+	_, p, ok := registered(level)
+	return p, ok
+}
@@ -0,0 +1,228 @@
+package params
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sync"
+)
+
+// Security estimates of LWE instances, in bits.
+//
+// This is a small approximation of the lattice estimator: the primal uSVP
+// attack (with the 2016 success condition of Alkim et al.) and the dual
+// distinguishing attack, both priced with the core-SVP model (one BKZ-β SVP
+// call costs 2^(0.292β) classically). The secret is binary, or block binary
+// for the level 0 key of block blind rotation, and the modulus is 2^32, or
+// 2^64 for the torus64 package. Only the variance of the secret enters the
+// estimates: attacks that guess the zero coefficients of sparse secrets are
+// not modelled. Core-SVP ignores the number of BKZ tours and the memory of
+// sieving, so it is conservative: it rates instances lower than estimates
+// of the full attack cost.
+
+// ErrInsecure is reported by CheckSecurity when a parameter set is estimated
+// to be below the requested security level
+var ErrInsecure = errors.New("params: estimated security below the requested level")
+
+const (
+	// logTorusModulus is log2 of the modulus of the 32-bit torus
+	logTorusModulus = 32
+	// logTorus64Modulus is log2 of the modulus of the 64-bit torus
+	logTorus64Modulus = 64
+)
+
+// minBlockSize is the smallest BKZ block size the estimates consider
+const minBlockSize = 40
+
+// LWEEstimate is the estimated security of one LWE instance, as log2 of the
+// core-SVP cost of each attack
+type LWEEstimate struct {
+	N          int
+	Alpha      float64
+	LogModulus int     // 32 or 64
+	SecretStd  float64 // Standard deviation of the secret coefficients
+	Primal     float64 // Primal uSVP attack
+	Dual       float64 // Dual distinguishing attack
+}
+
+// Bits returns the security of the instance: the cost of the cheaper attack
+func (e LWEEstimate) Bits() float64 {
+	return math.Min(e.Primal, e.Dual)
+}
+
+// Noiseless reports whether the noise is below one unit of the torus and
+// rounds away, which leaves the instance without security
+func (e LWEEstimate) Noiseless() bool {
+	return math.Ldexp(e.Alpha, e.LogModulus) < 1
+}
+
+// String formats the cost of both attacks
+func (e LWEEstimate) String() string {
+	if e.Noiseless() {
+		return "noise below the torus resolution"
+	}
+	return fmt.Sprintf("primal %.0f, dual %.0f", e.Primal, e.Dual)
+}
+
+// estimateCache holds the estimates computed so far, keyed by lweInstance
+var estimateCache sync.Map
+
+type lweInstance struct {
+	n          int
+	alpha      float64
+	logModulus int
+	secretStd  float64
+}
+
+// binarySecretStd is the standard deviation of uniform binary coefficients
+const binarySecretStd = 0.5
+
+// EstimateLWE estimates the security of LWE with dimension n, a binary
+// secret and Gaussian noise of standard deviation alpha (a fraction of the
+// torus). Noise below one unit of the 32-bit torus is rounded away by
+// encryption, so the samples can be solved by linear algebra: those
+// instances are estimated at 0 bits.
+func EstimateLWE(n int, alpha float64) LWEEstimate {
+	return estimateLWE(n, alpha, logTorusModulus, binarySecretStd)
+}
+
+// EstimateLWE64 estimates the security of LWE on the 64-bit torus, like
+// EstimateLWE. Only noise below 2^-64 is rounded away.
+func EstimateLWE64(n int, alpha float64) LWEEstimate {
+	return estimateLWE(n, alpha, logTorus64Modulus, binarySecretStd)
+}
+
+func estimateLWE(n int, alpha float64, logModulus int, secretStd float64) LWEEstimate {
+	key := lweInstance{n, alpha, logModulus, secretStd}
+	if e, ok := estimateCache.Load(key); ok {
+		return e.(LWEEstimate)
+	}
+	e := LWEEstimate{N: n, Alpha: alpha, LogModulus: logModulus, SecretStd: secretStd}
+	if !e.Noiseless() {
+		e.Primal = primalCost(n, alpha, float64(logModulus), secretStd)
+		e.Dual = dualCost(n, alpha, float64(logModulus), secretStd)
+	}
+	estimateCache.Store(key, e)
+	return e
+}
+
+// logRootHermite returns log2 of the root Hermite factor of BKZ-β
+func logRootHermite(beta float64) float64 {
+	return math.Log2(math.Pow(math.Pi*beta, 1/beta)*beta/(2*math.Pi*math.E)) / (2 * (beta - 1))
+}
+
+// sampleStep returns the step of the search over the number of samples m,
+// which ranges up to 2n
+func sampleStep(n, points int) int {
+	return max(1, n/points)
+}
+
+// primalCost returns the core-SVP cost of the primal uSVP attack, minimized
+// over the number of samples. The secret is scaled to the size of the noise
+// (binary coefficients have a standard deviation of 1/2), and BKZ-β finds
+// the embedded short vector when sigma*sqrt(β) <= δ^(2β-d) * vol^(1/d).
+func primalCost(n int, alpha, logQ, secretStd float64) float64 {
+	logSigma := math.Log2(alpha) + logQ
+	logScale := logSigma - math.Log2(secretStd)
+	best := math.Inf(1)
+	for m := 1; m <= 2*n; m += sampleStep(n, 128) {
+		d := float64(m + n + 1)
+		logVol := (float64(m)*logQ + float64(n)*logScale) / d
+		found := func(beta float64) bool {
+			return logSigma+math.Log2(beta)/2 <= (2*beta-d)*logRootHermite(beta)+logVol
+		}
+		if !found(d) {
+			continue
+		}
+		// found is monotone in β: binary search for the smallest block size
+		lo, hi := float64(minBlockSize), d
+		if found(lo) {
+			hi = lo
+		}
+		for hi-lo > 1 {
+			mid := math.Floor((lo + hi) / 2)
+			if found(mid) {
+				hi = mid
+			} else {
+				lo = mid
+			}
+		}
+		best = math.Min(best, 0.292*hi)
+	}
+	return best
+}
+
+// dualCost returns the core-SVP cost of the dual attack, minimized over the
+// number of samples and the block size. BKZ-β finds a short vector of length
+// ℓ = δ^d * vol^(1/d) in the scaled dual lattice, which distinguishes the
+// samples with advantage ε = exp(-2π²(ℓα)²). The attack needs 1/ε² such
+// vectors, and one sieving call yields 2^(0.2075β) of them.
+func dualCost(n int, alpha, logQ, secretStd float64) float64 {
+	logScale := -(math.Log2(alpha) + logQ - math.Log2(secretStd))
+	best := math.Inf(1)
+	for m := 1; m <= 2*n; m += sampleStep(n, 64) {
+		d := float64(m + n)
+		logVol := float64(n) * (logQ + logScale) / d
+		for beta := float64(minBlockSize); beta <= d; beta++ {
+			length := math.Exp2(d*logRootHermite(beta)+logVol) * alpha
+			logAdvantage := -2 * math.Pi * math.Pi * length * length / math.Ln2
+			cost := 0.292*beta + math.Max(0, -2*logAdvantage-0.2075*beta)
+			best = math.Min(best, cost)
+		}
+	}
+	return best
+}
+
+// SecurityEstimate is the estimated security of a parameter set
+type SecurityEstimate struct {
+	Lv0 LWEEstimate // Level 0 key: ciphertexts and key switching key
+	Lv1 LWEEstimate // Level 1 key: bootstrapping key
+}
+
+// Bits returns the security of the parameter set: that of its weaker key
+func (s SecurityEstimate) Bits() float64 {
+	return math.Min(s.Lv0.Bits(), s.Lv1.Bits())
+}
+
+// String formats the estimate of both levels
+func (s SecurityEstimate) String() string {
+	return fmt.Sprintf("estimated %.0f bits (core-SVP; level 0: %s; level 1: %s)", s.Bits(), s.Lv0, s.Lv1)
+}
+
+// EstimateSecurity estimates the security of the level 0 key (dimension
+// TLWELv0.N, noise KSKAlpha) and the level 1 key (dimension TLWELv1.N, noise
+// BSKAlpha) of the parameter set. With BlockSize > 1 the level 0 key is
+// block binary, and the estimate uses its lower variance.
+func (p Parameters) EstimateSecurity() SecurityEstimate {
+	return SecurityEstimate{
+		Lv0: estimateLWE(p.TLWELv0.N, p.KSKAlpha(), logTorusModulus, p.lv0SecretStd()),
+		Lv1: EstimateLWE(p.TLWELv1.N, p.BSKAlpha()),
+	}
+}
+
+// EstimateSecurity64 estimates the security of the parameter set on the
+// 64-bit torus of the torus64 package, where the noise of the Uint profiles
+// is not rounded away
+func (p Parameters) EstimateSecurity64() SecurityEstimate {
+	return SecurityEstimate{
+		Lv0: estimateLWE(p.TLWELv0.N, p.KSKAlpha(), logTorus64Modulus, p.lv0SecretStd()),
+		Lv1: EstimateLWE64(p.TLWELv1.N, p.BSKAlpha()),
+	}
+}
+
+// lv0SecretStd returns the standard deviation of the level 0 key
+// coefficients, which are 1 with probability lv0KeyWeight/n
+func (p Parameters) lv0SecretStd() float64 {
+	w := p.lv0KeyWeight() / float64(p.TLWELv0.N)
+	return math.Sqrt(w * (1 - w))
+}
+
+// CheckSecurity returns an error wrapping ErrInsecure if the parameter set
+// is estimated below the given number of bits. Use it to reject custom
+// parameter sets before generating keys.
+func (p Parameters) CheckSecurity(bits float64) error {
+	if s := p.EstimateSecurity(); s.Bits() < bits {
+		return fmt.Errorf("%w: %s, want %.0f", ErrInsecure, s, bits)
+	}
+	return nil
+}
@@ -0,0 +1,96 @@
+package params_test
+
+import (
+	"errors"
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/thedonutfactory/go-tfhe/params"
+)
+
+func TestEstimateLWE(t *testing.T) {
+	base := params.EstimateLWE(630, 3.0517578125e-05)
+	if base.Primal < 80 || base.Primal > 130 || base.Dual < 80 || base.Dual > 130 {
+		t.Fatalf("n=630, alpha=2^-15: %s, want 80 to 130 bits", base)
+	}
+
+	testCases := []struct {
+		name  string
+		n     int
+		alpha float64
+	}{
+		{"larger dimension", 800, 3.0517578125e-05},
+		{"more noise", 630, 1.0e-4},
+	}
+	for _, tc := range testCases {
+		if e := params.EstimateLWE(tc.n, tc.alpha); e.Bits() <= base.Bits() {
+			t.Errorf("%s: %.1f bits, want more than %.1f", tc.name, e.Bits(), base.Bits())
+		}
+	}
+
+	if e := params.EstimateLWE(2048, 2.2204460492503131e-17); !e.Noiseless() || e.Bits() != 0 {
+		t.Errorf("noise below the torus resolution: %.1f bits, want 0", e.Bits())
+	}
+}
+
+func TestEstimateLWE64(t *testing.T) {
+	// The level 1 noise of Uint5 is below the 32-bit torus resolution only
+	uint5 := params.GetParameters(params.SecurityUint5)
+	if s := uint5.EstimateSecurity64(); s.Lv1.Noiseless() || s.Bits() < 80 {
+		t.Errorf("Uint5 on the 64-bit torus: %s, want at least 80 bits", s)
+	}
+
+	// Noise well above both resolutions gives close estimates on both tori
+	e32 := params.EstimateLWE(630, 3.0517578125e-05)
+	e64 := params.EstimateLWE64(630, 3.0517578125e-05)
+	if math.Abs(e32.Bits()-e64.Bits()) > 5 {
+		t.Errorf("n=630, alpha=2^-15: %.1f bits on the 32-bit torus, %.1f on the 64-bit torus", e32.Bits(), e64.Bits())
+	}
+}
+
+func TestEstimateSecurityBlockKeys(t *testing.T) {
+	uniform := params.GetParameters(params.Security128Bit)
+	block := uniform
+	block.TRGSWLv1.BlockSize = 3
+
+	u, b := uniform.EstimateSecurity(), block.EstimateSecurity()
+	t.Logf("uniform: %s; block: %s", u, b)
+	// One non-zero coefficient in 4 on average: variance 1/4 * 3/4
+	if want := math.Sqrt(3.0 / 16); math.Abs(b.Lv0.SecretStd-want) > 1e-12 {
+		t.Errorf("block key SecretStd = %v, want %v", b.Lv0.SecretStd, want)
+	}
+	if b.Lv0.Bits() >= u.Lv0.Bits() {
+		t.Errorf("block key level 0: %.1f bits, want less than the %.1f bits of a uniform key", b.Lv0.Bits(), u.Lv0.Bits())
+	}
+	if b.Lv1 != u.Lv1 {
+		t.Errorf("block key level 1: %s, want the uniform estimate %s", b.Lv1, u.Lv1)
+	}
+}
+
+func TestCheckSecurity(t *testing.T) {
+	weak := params.GetParameters(params.Security80Bit)
+	weak.TLWELv0.N = 300
+
+	testCases := []struct {
+		name     string
+		p        params.Parameters
+		bits     float64
+		insecure bool
+	}{
+		{"80-bit", params.GetParameters(params.Security80Bit), 80, false},
+		{"128-bit at 90 bits", params.GetParameters(params.Security128Bit), 90, false},
+		{"small level 0 dimension", weak, 80, true},
+		{"noiseless bootstrapping key", params.GetParameters(params.SecurityUint5), 80, true},
+	}
+	for _, tc := range testCases {
+		err := tc.p.CheckSecurity(tc.bits)
+		if got := errors.Is(err, params.ErrInsecure); got != tc.insecure {
+			t.Errorf("%s: CheckSecurity(%.0f) = %v", tc.name, tc.bits, err)
+		}
+	}
+
+	if info := params.GetParameters(params.Security128Bit).SecurityInfo(); !strings.Contains(info, "estimated") {
+		t.Errorf("SecurityInfo() = %q, want the security estimate", info)
+	}
+}
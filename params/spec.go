@@ -0,0 +1,175 @@
+package params
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+)
+
+// ErrParamsFile is returned when a parameter file cannot be parsed
+var ErrParamsFile = errors.New("params: malformed parameter file")
+
+// Spec is the file format of custom parameter sets, in JSON or YAML:
+//
+//	name: uint5-wide
+//	base: uint5          # start from a registered set (optional)
+//	min_security: 80     # reject the set below this estimate (optional)
+//	lv0:
+//	  n: 1100
+//	  alpha: 5.0e-08
+//	lv1:
+//	  n: 2048
+//	  alpha: 2.2e-17
+//	decomposition:
+//	  bgbit: 22
+//	  l: 1
+//	key_switch:
+//	  basebit: 4
+//	  t: 8
+//	block_size: 3
+//	lookup_table_size: 0
+//
+// Sections left out keep the values of the base set.
+type Spec struct {
+	Name            string             `json:"name"`
+	Base            string             `json:"base"`
+	MinSecurity     float64            `json:"min_security"`
+	Lv0             *LWESpec           `json:"lv0"`
+	Lv1             *LWESpec           `json:"lv1"`
+	Decomposition   *DecompositionSpec `json:"decomposition"`
+	KeySwitch       *KeySwitchSpec     `json:"key_switch"`
+	BlockSize       *int               `json:"block_size"`
+	LookUpTableSize *int               `json:"lookup_table_size"`
+}
+
+// LWESpec is the dimension and noise of one level of a Spec
+type LWESpec struct {
+	N     int     `json:"n"`
+	Alpha float64 `json:"alpha"`
+}
+
+// DecompositionSpec is the gadget decomposition of a Spec: L levels of base 2^BGBIT
+type DecompositionSpec struct {
+	BGBIT uint32 `json:"bgbit"`
+	L     int    `json:"l"`
+}
+
+// KeySwitchSpec is the key switching decomposition of a Spec: T levels of
+// base 2^BASEBIT
+type KeySwitchSpec struct {
+	BASEBIT int `json:"basebit"`
+	T       int `json:"t"`
+}
+
+// Build returns the parameter set described by the spec, with level
+// SecurityCustom. It does not register it.
+func (s Spec) Build() (Parameters, error) {
+	var base Parameters
+	if s.Base != "" {
+		var err error
+		if base, err = Lookup(s.Base); err != nil {
+			return Parameters{}, err
+		}
+	}
+
+	b := NewBuilder(base)
+	if s.Lv0 != nil {
+		b.Lv0(s.Lv0.N, s.Lv0.Alpha)
+	}
+	if s.Lv1 != nil {
+		b.Lv1(s.Lv1.N, s.Lv1.Alpha)
+	}
+	if s.Decomposition != nil {
+		b.Decomposition(s.Decomposition.BGBIT, s.Decomposition.L)
+	}
+	if s.KeySwitch != nil {
+		b.KeySwitch(s.KeySwitch.BASEBIT, s.KeySwitch.T)
+	}
+	if s.BlockSize != nil {
+		b.BlockSize(*s.BlockSize)
+	}
+	if s.LookUpTableSize != nil {
+		b.LookUpTableSize(*s.LookUpTableSize)
+	}
+
+	p, err := b.Build()
+	if err != nil {
+		return Parameters{}, err
+	}
+	if s.MinSecurity > 0 {
+		if err := p.CheckSecurity(s.MinSecurity); err != nil {
+			return Parameters{}, err
+		}
+	}
+	return p, nil
+}
+
+// ReadSpecJSON reads a Spec in JSON. Unknown fields are errors.
+func ReadSpecJSON(r io.Reader) (Spec, error) {
+	var s Spec
+	dec := json.NewDecoder(r)
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&s); err != nil {
+		return Spec{}, fmt.Errorf("%w: %v", ErrParamsFile, err)
+	}
+	return s, nil
+}
+
+// ReadSpecYAML reads a Spec in YAML. Parameter files only need nested
+// mappings of scalars, so only that subset of YAML is supported: block
+// mappings, plain and quoted scalars, comments and document markers.
+func ReadSpecYAML(r io.Reader) (Spec, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return Spec{}, err
+	}
+	doc, err := parseYAML(data)
+	if err != nil {
+		return Spec{}, err
+	}
+	// The mapping has the shape of the JSON document, so it goes through
+	// the same decoder
+	yamlStrings(doc, reflect.TypeOf(Spec{}))
+	encoded, err := json.Marshal(doc)
+	if err != nil {
+		return Spec{}, fmt.Errorf("%w: %v", ErrParamsFile, err)
+	}
+	return ReadSpecJSON(bytes.NewReader(encoded))
+}
+
+// LoadFile reads a Spec from a .json, .yaml or .yml file and builds it.
+// A named spec is registered under its name.
+func LoadFile(path string) (Parameters, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return Parameters{}, err
+	}
+	defer f.Close()
+
+	var s Spec
+	switch ext := filepath.Ext(path); ext {
+	case ".json":
+		s, err = ReadSpecJSON(f)
+	case ".yaml", ".yml":
+		s, err = ReadSpecYAML(f)
+	default:
+		return Parameters{}, fmt.Errorf("%w: unknown extension %q", ErrParamsFile, ext)
+	}
+	if err != nil {
+		return Parameters{}, fmt.Errorf("%s: %w", path, err)
+	}
+
+	p, err := s.Build()
+	if err != nil {
+		return Parameters{}, fmt.Errorf("%s: %w", path, err)
+	}
+	if s.Name == "" {
+		return p, nil
+	}
+	return Register(s.Name, p)
+}
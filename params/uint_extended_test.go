@@ -0,0 +1,30 @@
+//go:build largekeys
+
+package params_test
+
+import (
+	"testing"
+
+	"github.com/thedonutfactory/go-tfhe/params"
+)
+
+// TestExtendedUintParameters tests the Uint parameter sets with extended
+// lookup tables. Their key switching keys take 1.7 GB (Uint6) to 3.7 GB
+// (Uint7, Uint8), so they only run with -tags largekeys (make test-largekeys).
+func TestExtendedUintParameters(t *testing.T) {
+	testCases := []struct {
+		name           string
+		secLevel       params.SecurityLevel
+		messageModulus int
+	}{
+		{"Uint6", params.SecurityUint6, 64},
+		{"Uint7", params.SecurityUint7, 128},
+		{"Uint8", params.SecurityUint8, 256},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			testUintParameterSet(t, tc.secLevel, tc.name, tc.messageModulus)
+		})
+	}
+}
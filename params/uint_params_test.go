@@ -13,49 +13,43 @@ import (
 	"github.com/thedonutfactory/go-tfhe/tlwe"
 )
 
-// TestAllUintParameters tests all Uint parameter sets with programmable bootstrapping
+// TestAllUintParameters tests the Uint parameter sets with programmable
+// bootstrapping. The extended lookup table sets Uint6-Uint8 are tested in
+// uint_extended_test.go.
 func TestAllUintParameters(t *testing.T) {
 	testCases := []struct {
 		name           string
 		secLevel       params.SecurityLevel
 		messageModulus int
-		skipReason     string // If non-empty, test will be skipped
 	}{
-		{"Uint1", params.SecurityUint1, 2, ""},
-		{"Uint2", params.SecurityUint2, 4, ""},
-		{"Uint3", params.SecurityUint3, 8, ""},
-		{"Uint4", params.SecurityUint4, 16, ""},
-		{"Uint5", params.SecurityUint5, 32, ""},
-		{"Uint6", params.SecurityUint6, 64, "Extended LUT (polyExtendFactor=2) not fully implemented"},
-		{"Uint7", params.SecurityUint7, 128, "Extended LUT (polyExtendFactor=4) not fully implemented"},
-		{"Uint8", params.SecurityUint8, 256, "Extended LUT (polyExtendFactor=9) not fully implemented"},
+		{"Uint1", params.SecurityUint1, 2},
+		{"Uint2", params.SecurityUint2, 4},
+		{"Uint3", params.SecurityUint3, 8},
+		{"Uint4", params.SecurityUint4, 16},
+		{"Uint5", params.SecurityUint5, 32},
 	}
 
 	for _, tc := range testCases {
 		t.Run(tc.name, func(t *testing.T) {
-			if tc.skipReason != "" {
-				t.Skipf("Skipping %s: %s", tc.name, tc.skipReason)
-				return
-			}
 			testUintParameterSet(t, tc.secLevel, tc.name, tc.messageModulus)
 		})
 	}
 }
 
 func testUintParameterSet(t *testing.T, secLevel params.SecurityLevel, name string, messageModulus int) {
-	params.CurrentSecurityLevel = secLevel
+	p := params.GetParameters(secLevel)
 
-	t.Logf("Testing %s with messageModulus=%d, N=%d", name, messageModulus, params.GetTRGSWLv1().N)
+	t.Logf("Testing %s with messageModulus=%d, N=%d", name, messageModulus, p.TRGSWLv1.N)
 
 	// Generate keys
 	keyStart := time.Now()
-	secretKey := key.NewSecretKey()
+	secretKey := key.NewSecretKeyWithParams(p)
 	cloudKey := cloudkey.NewCloudKey(secretKey)
-	eval := evaluator.NewEvaluator(params.GetTRGSWLv1().N)
+	eval := evaluator.NewEvaluatorWithParams(p)
 	keyDuration := time.Since(keyStart)
 	t.Logf("Key generation: %v", keyDuration)
 
-	gen := lut.NewGenerator(messageModulus)
+	gen := lut.NewGeneratorWithParams(p, messageModulus)
 
 	// Test identity function on a subset of values
 	t.Run("Identity", func(t *testing.T) {
@@ -65,8 +59,8 @@ func testUintParameterSet(t *testing.T, secLevel params.SecurityLevel, name stri
 		testValues := getTestValues(messageModulus)
 
 		for _, x := range testValues {
-			ct := tlwe.NewTLWELv0()
-			ct.EncryptLWEMessage(x, messageModulus, params.GetTLWELv0().ALPHA, secretKey.KeyLv0)
+			ct := tlwe.NewTLWELv0WithParams(p)
+			ct.EncryptLWEMessage(x, messageModulus, p.TLWELv0.ALPHA, secretKey.KeyLv0)
 
 			ctResult := eval.BootstrapLUT(ct, lutId, cloudKey.BootstrappingKey, cloudKey.KeySwitchingKey, cloudKey.DecompositionOffset)
 
@@ -87,8 +81,8 @@ func testUintParameterSet(t *testing.T, secLevel params.SecurityLevel, name stri
 		testValues := getTestValues(messageModulus)
 
 		for _, x := range testValues {
-			ct := tlwe.NewTLWELv0()
-			ct.EncryptLWEMessage(x, messageModulus, params.GetTLWELv0().ALPHA, secretKey.KeyLv0)
+			ct := tlwe.NewTLWELv0WithParams(p)
+			ct.EncryptLWEMessage(x, messageModulus, p.TLWELv0.ALPHA, secretKey.KeyLv0)
 
 			ctResult := eval.BootstrapLUT(ct, lutComplement, cloudKey.BootstrappingKey, cloudKey.KeySwitchingKey, cloudKey.DecompositionOffset)
 
@@ -111,8 +105,8 @@ func testUintParameterSet(t *testing.T, secLevel params.SecurityLevel, name stri
 		testValues := getTestValues(messageModulus)
 
 		for _, x := range testValues {
-			ct := tlwe.NewTLWELv0()
-			ct.EncryptLWEMessage(x, messageModulus, params.GetTLWELv0().ALPHA, secretKey.KeyLv0)
+			ct := tlwe.NewTLWELv0WithParams(p)
+			ct.EncryptLWEMessage(x, messageModulus, p.TLWELv0.ALPHA, secretKey.KeyLv0)
 
 			ctResult := eval.BootstrapLUT(ct, lutMod, cloudKey.BootstrappingKey, cloudKey.KeySwitchingKey, cloudKey.DecompositionOffset)
 
@@ -164,11 +158,11 @@ func BenchmarkUintParameters(b *testing.B) {
 
 	for _, ps := range paramSets {
 		b.Run(fmt.Sprintf("KeyGen/%s", ps.name), func(b *testing.B) {
-			params.CurrentSecurityLevel = ps.secLevel
+			p := params.GetParameters(ps.secLevel)
 			b.ResetTimer()
 
 			for i := 0; i < b.N; i++ {
-				secretKey := key.NewSecretKey()
+				secretKey := key.NewSecretKeyWithParams(p)
 				_ = cloudkey.NewCloudKey(secretKey)
 			}
 		})
@@ -194,17 +188,17 @@ func BenchmarkPBS(b *testing.B) {
 
 	for _, ps := range paramSets {
 		b.Run(ps.name, func(b *testing.B) {
-			params.CurrentSecurityLevel = ps.secLevel
+			p := params.GetParameters(ps.secLevel)
 
-			secretKey := key.NewSecretKey()
+			secretKey := key.NewSecretKeyWithParams(p)
 			cloudKey := cloudkey.NewCloudKey(secretKey)
-			eval := evaluator.NewEvaluator(params.GetTRGSWLv1().N)
+			eval := evaluator.NewEvaluatorWithParams(p)
 
-			gen := lut.NewGenerator(ps.messageModulus)
+			gen := lut.NewGeneratorWithParams(p, ps.messageModulus)
 			lutId := gen.GenLookUpTable(func(x int) int { return x })
 
-			ct := tlwe.NewTLWELv0()
-			ct.EncryptLWEMessage(1, ps.messageModulus, params.GetTLWELv0().ALPHA, secretKey.KeyLv0)
+			ct := tlwe.NewTLWELv0WithParams(p)
+			ct.EncryptLWEMessage(1, ps.messageModulus, p.TLWELv0.ALPHA, secretKey.KeyLv0)
 
 			b.ResetTimer()
 
@@ -236,10 +230,10 @@ func TestUintParameterProperties(t *testing.T) {
 
 	for _, tc := range testCases {
 		t.Run(tc.name, func(t *testing.T) {
-			params.CurrentSecurityLevel = tc.secLevel
+			p := params.GetParameters(tc.secLevel)
 
-			n := params.GetTRGSWLv1().N
-			lweN := params.GetTLWELv0().N
+			n := p.TRGSWLv1.N
+			lweN := p.TLWELv0.N
 
 			if n != tc.expectedN {
 				t.Errorf("Polynomial degree: got %d, want %d", n, tc.expectedN)
@@ -250,11 +244,11 @@ func TestUintParameterProperties(t *testing.T) {
 			}
 
 			// Verify other parameters are set
-			if params.GetTLWELv0().ALPHA == 0 {
+			if p.TLWELv0.ALPHA == 0 {
 				t.Error("LWE noise not set")
 			}
 
-			if params.GetTRGSWLv1().BG == 0 {
+			if p.TRGSWLv1.BG == 0 {
 				t.Error("TRGSW base not set")
 			}
 
@@ -0,0 +1,228 @@
+package params
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// yamlLine is a "key: value" line of a YAML document
+type yamlLine struct {
+	num    int // Line number, from 1
+	indent int
+	key    string
+	value  string // Empty for the parent of a nested mapping
+}
+
+func yamlError(line int, msg string, args ...any) error {
+	return fmt.Errorf("%w: yaml line %d: %s", ErrParamsFile, line, fmt.Sprintf(msg, args...))
+}
+
+// parseYAML parses a YAML document made of block mappings and scalars into
+// nested maps. Sequences, flow collections, anchors and multi-line scalars
+// are rejected.
+func parseYAML(data []byte) (map[string]any, error) {
+	var lines []yamlLine
+	for i, text := range strings.Split(string(data), "\n") {
+		num := i + 1
+		text = strings.TrimRight(stripYAMLComment(text), " \t\r")
+		trimmed := strings.TrimLeft(text, " ")
+		if trimmed == "" || trimmed == "---" || trimmed == "..." {
+			continue
+		}
+		if strings.HasPrefix(trimmed, "\t") {
+			return nil, yamlError(num, "tabs are not allowed in indentation")
+		}
+		if strings.HasPrefix(trimmed, "- ") || trimmed == "-" {
+			return nil, yamlError(num, "sequences are not supported")
+		}
+
+		key, value, ok := splitYAMLKey(trimmed)
+		if !ok {
+			return nil, yamlError(num, "expected \"key: value\", found %q", trimmed)
+		}
+		key, err := yamlKey(key)
+		if err != nil {
+			return nil, yamlError(num, "%v", err)
+		}
+		lines = append(lines, yamlLine{num: num, indent: len(text) - len(trimmed), key: key, value: value})
+	}
+	if len(lines) == 0 {
+		return map[string]any{}, nil
+	}
+
+	doc, next, err := parseYAMLMapping(lines, 0, lines[0].indent)
+	if err != nil {
+		return nil, err
+	}
+	if next < len(lines) {
+		return nil, yamlError(lines[next].num, "unexpected indentation")
+	}
+	return doc, nil
+}
+
+// parseYAMLMapping parses the mapping starting at lines[i], whose keys are
+// indented by indent. It returns the index of the first line after it.
+func parseYAMLMapping(lines []yamlLine, i, indent int) (map[string]any, int, error) {
+	m := make(map[string]any)
+	for i < len(lines) && lines[i].indent >= indent {
+		l := lines[i]
+		if l.indent > indent {
+			return nil, i, yamlError(l.num, "unexpected indentation")
+		}
+		if _, ok := m[l.key]; ok {
+			return nil, i, yamlError(l.num, "duplicate key %q", l.key)
+		}
+		i++
+
+		if l.value != "" {
+			v, err := yamlScalar(l.value)
+			if err != nil {
+				return nil, i, yamlError(l.num, "%v", err)
+			}
+			m[l.key] = v
+			continue
+		}
+		if i < len(lines) && lines[i].indent > indent {
+			child, next, err := parseYAMLMapping(lines, i, lines[i].indent)
+			if err != nil {
+				return nil, next, err
+			}
+			m[l.key] = child
+			i = next
+			continue
+		}
+		m[l.key] = nil
+	}
+	return m, i, nil
+}
+
+// stripYAMLComment removes a comment: a '#' at the start of the line or
+// after a space, outside of quoted scalars
+func stripYAMLComment(text string) string {
+	var quote byte
+	for i := 0; i < len(text); i++ {
+		c := text[i]
+		switch {
+		case quote != 0:
+			if c == '\\' && quote == '"' {
+				i++
+			} else if c == quote {
+				quote = 0
+			}
+		case (c == '"' || c == '\'') && (i == 0 || text[i-1] == ' '):
+			quote = c
+		case c == '#' && (i == 0 || text[i-1] == ' ' || text[i-1] == '\t'):
+			return text[:i]
+		}
+	}
+	return text
+}
+
+// splitYAMLKey splits "key: value" or "key:" at the first ": " outside of
+// a quoted key
+func splitYAMLKey(text string) (key, value string, ok bool) {
+	start := 0
+	if text[0] == '"' || text[0] == '\'' {
+		end := strings.IndexByte(text[1:], text[0])
+		if end < 0 {
+			return "", "", false
+		}
+		start = end + 2
+	}
+	for i := start; i < len(text); i++ {
+		if text[i] == ':' && (i+1 == len(text) || text[i+1] == ' ') {
+			return strings.TrimSpace(text[:i]), strings.TrimSpace(text[i+1:]), true
+		}
+	}
+	return "", "", false
+}
+
+// yamlKey unquotes a mapping key
+func yamlKey(key string) (string, error) {
+	v, err := yamlScalar(key)
+	if err != nil {
+		return "", err
+	}
+	s, ok := v.(string)
+	if !ok || s == "" {
+		return "", fmt.Errorf("key %q is not a string", key)
+	}
+	return s, nil
+}
+
+// yamlNumber is a plain scalar that parses as a number. It keeps its text so
+// that it can still be decoded into a string field (see yamlStrings).
+type yamlNumber string
+
+// MarshalJSON encodes n as a JSON number
+func (n yamlNumber) MarshalJSON() ([]byte, error) {
+	f, err := strconv.ParseFloat(strings.ReplaceAll(string(n), "_", ""), 64)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(f)
+}
+
+// yamlStrings turns the numbers of doc that belong to string fields of the
+// struct type t back into their text, so "name: 2048" is the name "2048"
+// rather than a type error. Nested mappings follow the struct fields.
+func yamlStrings(doc map[string]any, t reflect.Type) {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		ft := field.Type
+		if ft.Kind() == reflect.Pointer {
+			ft = ft.Elem()
+		}
+		switch v := doc[name].(type) {
+		case yamlNumber:
+			if ft.Kind() == reflect.String {
+				doc[name] = string(v)
+			}
+		case map[string]any:
+			if ft.Kind() == reflect.Struct {
+				yamlStrings(v, ft)
+			}
+		}
+	}
+}
+
+// yamlScalar converts a scalar to a string, yamlNumber, bool or nil
+func yamlScalar(s string) (any, error) {
+	if s == "" {
+		return "", nil
+	}
+	switch s[0] {
+	case '"':
+		v, err := strconv.Unquote(s)
+		if err != nil {
+			return nil, fmt.Errorf("bad double-quoted scalar %s", s)
+		}
+		return v, nil
+	case '\'':
+		if len(s) < 2 || s[len(s)-1] != '\'' {
+			return nil, fmt.Errorf("bad single-quoted scalar %s", s)
+		}
+		return strings.ReplaceAll(s[1:len(s)-1], "''", "'"), nil
+	case '[', '{':
+		return nil, fmt.Errorf("flow collections are not supported")
+	case '&', '*', '!', '|', '>':
+		return nil, fmt.Errorf("anchors, tags and block scalars are not supported")
+	}
+
+	switch s {
+	case "~", "null", "Null", "NULL":
+		return nil, nil
+	case "true", "True", "TRUE":
+		return true, nil
+	case "false", "False", "FALSE":
+		return false, nil
+	}
+	if _, err := strconv.ParseFloat(strings.ReplaceAll(s, "_", ""), 64); err == nil {
+		return yamlNumber(s), nil
+	}
+	return s, nil
+}
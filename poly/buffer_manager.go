@@ -7,6 +7,8 @@ import "github.com/thedonutfactory/go-tfhe/params"
 type BufferManager struct {
 	// Polynomial degree
 	n int
+	// Gadget decomposition levels
+	l int
 
 	// === FFT Buffers ===
 
@@ -60,11 +62,10 @@ type BufferManager struct {
 	}
 }
 
-// NewBufferManager creates a new centralized buffer manager
-func NewBufferManager(n int) *BufferManager {
-	l := params.GetTRGSWLv1().L
-
-	bm := &BufferManager{n: n}
+// NewBufferManager creates a new centralized buffer manager for polynomial
+// degree n and l gadget decomposition levels
+func NewBufferManager(n, l int) *BufferManager {
+	bm := &BufferManager{n: n, l: l}
 
 	// Initialize FFT buffers
 	bm.FFT.Poly = NewPoly(n)
@@ -144,7 +145,7 @@ func (bm *BufferManager) Reset() {
 // MemoryUsage returns approximate memory usage in bytes
 func (bm *BufferManager) MemoryUsage() int {
 	n := bm.n
-	l := params.GetTRGSWLv1().L
+	l := bm.l
 
 	// Poly: N * 4 bytes, FourierPoly: N * 8 * 2 bytes (complex)
 	polySize := n * 4
@@ -59,6 +59,30 @@ func (e *Evaluator) ToPolySubAssignUnsafe(fp FourierPoly, pOut Poly) {
 	convertFourierPolyToPolySubAssign(fp.Coeffs, pOut.Coeffs)
 }
 
+// MonomialToFourierPolyAssign transforms the monomial X^d to FourierPoly and writes it to fpOut.
+// This is equivalent to ToFourierPolyAssign on X^d, without running the FFT.
+func (e *Evaluator) MonomialToFourierPolyAssign(d int, fpOut FourierPoly) {
+	mask := 2*e.degree - 1
+	d &= mask
+
+	for j, jj := 0, 0; j < e.degree; j, jj = j+8, jj+4 {
+		c0 := e.twMono[(e.twMonoIdx[jj+0]*d)&mask]
+		c1 := e.twMono[(e.twMonoIdx[jj+1]*d)&mask]
+		c2 := e.twMono[(e.twMonoIdx[jj+2]*d)&mask]
+		c3 := e.twMono[(e.twMonoIdx[jj+3]*d)&mask]
+
+		fpOut.Coeffs[j+0] = real(c0)
+		fpOut.Coeffs[j+1] = real(c1)
+		fpOut.Coeffs[j+2] = real(c2)
+		fpOut.Coeffs[j+3] = real(c3)
+
+		fpOut.Coeffs[j+4] = imag(c0)
+		fpOut.Coeffs[j+5] = imag(c1)
+		fpOut.Coeffs[j+6] = imag(c2)
+		fpOut.Coeffs[j+7] = imag(c3)
+	}
+}
+
 // convertPolyToFourierPolyAssign converts and folds p to fpOut.
 // This splits the polynomial into two halves and interleaves them for SIMD efficiency.
 func convertPolyToFourierPolyAssign(p []params.Torus, fpOut []float64) {
@@ -0,0 +1,240 @@
+package poly
+
+import (
+	"math"
+	"unsafe"
+
+	"github.com/thedonutfactory/go-tfhe/params"
+)
+
+const (
+	// Limbs64 is the number of limbs a 64-bit torus polynomial is split into
+	// for the FFT. The float64 FFT is exact on products of up to about 50
+	// bits, so each coefficient is cut into balanced 16-bit digits that are
+	// multiplied separately and recombined modulo 2^64.
+	Limbs64 = 4
+
+	// limbBits64 is the number of bits of each limb
+	limbBits64 = 16
+)
+
+// Poly64 is a polynomial over Z_(2^64)[X]/(X^N + 1).
+type Poly64 struct {
+	Coeffs []params.Torus64
+}
+
+// NewPoly64 creates a 64-bit polynomial with degree N.
+func NewPoly64(N int) Poly64 {
+	if !isPowerOfTwo(N) {
+		panic("degree not power of two")
+	}
+	if N < MinDegree {
+		panic("degree smaller than MinDegree")
+	}
+	return Poly64{Coeffs: make([]params.Torus64, N)}
+}
+
+// Clear clears all coefficients to zero.
+func (p Poly64) Clear() {
+	for i := range p.Coeffs {
+		p.Coeffs[i] = 0
+	}
+}
+
+// FourierPoly64 is a fourier transformed Poly64: Limbs[k] holds limb k of
+// every coefficient, with weight 2^(16k) and a value in [-2^15, 2^15).
+//
+// A FourierPoly64 is only multiplied by a FourierPoly of small integers
+// (decomposed digits or a binary key), so that every limb of the product
+// stays exact.
+type FourierPoly64 struct {
+	Limbs [Limbs64]FourierPoly
+}
+
+// NewFourierPoly64 creates a 64-bit fourier polynomial with degree N.
+func NewFourierPoly64(N int) FourierPoly64 {
+	var fp FourierPoly64
+	for k := range fp.Limbs {
+		fp.Limbs[k] = NewFourierPoly(N)
+	}
+	return fp
+}
+
+// Clear clears all coefficients to zero.
+func (p FourierPoly64) Clear() {
+	for _, limb := range p.Limbs {
+		limb.Clear()
+	}
+}
+
+// NewPoly64 creates a 64-bit polynomial with the degree of the evaluator.
+func (e *Evaluator) NewPoly64() Poly64 {
+	return NewPoly64(e.degree)
+}
+
+// NewFourierPoly64 creates a 64-bit fourier polynomial with the degree of the evaluator.
+func (e *Evaluator) NewFourierPoly64() FourierPoly64 {
+	return NewFourierPoly64(e.degree)
+}
+
+// ToFourierPoly64 transforms Poly64 to FourierPoly64.
+func (e *Evaluator) ToFourierPoly64(p Poly64) FourierPoly64 {
+	fpOut := e.NewFourierPoly64()
+	e.ToFourierPoly64Assign(p, fpOut)
+	return fpOut
+}
+
+// ToFourierPoly64Assign splits p into limbs, transforms them and writes them to fpOut.
+func (e *Evaluator) ToFourierPoly64Assign(p Poly64, fpOut FourierPoly64) {
+	convertPoly64ToFourierPoly64Assign(p.Coeffs, fpOut)
+	for _, limb := range fpOut.Limbs {
+		fftInPlace(limb.Coeffs, e.tw)
+	}
+}
+
+// SmallPoly64ToFourierPolyAssign transforms a Poly64 whose coefficients are
+// small signed integers (read as int64) to a single FourierPoly and writes
+// it to fpOut. Use it for decomposed digits and keys, the operands of
+// MulAddFourierPoly64Assign.
+func (e *Evaluator) SmallPoly64ToFourierPolyAssign(p Poly64, fpOut FourierPoly) {
+	convertSmallPoly64ToFourierPolyAssign(p.Coeffs, fpOut.Coeffs)
+	fftInPlace(fpOut.Coeffs, e.tw)
+}
+
+// MulAddFourierPoly64Assign computes fpOut += fp0 * fp1, where fp0 is a
+// transformed polynomial of small integers.
+func (e *Evaluator) MulAddFourierPoly64Assign(fp0 FourierPoly, fp1, fpOut FourierPoly64) {
+	for k := range fpOut.Limbs {
+		elementWiseMulAddCmplxAssign(fp0.Coeffs, fp1.Limbs[k].Coeffs, fpOut.Limbs[k].Coeffs)
+	}
+}
+
+// ToPoly64AssignUnsafe transforms FourierPoly64 to Poly64 and writes it to pOut.
+// This method modifies fp directly, so use it only if you don't need fp after.
+func (e *Evaluator) ToPoly64AssignUnsafe(fp FourierPoly64, pOut Poly64) {
+	pOut.Clear()
+	e.ToPoly64AddAssignUnsafe(fp, pOut)
+}
+
+// ToPoly64AddAssignUnsafe transforms FourierPoly64 to Poly64 and adds it to pOut.
+// This method modifies fp directly.
+func (e *Evaluator) ToPoly64AddAssignUnsafe(fp FourierPoly64, pOut Poly64) {
+	for k, limb := range fp.Limbs {
+		ifftInPlace(limb.Coeffs, e.twInv)
+		convertFourierPolyToPoly64AddAssign(limb.Coeffs, uint(k*limbBits64), pOut.Coeffs)
+	}
+}
+
+// MulPoly64Assign computes pOut = p0 * p1, where p1 has small signed
+// coefficients (a key or decomposed digits).
+func (e *Evaluator) MulPoly64Assign(p0, p1, pOut Poly64) {
+	fp0 := e.ToFourierPoly64(p0)
+	fp1 := e.NewFourierPoly()
+	e.SmallPoly64ToFourierPolyAssign(p1, fp1)
+
+	fpOut := e.NewFourierPoly64()
+	e.MulAddFourierPoly64Assign(fp1, fp0, fpOut)
+	e.ToPoly64AssignUnsafe(fpOut, pOut)
+}
+
+// PolyMulWithXKInPlace64 multiplies a 64-bit polynomial by X^k in the ring
+// Z[X]/(X^N+1) and writes it to result
+func PolyMulWithXKInPlace64(a []params.Torus64, k int, result []params.Torus64) {
+	n := len(a)
+	k = k % (2 * n) // Normalize k to [0, 2N)
+	if k < 0 {
+		k += 2 * n
+	}
+
+	if k < n {
+		// Positive rotation: coefficients shift right, wrap with negation
+		for i := 0; i < n-k; i++ {
+			result[i+k] = a[i]
+		}
+		for i := n - k; i < n; i++ {
+			result[i+k-n] = -a[i]
+		}
+	} else {
+		// Rotation >= n: all coefficients get negated
+		k -= n
+		for i := 0; i < n-k; i++ {
+			result[i+k] = -a[i]
+		}
+		for i := n - k; i < n; i++ {
+			result[i+k-n] = a[i]
+		}
+	}
+}
+
+// splitLimbs64 splits x into balanced limbs: x = sum_k limbs[k] * 2^(16k) mod 2^64
+func splitLimbs64(x params.Torus64) [Limbs64]float64 {
+	var limbs [Limbs64]float64
+	c := uint64(x)
+	for k := range limbs {
+		d := int64(int16(c))
+		limbs[k] = float64(d)
+		c = (c - uint64(d)) >> limbBits64
+	}
+	return limbs
+}
+
+// convertPoly64ToFourierPoly64Assign splits, converts and folds p to the limbs of fpOut.
+func convertPoly64ToFourierPoly64Assign(p []params.Torus64, fpOut FourierPoly64) {
+	N := len(p)
+
+	for i, ii := 0, 0; i < N; i, ii = i+8, ii+4 {
+		for j := 0; j < 4; j++ {
+			// First half (real parts) and second half (imaginary parts)
+			lo := splitLimbs64(p[ii+j])
+			hi := splitLimbs64(p[ii+j+N/2])
+			for k, limb := range fpOut.Limbs {
+				limb.Coeffs[i+j] = lo[k]
+				limb.Coeffs[i+j+4] = hi[k]
+			}
+		}
+	}
+}
+
+// convertSmallPoly64ToFourierPolyAssign converts and folds p, read as signed integers, to fpOut.
+func convertSmallPoly64ToFourierPolyAssign(p []params.Torus64, fpOut []float64) {
+	N := len(p)
+
+	for i, ii := 0, 0; i < N; i, ii = i+8, ii+4 {
+		q0 := (*[4]params.Torus64)(unsafe.Pointer(&p[ii]))
+		q1 := (*[4]params.Torus64)(unsafe.Pointer(&p[ii+N/2]))
+		fqOut := (*[8]float64)(unsafe.Pointer(&fpOut[i]))
+
+		fqOut[0] = float64(int64(q0[0]))
+		fqOut[1] = float64(int64(q0[1]))
+		fqOut[2] = float64(int64(q0[2]))
+		fqOut[3] = float64(int64(q0[3]))
+
+		fqOut[4] = float64(int64(q1[0]))
+		fqOut[5] = float64(int64(q1[1]))
+		fqOut[6] = float64(int64(q1[2]))
+		fqOut[7] = float64(int64(q1[3]))
+	}
+}
+
+// convertFourierPolyToPoly64AddAssign rounds and unfolds one limb, and adds
+// it to pOut shifted left by shift bits. The rounded values are exact
+// integers below 2^53, so they are not reduced before the shift.
+func convertFourierPolyToPoly64AddAssign(fp []float64, shift uint, pOut []params.Torus64) {
+	N := len(fp)
+
+	for i, ii := 0, 0; i < N; i, ii = i+8, ii+4 {
+		fq := (*[8]float64)(unsafe.Pointer(&fp[i]))
+		qOut0 := (*[4]params.Torus64)(unsafe.Pointer(&pOut[ii]))
+		qOut1 := (*[4]params.Torus64)(unsafe.Pointer(&pOut[ii+N/2]))
+
+		qOut0[0] += params.Torus64(int64(math.Round(fq[0]))) << shift
+		qOut0[1] += params.Torus64(int64(math.Round(fq[1]))) << shift
+		qOut0[2] += params.Torus64(int64(math.Round(fq[2]))) << shift
+		qOut0[3] += params.Torus64(int64(math.Round(fq[3]))) << shift
+
+		qOut1[0] += params.Torus64(int64(math.Round(fq[4]))) << shift
+		qOut1[1] += params.Torus64(int64(math.Round(fq[5]))) << shift
+		qOut1[2] += params.Torus64(int64(math.Round(fq[6]))) << shift
+		qOut1[3] += params.Torus64(int64(math.Round(fq[7]))) << shift
+	}
+}
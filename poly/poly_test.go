@@ -1,6 +1,7 @@
 package poly
 
 import (
+	"math/rand"
 	"testing"
 
 	"github.com/thedonutfactory/go-tfhe/params"
@@ -122,3 +123,74 @@ func BenchmarkElementWiseMul(b *testing.B) {
 		eval.MulFourierPolyAssign(fp1, fp2, fp1)
 	}
 }
+
+// TestMonomialToFourierPoly checks the direct monomial transform against the FFT of X^d
+func TestMonomialToFourierPoly(t *testing.T) {
+	eval := NewEvaluator(1024)
+	fpMono := eval.NewFourierPoly()
+
+	for _, d := range []int{0, 1, 7, 1023, 1024, 1500, 2047, -3} {
+		p := eval.NewPoly()
+		dd := d & 2047
+		if dd < 1024 {
+			p.Coeffs[dd] = 1
+		} else {
+			p.Coeffs[dd-1024] = ^params.Torus(0)
+		}
+
+		fpExpected := eval.ToFourierPoly(p)
+		eval.MonomialToFourierPolyAssign(d, fpMono)
+
+		for i := range fpExpected.Coeffs {
+			diff := fpExpected.Coeffs[i] - fpMono.Coeffs[i]
+			if diff > 1e-9 || diff < -1e-9 {
+				t.Errorf("X^%d: coefficient %d = %v, want %v", d, i, fpMono.Coeffs[i], fpExpected.Coeffs[i])
+				break
+			}
+		}
+	}
+}
+
+// TestMulPoly64 checks the limb-split multiplication of 64-bit polynomials
+// by small polynomials against the schoolbook negacyclic product
+func TestMulPoly64(t *testing.T) {
+	const n = 2048
+	eval := NewEvaluator(n)
+	rng := rand.New(rand.NewSource(1))
+
+	testCases := []struct {
+		name  string
+		small func() int64
+	}{
+		{"binary key", func() int64 { return int64(rng.Intn(2)) }},
+		{"22-bit digits", func() int64 { return rng.Int63n(1<<22) - 1<<21 }},
+	}
+	for _, tc := range testCases {
+		p0, p1 := eval.NewPoly64(), eval.NewPoly64()
+		for i := 0; i < n; i++ {
+			p0.Coeffs[i] = params.Torus64(rng.Uint64())
+			p1.Coeffs[i] = params.Torus64(tc.small())
+		}
+
+		want := make([]params.Torus64, n)
+		for i := 0; i < n; i++ {
+			for j := 0; j < n; j++ {
+				prod := p0.Coeffs[i] * p1.Coeffs[j]
+				if i+j < n {
+					want[i+j] += prod
+				} else {
+					want[i+j-n] -= prod
+				}
+			}
+		}
+
+		got := eval.NewPoly64()
+		eval.MulPoly64Assign(p0, p1, got)
+		for i := range want {
+			if got.Coeffs[i] != want[i] {
+				t.Errorf("%s: coefficient %d = 0x%016x, want 0x%016x", tc.name, i, uint64(got.Coeffs[i]), uint64(want[i]))
+				break
+			}
+		}
+	}
+}
@@ -0,0 +1,188 @@
+package proxyreenc
+
+import (
+	"math/bits"
+
+	"github.com/thedonutfactory/go-tfhe/csprng"
+	"github.com/thedonutfactory/go-tfhe/key"
+	"github.com/thedonutfactory/go-tfhe/params"
+	"github.com/thedonutfactory/go-tfhe/poly"
+	"github.com/thedonutfactory/go-tfhe/tlwe"
+	"github.com/thedonutfactory/go-tfhe/utils"
+)
+
+// PublicKey is implemented by the public keys of this package, so that
+// callers (bitutils.EncryptBitsPublic, integer.EncryptPublic, ...) can
+// encrypt with either of them.
+type PublicKey interface {
+	// EncryptTorusLv0 encrypts each torus value as a level 0 ciphertext
+	EncryptTorusLv0(values []params.Torus) []*tlwe.TLWELv0
+
+	// KeyParams returns the parameter set of the key
+	KeyParams() params.Parameters
+}
+
+// EncodeBool returns the gate encoding of a boolean (±1/8)
+func EncodeBool(b bool) params.Torus {
+	if b {
+		return utils.F64ToTorus(0.125)
+	}
+	return utils.F64ToTorus(-0.125)
+}
+
+// EncodeLWEMessage returns the torus encoding of tlwe.TLWELv0.EncryptLWEMessage:
+// message * 2^31 / messageModulus
+func EncodeLWEMessage(message int, messageModulus int) params.Torus {
+	scale := float64(uint64(1)<<31) / float64(messageModulus)
+	message %= messageModulus
+	if message < 0 {
+		message += messageModulus
+	}
+	return utils.F64ToTorus(float64(message) * scale / float64(uint64(1)<<32))
+}
+
+// CompactPublicKey is an RLWE public key for the level 0 secret key.
+//
+// The level 0 key s (dimension n) is padded with zeros to a polynomial of
+// degree N, the next power of two, and the public key is a single RLWE
+// encryption of zero (A, B = A*s + E) with the level 0 noise. That is 2N
+// torus values instead of the 2n*(n+1) of PublicKeyLv0. Every coefficient
+// of B is an LWE sample under s, so the key is as hard to break as the
+// level 0 ciphertexts themselves.
+//
+// One encryption packs up to N messages into a CompactCiphertextList, which
+// Expand turns into level 0 ciphertexts.
+type CompactPublicKey struct {
+	A      []params.Torus
+	B      []params.Torus
+	Params params.Parameters
+}
+
+// CompactCiphertextList holds messages encrypted with a CompactPublicKey,
+// packed N per RLWE ciphertext
+type CompactCiphertextList struct {
+	A      [][]params.Torus
+	B      [][]params.Torus
+	Len    int // Number of messages
+	Params params.Parameters
+}
+
+// NewCompactPublicKey generates a compact public key for sk's level 0 key
+func NewCompactPublicKey(sk *key.SecretKey) *CompactPublicKey {
+	rng := csprng.New()
+	n := compactDegree(sk.Params)
+	pk := &CompactPublicKey{
+		A:      make([]params.Torus, n),
+		B:      make([]params.Torus, n),
+		Params: sk.Params,
+	}
+	for i := range pk.A {
+		pk.A[i] = params.Torus(rng.Uint32())
+	}
+	s := make([]params.Torus, n)
+	copy(s, sk.KeyLv0)
+
+	as := poly.NewEvaluator(n).MulPoly(poly.Poly{Coeffs: pk.A}, poly.Poly{Coeffs: s})
+	for i := range pk.B {
+		pk.B[i] = as.Coeffs[i] + utils.GaussianF64(0, sk.Params.TLWELv0.ALPHA, rng)
+	}
+	return pk
+}
+
+// compactDegree returns the smallest power of two at least the level 0 dimension
+func compactDegree(p params.Parameters) int {
+	return max(1<<bits.Len(uint(p.TLWELv0.N-1)), poly.MinDegree)
+}
+
+// EncryptTorus encrypts the values, N to an RLWE ciphertext.
+//
+// Each ciphertext is (A*u + E1, B*u + E2 + M) for a fresh binary
+// polynomial u and Gaussian E1, E2, where M holds the values as
+// coefficients. Its phase is M + E*u + E2 - E1*s, so the noise has a
+// standard deviation of at most about sqrt((N+n)/2) times the level 0
+// noise, like PublicKeyLv0.
+func (pk *CompactPublicKey) EncryptTorus(values []params.Torus) *CompactCiphertextList {
+	rng := csprng.New()
+	n := len(pk.A)
+	alpha := pk.Params.TLWELv0.ALPHA
+	eval := poly.NewEvaluator(n)
+
+	list := &CompactCiphertextList{Len: len(values), Params: pk.Params}
+	for start := 0; start < len(values); start += n {
+		u := make([]params.Torus, n)
+		for i := range u {
+			u[i] = params.Torus(rng.Intn(2))
+		}
+		a := eval.MulPoly(poly.Poly{Coeffs: pk.A}, poly.Poly{Coeffs: u}).Coeffs
+		b := eval.MulPoly(poly.Poly{Coeffs: pk.B}, poly.Poly{Coeffs: u}).Coeffs
+		for i := 0; i < n; i++ {
+			a[i] += utils.GaussianF64(0, alpha, rng)
+			b[i] += utils.GaussianF64(0, alpha, rng)
+			if start+i < len(values) {
+				b[i] += values[start+i]
+			}
+		}
+		list.A = append(list.A, a)
+		list.B = append(list.B, b)
+	}
+	return list
+}
+
+// EncryptBools encrypts booleans in the gate encoding
+func (pk *CompactPublicKey) EncryptBools(bits []bool) *CompactCiphertextList {
+	values := make([]params.Torus, len(bits))
+	for i, b := range bits {
+		values[i] = EncodeBool(b)
+	}
+	return pk.EncryptTorus(values)
+}
+
+// EncryptLWEMessages encrypts integer messages with the encoding of
+// tlwe.TLWELv0.EncryptLWEMessage
+func (pk *CompactPublicKey) EncryptLWEMessages(messages []int, messageModulus int) *CompactCiphertextList {
+	values := make([]params.Torus, len(messages))
+	for i, m := range messages {
+		values[i] = EncodeLWEMessage(m, messageModulus)
+	}
+	return pk.EncryptTorus(values)
+}
+
+// EncryptTorusLv0 encrypts the values and expands them (see PublicKey)
+func (pk *CompactPublicKey) EncryptTorusLv0(values []params.Torus) []*tlwe.TLWELv0 {
+	return pk.EncryptTorus(values).Expand()
+}
+
+// KeyParams returns the parameter set of the public key
+func (pk *CompactPublicKey) KeyParams() params.Parameters {
+	return pk.Params
+}
+
+// Expand extracts every message as a level 0 ciphertext. No key is needed:
+// the coefficients of the extracted mask beyond n multiply the zero padding
+// of the secret key, so they are dropped.
+func (l *CompactCiphertextList) Expand() []*tlwe.TLWELv0 {
+	result := make([]*tlwe.TLWELv0, l.Len)
+	for i := range result {
+		a, b := l.A[i/len(l.A[0])], l.B[i/len(l.B[0])]
+		result[i] = extractLv0(a, b, i%len(a), l.Params)
+	}
+	return result
+}
+
+// extractLv0 extracts coefficient k of the RLWE ciphertext (a, b) as an
+// LWE ciphertext, keeping the first n mask coefficients. The noise of
+// E*u + E2 - E1*s (binary u and s) has about N/2 + n/2 + 1 Gaussian terms.
+func extractLv0(a, b []params.Torus, k int, p params.Parameters) *tlwe.TLWELv0 {
+	n := len(a)
+	result := tlwe.NewTLWELv0WithParams(p)
+	for j := 0; j < p.TLWELv0.N; j++ {
+		if j <= k {
+			result.P[j] = a[k-j]
+		} else {
+			result.P[j] = -a[n+k-j]
+		}
+	}
+	result.SetB(b[k])
+	result.Variance = (float64(n)/2 + float64(p.TLWELv0.N)/2 + 1) * p.FreshVariance()
+	return result
+}
@@ -39,8 +39,10 @@
 package proxyreenc
 
 import (
-	"math/rand"
+	"math"
 
+	"github.com/thedonutfactory/go-tfhe/csprng"
+	"github.com/thedonutfactory/go-tfhe/key"
 	"github.com/thedonutfactory/go-tfhe/params"
 	"github.com/thedonutfactory/go-tfhe/tlwe"
 	"github.com/thedonutfactory/go-tfhe/utils"
@@ -77,12 +79,24 @@ func NewPublicKeyLv0(secretKey []params.Torus) *PublicKeyLv0 {
 //   - size: Number of zero encryptions to generate (larger = more security)
 //   - alpha: Noise parameter for encryptions
 func NewPublicKeyLv0WithParams(secretKey []params.Torus, size int, alpha float64) *PublicKeyLv0 {
+	return newPublicKeyLv0(params.Current(), secretKey, size, alpha)
+}
+
+// NewPublicKeyLv0ForKey generates a public key for a secret key of any
+// parameter set, with 2n encryptions of zero at the key's level 0 noise.
+func NewPublicKeyLv0ForKey(sk *key.SecretKey) *PublicKeyLv0 {
+	return newPublicKeyLv0(sk.Params, sk.KeyLv0, sk.Params.TLWELv0.N*2, sk.Params.TLWELv0.ALPHA)
+}
+
+// newPublicKeyLv0 generates size encryptions of zero for parameter set p
+func newPublicKeyLv0(p params.Parameters, secretKey []params.Torus, size int, alpha float64) *PublicKeyLv0 {
 	encryptions := make([]*tlwe.TLWELv0, size)
 
 	// Generate encryptions of zero
+	src := csprng.Fork()
 	for i := 0; i < size; i++ {
-		ct := tlwe.NewTLWELv0()
-		ct.EncryptF64(0.0, alpha, secretKey)
+		ct := tlwe.NewTLWELv0WithParams(p)
+		ct.EncryptF64WithSource(0.0, alpha, secretKey, src)
 		encryptions[i] = ct
 	}
 
@@ -91,6 +105,11 @@ func NewPublicKeyLv0WithParams(secretKey []params.Torus, size int, alpha float64
 	}
 }
 
+// KeyParams returns the parameter set of the public key
+func (pk *PublicKeyLv0) KeyParams() params.Parameters {
+	return pk.Encryptions[0].Params
+}
+
 // EncryptF64 encrypts a value using the public key.
 //
 // This allows encryption without the secret key by combining
@@ -102,14 +121,29 @@ func NewPublicKeyLv0WithParams(secretKey []params.Torus, size int, alpha float64
 //
 // Returns a TLWELv0 ciphertext encrypting the plaintext.
 func (pk *PublicKeyLv0) EncryptF64(plaintext float64, alpha float64) *tlwe.TLWELv0 {
-	rng := rand.New(rand.NewSource(rand.Int63()))
-	result := tlwe.NewTLWELv0()
+	return pk.EncryptTorus(utils.F64ToTorus(plaintext), alpha)
+}
+
+// EncryptTorus encrypts a torus value using the public key.
+//
+// The noise of the result is the sum of the noise of about half of the
+// encryptions of zero plus fresh noise alpha, so it is roughly sqrt(n)
+// times that of a secret key encryption.
+func (pk *PublicKeyLv0) EncryptTorus(plaintext params.Torus, alpha float64) *tlwe.TLWELv0 {
+	return pk.EncryptTorusWithSource(plaintext, alpha, csprng.Fork())
+}
+
+// EncryptTorusWithSource encrypts a torus value using the public key,
+// drawing the random combination and noise from src
+func (pk *PublicKeyLv0) EncryptTorusWithSource(plaintext params.Torus, alpha float64, src csprng.Source) *tlwe.TLWELv0 {
+	rng := csprng.Rand(src)
+	zero := pk.Encryptions[0]
+	result := &tlwe.TLWELv0{P: make([]params.Torus, len(zero.P)), Params: zero.Params}
 
 	// Add the plaintext to b
-	plaintextTorus := utils.F64ToTorus(plaintext)
-	result.SetB(plaintextTorus)
+	result.SetB(plaintext)
 
-	n := params.GetTLWELv0().N
+	n := len(result.P) - 1
 
 	// Randomly combine encryptions of zero
 	for _, enc := range pk.Encryptions {
@@ -124,12 +158,14 @@ func (pk *PublicKeyLv0) EncryptF64(plaintext float64, alpha float64) *tlwe.TLWEL
 					result.P[i] = result.P[i] - enc.P[i]
 				}
 			}
+			result.Variance += enc.Variance
 		}
 	}
 
 	// Add fresh noise
 	noise := utils.GaussianF64(0.0, alpha, rng)
 	result.SetB(result.B() + noise)
+	result.Variance += alpha * alpha
 
 	return result
 }
@@ -151,6 +187,24 @@ func (pk *PublicKeyLv0) EncryptBool(plaintext bool, alpha float64) *tlwe.TLWELv0
 	return pk.EncryptF64(p, alpha)
 }
 
+// EncryptLWEMessage encrypts an integer message with the encoding of
+// tlwe.TLWELv0.EncryptLWEMessage (message * 2^31 / messageModulus), for
+// programmable bootstrapping.
+func (pk *PublicKeyLv0) EncryptLWEMessage(message int, messageModulus int, alpha float64) *tlwe.TLWELv0 {
+	return pk.EncryptTorus(EncodeLWEMessage(message, messageModulus), alpha)
+}
+
+// EncryptTorusLv0 encrypts every value with the key's level 0 noise (see PublicKey)
+func (pk *PublicKeyLv0) EncryptTorusLv0(values []params.Torus) []*tlwe.TLWELv0 {
+	alpha := pk.KeyParams().TLWELv0.ALPHA
+	src := csprng.Fork()
+	result := make([]*tlwe.TLWELv0, len(values))
+	for i, v := range values {
+		result[i] = pk.EncryptTorusWithSource(v, alpha, src)
+	}
+	return result
+}
+
 // ProxyReencryptionKey stores the reencryption key from one secret key to another.
 //
 // This key allows converting ciphertexts encrypted under keyFrom to
@@ -171,23 +225,26 @@ type ProxyReencryptionKey struct {
 //   - keyFrom: Alice's secret key (delegator)
 //   - publicKeyTo: Bob's public key (delegatee)
 //
-// Returns a proxy reencryption key from Alice to Bob.
+// Returns a proxy reencryption key from Alice to Bob. The noise and the
+// decomposition are those of the parameter set of Bob's public key.
 //
 // # Security
 //
 // This is the secure way to generate a reencryption key. Bob's secret key
 // is never exposed, only his public key is needed.
 func NewProxyReencryptionKeyAsymmetric(keyFrom []params.Torus, publicKeyTo *PublicKeyLv0) *ProxyReencryptionKey {
+	p := publicKeyTo.KeyParams()
 	return NewProxyReencryptionKeyAsymmetricWithParams(
 		keyFrom,
 		publicKeyTo,
-		params.KSKAlpha(),
-		params.GetTRGSWLv1().BASEBIT,
-		params.GetTRGSWLv1().IKS_T,
+		p.KSKAlpha(),
+		p.TRGSWLv1.BASEBIT,
+		p.TRGSWLv1.IKS_T,
 	)
 }
 
 // NewProxyReencryptionKeyAsymmetricWithParams generates a proxy reencryption key with custom parameters.
+// The key encrypts under the parameter set of publicKeyTo.
 func NewProxyReencryptionKeyAsymmetricWithParams(
 	keyFrom []params.Torus,
 	publicKeyTo *PublicKeyLv0,
@@ -196,16 +253,15 @@ func NewProxyReencryptionKeyAsymmetricWithParams(
 	t int,
 ) *ProxyReencryptionKey {
 	base := 1 << basebit
-	n := params.GetTLWELv0().N
+	n := len(keyFrom)
 
 	keyEncryptions := make([]*tlwe.TLWELv0, base*t*n)
-
-	// Initialize all to zero
 	for i := range keyEncryptions {
-		keyEncryptions[i] = tlwe.NewTLWELv0()
+		keyEncryptions[i] = tlwe.NewTLWELv0WithParams(publicKeyTo.KeyParams())
 	}
 
 	// Generate decomposed encryptions using the PUBLIC key
+	src := csprng.Fork()
 	for i := 0; i < n; i++ {
 		for j := 0; j < t; j++ {
 			for k := 0; k < base; k++ {
@@ -219,7 +275,7 @@ func NewProxyReencryptionKeyAsymmetricWithParams(
 				idx := (base * t * i) + (base * j) + k
 
 				// Use public key encryption instead of secret key
-				keyEncryptions[idx] = publicKeyTo.EncryptF64(p, alpha)
+				keyEncryptions[idx] = publicKeyTo.EncryptTorusWithSource(utils.F64ToTorus(p), alpha, src)
 			}
 		}
 	}
@@ -246,35 +302,56 @@ func NewProxyReencryptionKeyAsymmetricWithParams(
 //
 // This mode requires access to both secret keys. For true delegation where
 // Bob doesn't share his secret key, use NewProxyReencryptionKeyAsymmetric instead.
+//
+// The raw keys do not record their parameter set, so the current one is used;
+// NewProxyReencryptionKeySymmetricForKeys takes it from the keys.
 func NewProxyReencryptionKeySymmetric(keyFrom []params.Torus, keyTo []params.Torus) *ProxyReencryptionKey {
-	return NewProxyReencryptionKeySymmetricWithParams(
-		keyFrom,
-		keyTo,
-		params.KSKAlpha(),
-		params.GetTRGSWLv1().BASEBIT,
-		params.GetTRGSWLv1().IKS_T,
-	)
+	p := params.Current()
+	return newProxyReencryptionKeySymmetric(p, keyFrom, keyTo, p.KSKAlpha(), p.TRGSWLv1.BASEBIT, p.TRGSWLv1.IKS_T)
+}
+
+// NewProxyReencryptionKeySymmetricForKeys generates a symmetric mode key
+// between secret keys of any parameter set, with the noise and the
+// decomposition of the target key's parameter set
+func NewProxyReencryptionKeySymmetricForKeys(keyFrom, keyTo *key.SecretKey) *ProxyReencryptionKey {
+	p := keyTo.Params
+	return newProxyReencryptionKeySymmetric(p, keyFrom.KeyLv0, keyTo.KeyLv0, p.KSKAlpha(), p.TRGSWLv1.BASEBIT, p.TRGSWLv1.IKS_T)
 }
 
 // NewProxyReencryptionKeySymmetricWithParams generates a symmetric mode key with custom parameters.
+// The key encrypts under the current parameter set.
 func NewProxyReencryptionKeySymmetricWithParams(
 	keyFrom []params.Torus,
 	keyTo []params.Torus,
 	alpha float64,
 	basebit int,
 	t int,
+) *ProxyReencryptionKey {
+	return newProxyReencryptionKeySymmetric(params.Current(), keyFrom, keyTo, alpha, basebit, t)
+}
+
+// newProxyReencryptionKeySymmetric generates a symmetric mode key encrypting
+// under keyTo of parameter set p
+func newProxyReencryptionKeySymmetric(
+	p params.Parameters,
+	keyFrom []params.Torus,
+	keyTo []params.Torus,
+	alpha float64,
+	basebit int,
+	t int,
 ) *ProxyReencryptionKey {
 	base := 1 << basebit
-	n := params.GetTLWELv0().N
+	n := len(keyFrom)
 
 	keyEncryptions := make([]*tlwe.TLWELv0, base*t*n)
 
 	// Initialize all to zero
 	for i := range keyEncryptions {
-		keyEncryptions[i] = tlwe.NewTLWELv0()
+		keyEncryptions[i] = tlwe.NewTLWELv0WithParams(p)
 	}
 
 	// Generate decomposed encryptions similar to key switching key
+	src := csprng.Fork()
 	for i := 0; i < n; i++ {
 		for j := 0; j < t; j++ {
 			for k := 0; k < base; k++ {
@@ -287,7 +364,7 @@ func NewProxyReencryptionKeySymmetricWithParams(
 				p := (float64(k) * float64(keyFrom[i])) / float64(uint32(1)<<shiftAmount)
 				idx := (base * t * i) + (base * j) + k
 
-				keyEncryptions[idx].EncryptF64(p, alpha, keyTo)
+				keyEncryptions[idx].EncryptF64WithSource(p, alpha, keyTo, src)
 			}
 		}
 	}
@@ -319,7 +396,7 @@ func NewProxyReencryptionKeySymmetricWithParams(
 //   - Subtract the corresponding pre-computed encrypted values
 // 3. Result is an encryption of the same message under the target key
 func ReencryptTLWELv0(ctFrom *tlwe.TLWELv0, reencKey *ProxyReencryptionKey) *tlwe.TLWELv0 {
-	n := params.GetTLWELv0().N
+	n := len(ctFrom.P) - 1
 
 	// Calculate basebit from base
 	basebit := 0
@@ -330,11 +407,17 @@ func ReencryptTLWELv0(ctFrom *tlwe.TLWELv0, reencKey *ProxyReencryptionKey) *tlw
 	base := reencKey.Base
 	t := reencKey.T
 
-	result := tlwe.NewTLWELv0()
+	target := reencKey.KeyEncryptions[0]
+	result := &tlwe.TLWELv0{P: make([]params.Torus, len(target.P)), Params: target.Params}
 
 	// Start with the b value from the source ciphertext
 	result.SetB(ctFrom.B())
 
+	// Noise: the source noise, the subtracted key encryptions and the
+	// rounding of each coefficient to basebit*t bits (binary key)
+	precision := math.Ldexp(1, -basebit*t)
+	result.Variance = ctFrom.Variance + float64(n)/2*precision*precision/12
+
 	// Precision offset for rounding (similar to identity key switching)
 	precOffset := params.Torus(1) << (32 - (1 + basebit*t))
 
@@ -358,6 +441,7 @@ func ReencryptTLWELv0(ctFrom *tlwe.TLWELv0, reencKey *ProxyReencryptionKey) *tlw
 				for x := 0; x <= n; x++ {
 					result.P[x] = result.P[x] - reencKey.KeyEncryptions[idx].P[x]
 				}
+				result.Variance += reencKey.KeyEncryptions[idx].Variance
 			}
 		}
 	}
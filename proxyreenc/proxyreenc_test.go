@@ -45,6 +45,51 @@ func TestPublicKeyEncryptionMultiple(t *testing.T) {
 	}
 }
 
+func TestPublicKeyEncryptLWEMessage(t *testing.T) {
+	secretKey := key.NewSecretKeyWithParams(params.GetParameters(params.SecurityUint4))
+	publicKey := NewPublicKeyLv0ForKey(secretKey)
+	if publicKey.KeyParams() != secretKey.Params {
+		t.Fatalf("public key params = %v, want %v", publicKey.KeyParams().Level, secretKey.Params.Level)
+	}
+
+	for message := 0; message < 16; message++ {
+		ct := publicKey.EncryptLWEMessage(message, 16, secretKey.Params.TLWELv0.ALPHA)
+		if got := ct.DecryptLWEMessage(16, secretKey.KeyLv0); got != message {
+			t.Errorf("EncryptLWEMessage(%d) decrypted to %d", message, got)
+		}
+	}
+}
+
+func TestCompactPublicKey(t *testing.T) {
+	secretKey := key.NewSecretKey()
+	publicKey := NewCompactPublicKey(secretKey)
+	if len(publicKey.A) != 1024 {
+		t.Errorf("compact key degree = %d, want 1024 for n = %d", len(publicKey.A), secretKey.Params.TLWELv0.N)
+	}
+
+	// More bits than one RLWE ciphertext holds
+	bits := make([]bool, 1500)
+	for i := range bits {
+		bits[i] = (i*7)%3 == 0
+	}
+	list := publicKey.EncryptBools(bits)
+	if len(list.A) != 2 {
+		t.Errorf("%d bits were packed into %d ciphertexts, want 2", len(bits), len(list.A))
+	}
+	for i, ct := range list.Expand() {
+		if got := ct.DecryptBool(secretKey.KeyLv0); got != bits[i] {
+			t.Errorf("bit %d decrypted to %v, want %v", i, got, bits[i])
+		}
+	}
+
+	messages := []int{0, 3, 5, 7, 1}
+	for i, ct := range publicKey.EncryptLWEMessages(messages, 8).Expand() {
+		if got := ct.DecryptLWEMessage(8, secretKey.KeyLv0); got != messages[i] {
+			t.Errorf("message %d decrypted to %d, want %d", i, got, messages[i])
+		}
+	}
+}
+
 func TestProxyReencryptionAsymmetric(t *testing.T) {
 	aliceKey := key.NewSecretKey()
 	bobKey := key.NewSecretKey()
@@ -189,6 +234,35 @@ func TestProxyReencryptionKeyGeneration(t *testing.T) {
 	}
 }
 
+func TestProxyReencryptionWithParams(t *testing.T) {
+	// A parameter set other than the current one, with a different dimension
+	p := params.GetParameters(params.Security80Bit)
+	if p.TLWELv0.N == params.GetTLWELv0().N {
+		t.Fatal("80-bit parameters are expected to differ from the current ones")
+	}
+	aliceKey := key.NewSecretKeyWithParams(p)
+	bobKey := key.NewSecretKeyWithParams(p)
+
+	for _, tc := range []struct {
+		name     string
+		reencKey *ProxyReencryptionKey
+	}{
+		{"symmetric", NewProxyReencryptionKeySymmetricForKeys(aliceKey, bobKey)},
+		{"asymmetric", NewProxyReencryptionKeyAsymmetric(aliceKey.KeyLv0, NewPublicKeyLv0ForKey(bobKey))},
+	} {
+		for _, message := range []bool{true, false} {
+			aliceCt := tlwe.NewTLWELv0WithParams(p).EncryptBool(message, p.TLWELv0.ALPHA, aliceKey.KeyLv0)
+			bobCt := ReencryptTLWELv0(aliceCt, tc.reencKey)
+			if bobCt.Params != p || len(bobCt.P) != p.TLWELv0.N+1 {
+				t.Fatalf("%s: reencrypted to level %d with %d coefficients, want 80-bit with %d", tc.name, bobCt.Params.Level, len(bobCt.P), p.TLWELv0.N+1)
+			}
+			if got := bobCt.DecryptBool(bobKey.KeyLv0); got != message {
+				t.Errorf("%s proxy reencryption: got %v, want %v", tc.name, got, message)
+			}
+		}
+	}
+}
+
 // Benchmark asymmetric key generation
 func BenchmarkAsymmetricKeyGeneration(b *testing.B) {
 	aliceKey := key.NewSecretKey()
@@ -1,6 +1,7 @@
 package tlwe
 
 import (
+	"github.com/thedonutfactory/go-tfhe/csprng"
 	"github.com/thedonutfactory/go-tfhe/params"
 )
 
@@ -10,6 +11,12 @@ import (
 // For programmable bootstrapping, use this function to match the LUT encoding.
 // Encoding: message → message * scale, where scale = 2^31 / messageModulus
 func (t *TLWELv0) EncryptLWEMessage(message int, messageModulus int, alpha float64, key []params.Torus) *TLWELv0 {
+	return t.EncryptLWEMessageWithSource(message, messageModulus, alpha, key, csprng.Fork())
+}
+
+// EncryptLWEMessageWithSource encrypts an integer message like
+// EncryptLWEMessage, drawing the mask and noise from src
+func (t *TLWELv0) EncryptLWEMessageWithSource(message int, messageModulus int, alpha float64, key []params.Torus, src csprng.Source) *TLWELv0 {
 	// Calculate scale: 2^31 / messageModulus
 	scale := float64(uint64(1)<<31) / float64(messageModulus)
 
@@ -22,7 +29,7 @@ func (t *TLWELv0) EncryptLWEMessage(message int, messageModulus int, alpha float
 	// Encode: message * scale / 2^32 to get value in [0, 1)
 	encodedMessage := float64(message) * scale / float64(uint64(1)<<32)
 
-	return t.EncryptF64(encodedMessage, alpha, key)
+	return t.EncryptF64WithSource(encodedMessage, alpha, key, src)
 }
 
 // DecryptLWEMessage decrypts an integer message using general message encoding
@@ -34,7 +41,7 @@ func (t *TLWELv0) DecryptLWEMessage(messageModulus int, key []params.Torus) int
 	scale := params.Torus(uint64(1)<<31) / params.Torus(messageModulus)
 
 	// Get phase (decrypted value with noise)
-	n := params.GetTLWELv0().N
+	n := len(t.P) - 1
 	var innerProduct params.Torus
 	for i := 0; i < n; i++ {
 		innerProduct += t.P[i] * key[i]
@@ -0,0 +1,118 @@
+package tlwe
+
+import (
+	"bytes"
+	"io"
+
+	"github.com/thedonutfactory/go-tfhe/params"
+	"github.com/thedonutfactory/go-tfhe/utils"
+)
+
+// WriteTo writes the ciphertext in binary form to w.
+// The output starts with a params.Header recording the parameter set.
+func (t *TLWELv0) WriteTo(w io.Writer) (int64, error) {
+	if t.Params == (params.Parameters{}) {
+		return 0, params.ErrNoParams
+	}
+	n, err := params.NewHeader(params.ObjectTLWELv0, t.Params).WriteTo(w)
+	if err != nil {
+		return n, err
+	}
+	m, err := utils.WriteTorusVec(w, t.P)
+	return n + m, err
+}
+
+// ReadFrom reads a ciphertext written by WriteTo from r.
+// If t already belongs to a parameter set (for example it was created with a
+// WithParams constructor), the blob must use the same set; otherwise t adopts
+// the parameter set recorded in the header.
+func (t *TLWELv0) ReadFrom(r io.Reader) (int64, error) {
+	var h params.Header
+	n, err := h.ReadFrom(r)
+	if err != nil {
+		return n, err
+	}
+	if err := h.Check(params.ObjectTLWELv0); err != nil {
+		return n, err
+	}
+	if t.Params != (params.Parameters{}) {
+		if err := h.CheckParams(t.Params); err != nil {
+			return n, err
+		}
+	}
+	t.Params = h.Params
+	t.P = make([]params.Torus, h.Params.TLWELv0.N+1)
+	t.Variance = 0
+	m, err := utils.ReadTorusVec(r, t.P)
+	return n + m, err
+}
+
+// MarshalBinary implements encoding.BinaryMarshaler
+func (t *TLWELv0) MarshalBinary() ([]byte, error) {
+	var buf bytes.Buffer
+	buf.Grow(params.HeaderSize + len(t.P)*4)
+	if _, err := t.WriteTo(&buf); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// UnmarshalBinary implements encoding.BinaryUnmarshaler
+func (t *TLWELv0) UnmarshalBinary(data []byte) error {
+	_, err := t.ReadFrom(bytes.NewReader(data))
+	return err
+}
+
+// WriteTo writes the ciphertext in binary form to w.
+// The output starts with a params.Header recording the parameter set.
+func (t *TLWELv1) WriteTo(w io.Writer) (int64, error) {
+	if t.Params == (params.Parameters{}) {
+		return 0, params.ErrNoParams
+	}
+	n, err := params.NewHeader(params.ObjectTLWELv1, t.Params).WriteTo(w)
+	if err != nil {
+		return n, err
+	}
+	m, err := utils.WriteTorusVec(w, t.P)
+	return n + m, err
+}
+
+// ReadFrom reads a ciphertext written by WriteTo from r.
+// If t already belongs to a parameter set (for example it was created with a
+// WithParams constructor), the blob must use the same set; otherwise t adopts
+// the parameter set recorded in the header.
+func (t *TLWELv1) ReadFrom(r io.Reader) (int64, error) {
+	var h params.Header
+	n, err := h.ReadFrom(r)
+	if err != nil {
+		return n, err
+	}
+	if err := h.Check(params.ObjectTLWELv1); err != nil {
+		return n, err
+	}
+	if t.Params != (params.Parameters{}) {
+		if err := h.CheckParams(t.Params); err != nil {
+			return n, err
+		}
+	}
+	t.Params = h.Params
+	t.P = make([]params.Torus, h.Params.TLWELv1.N+1)
+	m, err := utils.ReadTorusVec(r, t.P)
+	return n + m, err
+}
+
+// MarshalBinary implements encoding.BinaryMarshaler
+func (t *TLWELv1) MarshalBinary() ([]byte, error) {
+	var buf bytes.Buffer
+	buf.Grow(params.HeaderSize + len(t.P)*4)
+	if _, err := t.WriteTo(&buf); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// UnmarshalBinary implements encoding.BinaryUnmarshaler
+func (t *TLWELv1) UnmarshalBinary(data []byte) error {
+	_, err := t.ReadFrom(bytes.NewReader(data))
+	return err
+}
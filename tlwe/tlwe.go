@@ -1,41 +1,86 @@
 package tlwe
 
 import (
-	"math/rand"
-
+	"github.com/thedonutfactory/go-tfhe/csprng"
 	"github.com/thedonutfactory/go-tfhe/params"
 	"github.com/thedonutfactory/go-tfhe/utils"
 )
 
 // TLWELv0 represents a Level 0 TLWE ciphertext
 type TLWELv0 struct {
-	P []params.Torus // Length is N+1, where last element is b
+	P      []params.Torus    // Length is N+1, where last element is b
+	Params params.Parameters // Parameter set the ciphertext belongs to
+
+	// Variance is an estimate of the noise variance of the phase, with the
+	// torus taken as [0, 1). Encryption sets it, the linear operations below
+	// propagate it and bootstrapping resets it (see evaluator.CheckNoise).
+	// Zero means noiseless (a trivial ciphertext) or not tracked (a
+	// ciphertext built by hand or read with ReadFrom). An untracked operand
+	// counts as noiseless, so a result computed from one under-reports its
+	// noise until it is bootstrapped.
+	Variance float64
 }
 
-// NewTLWELv0 creates a new TLWE Level 0 ciphertext
+// NewTLWELv0 creates a new TLWE Level 0 ciphertext for the current security level
 func NewTLWELv0() *TLWELv0 {
-	n := params.GetTLWELv0().N
+	return NewTLWELv0WithParams(params.Current())
+}
+
+// NewTLWELv0WithParams creates a new TLWE Level 0 ciphertext for parameter set p
+func NewTLWELv0WithParams(p params.Parameters) *TLWELv0 {
 	return &TLWELv0{
-		P: make([]params.Torus, n+1),
+		P:      make([]params.Torus, p.TLWELv0.N+1),
+		Params: p,
 	}
 }
 
+// newLike allocates an empty ciphertext with the same shape and parameters as t
+func (t *TLWELv0) newLike() *TLWELv0 {
+	return &TLWELv0{
+		P:      make([]params.Torus, len(t.P)),
+		Params: t.Params,
+	}
+}
+
+// CopyFrom copies the coefficients and the noise estimate of other into t
+func (t *TLWELv0) CopyFrom(other *TLWELv0) {
+	copy(t.P, other.P)
+	t.Variance = other.Variance
+}
+
 // B returns the b component of the TLWE ciphertext
 func (t *TLWELv0) B() params.Torus {
-	n := params.GetTLWELv0().N
-	return t.P[n]
+	return t.P[len(t.P)-1]
 }
 
 // SetB sets the b component of the TLWE ciphertext
 func (t *TLWELv0) SetB(val params.Torus) {
-	n := params.GetTLWELv0().N
-	t.P[n] = val
+	t.P[len(t.P)-1] = val
+}
+
+// IsTrivial reports whether t is a trivial (noiseless) ciphertext: its mask
+// is zero, so b holds the plaintext in the clear. Trivial ciphertexts carry
+// public values alongside encrypted ones; a real encryption has a zero mask
+// only with negligible probability.
+func (t *TLWELv0) IsTrivial() bool {
+	for _, a := range t.P[:len(t.P)-1] {
+		if a != 0 {
+			return false
+		}
+	}
+	return true
 }
 
 // EncryptF64 encrypts a float64 value with TLWE Level 0
 func (t *TLWELv0) EncryptF64(p float64, alpha float64, key []params.Torus) *TLWELv0 {
-	rng := rand.New(rand.NewSource(rand.Int63()))
-	n := params.GetTLWELv0().N
+	return t.EncryptF64WithSource(p, alpha, key, csprng.Fork())
+}
+
+// EncryptF64WithSource encrypts a float64 value with TLWE Level 0 drawing the
+// mask and noise from src
+func (t *TLWELv0) EncryptF64WithSource(p float64, alpha float64, key []params.Torus, src csprng.Source) *TLWELv0 {
+	rng := csprng.Rand(src)
+	n := len(t.P) - 1
 
 	var innerProduct params.Torus
 	for i := 0; i < n; i++ {
@@ -46,23 +91,30 @@ func (t *TLWELv0) EncryptF64(p float64, alpha float64, key []params.Torus) *TLWE
 
 	b := utils.GaussianF64(p, alpha, rng)
 	t.SetB(innerProduct + b)
+	t.Variance = alpha * alpha
 	return t
 }
 
 // EncryptBool encrypts a boolean value with TLWE Level 0
 func (t *TLWELv0) EncryptBool(pBool bool, alpha float64, key []params.Torus) *TLWELv0 {
+	return t.EncryptBoolWithSource(pBool, alpha, key, csprng.Fork())
+}
+
+// EncryptBoolWithSource encrypts a boolean value with TLWE Level 0 drawing
+// the mask and noise from src
+func (t *TLWELv0) EncryptBoolWithSource(pBool bool, alpha float64, key []params.Torus, src csprng.Source) *TLWELv0 {
 	var p float64
 	if pBool {
 		p = 0.125
 	} else {
 		p = -0.125
 	}
-	return t.EncryptF64(p, alpha, key)
+	return t.EncryptF64WithSource(p, alpha, key, src)
 }
 
 // DecryptBool decrypts a TLWE Level 0 ciphertext to a boolean
 func (t *TLWELv0) DecryptBool(key []params.Torus) bool {
-	n := params.GetTLWELv0().N
+	n := len(t.P) - 1
 	var innerProduct params.Torus
 	for i := 0; i < n; i++ {
 		innerProduct += t.P[i] * key[i]
@@ -74,10 +126,11 @@ func (t *TLWELv0) DecryptBool(key []params.Torus) bool {
 
 // Add adds two TLWE Level 0 ciphertexts
 func (t *TLWELv0) Add(other *TLWELv0) *TLWELv0 {
-	result := NewTLWELv0()
+	result := t.newLike()
 	for i := range result.P {
 		result.P[i] = t.P[i] + other.P[i]
 	}
+	result.Variance = t.Variance + other.Variance
 	return result
 }
 
@@ -86,29 +139,35 @@ func (t *TLWELv0) AddAssign(other *TLWELv0, output *TLWELv0) {
 	for i := range output.P {
 		output.P[i] = t.P[i] + other.P[i]
 	}
+	output.Variance = t.Variance + other.Variance
 }
 
 // Sub subtracts two TLWE Level 0 ciphertexts
 func (t *TLWELv0) Sub(other *TLWELv0) *TLWELv0 {
-	result := NewTLWELv0()
+	result := t.newLike()
 	for i := range result.P {
 		result.P[i] = t.P[i] - other.P[i]
 	}
+	result.Variance = t.Variance + other.Variance
 	return result
 }
 
 // Neg negates a TLWE Level 0 ciphertext
 func (t *TLWELv0) Neg() *TLWELv0 {
-	result := NewTLWELv0()
+	result := t.newLike()
 	for i := range result.P {
 		result.P[i] = 0 - t.P[i]
 	}
+	result.Variance = t.Variance
 	return result
 }
 
-// Mul multiplies two TLWE Level 0 ciphertexts (element-wise)
+// Mul multiplies two TLWE Level 0 ciphertexts (element-wise).
+// This does not multiply the encrypted messages; for encrypted multiplication
+// see the integer package, which uses programmable bootstrapping. The noise
+// of the result is not tracked.
 func (t *TLWELv0) Mul(other *TLWELv0) *TLWELv0 {
-	result := NewTLWELv0()
+	result := t.newLike()
 	for i := range result.P {
 		result.P[i] = t.P[i] * other.P[i]
 	}
@@ -117,45 +176,65 @@ func (t *TLWELv0) Mul(other *TLWELv0) *TLWELv0 {
 
 // AddMul adds a TLWE ciphertext multiplied by a constant
 func (t *TLWELv0) AddMul(other *TLWELv0, multiplier params.Torus) *TLWELv0 {
-	result := NewTLWELv0()
+	result := t.newLike()
 	for i := range result.P {
 		result.P[i] = t.P[i] + (other.P[i] * multiplier)
 	}
+	result.Variance = t.Variance + ScaledVariance(other.Variance, multiplier)
 	return result
 }
 
 // SubMul subtracts a TLWE ciphertext multiplied by a constant
 func (t *TLWELv0) SubMul(other *TLWELv0, multiplier params.Torus) *TLWELv0 {
-	result := NewTLWELv0()
+	result := t.newLike()
 	for i := range result.P {
 		result.P[i] = t.P[i] - (other.P[i] * multiplier)
 	}
+	result.Variance = t.Variance + ScaledVariance(other.Variance, multiplier)
 	return result
 }
 
+// ScaledVariance returns the noise variance of a ciphertext with the given
+// variance multiplied by the integer c (read as a signed 32-bit value)
+func ScaledVariance(variance float64, c params.Torus) float64 {
+	m := float64(int32(c))
+	return m * m * variance
+}
+
 // TLWELv1 represents a Level 1 TLWE ciphertext
 type TLWELv1 struct {
-	P []params.Torus // Length is N+1, where last element is b
+	P      []params.Torus    // Length is N+1, where last element is b
+	Params params.Parameters // Parameter set the ciphertext belongs to
 }
 
-// NewTLWELv1 creates a new TLWE Level 1 ciphertext
+// NewTLWELv1 creates a new TLWE Level 1 ciphertext for the current security level
 func NewTLWELv1() *TLWELv1 {
-	n := params.GetTLWELv1().N
+	return NewTLWELv1WithParams(params.Current())
+}
+
+// NewTLWELv1WithParams creates a new TLWE Level 1 ciphertext for parameter set p
+func NewTLWELv1WithParams(p params.Parameters) *TLWELv1 {
 	return &TLWELv1{
-		P: make([]params.Torus, n+1),
+		P:      make([]params.Torus, p.TLWELv1.N+1),
+		Params: p,
 	}
 }
 
 // SetB sets the b component of the TLWE Level 1 ciphertext
 func (t *TLWELv1) SetB(val params.Torus) {
-	n := params.GetTLWELv1().N
-	t.P[n] = val
+	t.P[len(t.P)-1] = val
 }
 
 // EncryptF64 encrypts a float64 value with TLWE Level 1
 func (t *TLWELv1) EncryptF64(p float64, alpha float64, key []params.Torus) *TLWELv1 {
-	rng := rand.New(rand.NewSource(rand.Int63()))
-	n := params.GetTLWELv1().N
+	return t.EncryptF64WithSource(p, alpha, key, csprng.Fork())
+}
+
+// EncryptF64WithSource encrypts a float64 value with TLWE Level 1 drawing the
+// mask and noise from src
+func (t *TLWELv1) EncryptF64WithSource(p float64, alpha float64, key []params.Torus, src csprng.Source) *TLWELv1 {
+	rng := csprng.Rand(src)
+	n := len(t.P) - 1
 
 	var innerProduct params.Torus
 	for i := 0; i < n; i++ {
@@ -171,23 +250,29 @@ func (t *TLWELv1) EncryptF64(p float64, alpha float64, key []params.Torus) *TLWE
 
 // EncryptBool encrypts a boolean value with TLWE Level 1
 func (t *TLWELv1) EncryptBool(pBool bool, alpha float64, key []params.Torus) *TLWELv1 {
+	return t.EncryptBoolWithSource(pBool, alpha, key, csprng.Fork())
+}
+
+// EncryptBoolWithSource encrypts a boolean value with TLWE Level 1 drawing
+// the mask and noise from src
+func (t *TLWELv1) EncryptBoolWithSource(pBool bool, alpha float64, key []params.Torus, src csprng.Source) *TLWELv1 {
 	var p float64
 	if pBool {
 		p = 0.125
 	} else {
 		p = -0.125
 	}
-	return t.EncryptF64(p, alpha, key)
+	return t.EncryptF64WithSource(p, alpha, key, src)
 }
 
 // DecryptBool decrypts a TLWE Level 1 ciphertext to a boolean
 func (t *TLWELv1) DecryptBool(key []params.Torus) bool {
-	n := params.GetTLWELv1().N
+	n := len(t.P) - 1
 	var innerProduct params.Torus
 	for i := 0; i < n; i++ {
 		innerProduct += t.P[i] * key[i]
 	}
 
-	resTorus := int32(t.P[len(key)] - innerProduct)
+	resTorus := int32(t.P[n] - innerProduct)
 	return resTorus >= 0
 }
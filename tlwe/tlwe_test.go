@@ -1,6 +1,8 @@
 package tlwe_test
 
 import (
+	"encoding/binary"
+	"errors"
 	"testing"
 
 	"github.com/thedonutfactory/go-tfhe/key"
@@ -83,3 +85,112 @@ func TestTLWELv1EncryptDecrypt(t *testing.T) {
 		}
 	}
 }
+
+func TestTLWELv0MarshalBinary(t *testing.T) {
+	sk := key.NewSecretKey()
+
+	for _, val := range []bool{true, false} {
+		ct := tlwe.NewTLWELv0().EncryptBool(val, params.GetTLWELv0().ALPHA, sk.KeyLv0)
+
+		data, err := ct.MarshalBinary()
+		if err != nil {
+			t.Fatalf("MarshalBinary failed: %v", err)
+		}
+
+		var restored tlwe.TLWELv0
+		if err := restored.UnmarshalBinary(data); err != nil {
+			t.Fatalf("UnmarshalBinary failed: %v", err)
+		}
+
+		if dec := restored.DecryptBool(sk.KeyLv0); dec != val {
+			t.Errorf("Marshal/Unmarshal(%v) decrypted to %v", val, dec)
+		}
+	}
+}
+
+func TestTLWELv0UnmarshalParamsMismatch(t *testing.T) {
+	data, err := tlwe.NewTLWELv0WithParams(params.GetParameters(params.Security80Bit)).MarshalBinary()
+	if err != nil {
+		t.Fatalf("MarshalBinary failed: %v", err)
+	}
+
+	restored := tlwe.NewTLWELv0WithParams(params.GetParameters(params.Security128Bit))
+	if err := restored.UnmarshalBinary(data); !errors.Is(err, params.ErrParamsMismatch) {
+		t.Errorf("UnmarshalBinary into different params: got %v, want ErrParamsMismatch", err)
+	}
+
+	var adopted tlwe.TLWELv0
+	if err := adopted.UnmarshalBinary(data); err != nil {
+		t.Fatalf("UnmarshalBinary into empty ciphertext failed: %v", err)
+	}
+	if adopted.Params.Level != params.Security80Bit || len(adopted.P) != 551 {
+		t.Errorf("UnmarshalBinary adopted level %d with %d coefficients, want 80-bit with 551", adopted.Params.Level, len(adopted.P))
+	}
+
+	if err := restored.UnmarshalBinary(data[:10]); !errors.Is(err, params.ErrInvalidHeader) {
+		t.Errorf("UnmarshalBinary of truncated data: got %v, want ErrInvalidHeader", err)
+	}
+}
+
+func TestTLWELv0UnmarshalForgedHeader(t *testing.T) {
+	data, err := tlwe.NewTLWELv0WithParams(params.GetParameters(params.Security80Bit)).MarshalBinary()
+	if err != nil {
+		t.Fatalf("MarshalBinary failed: %v", err)
+	}
+
+	// Offsets into the header: version at 4, level at 8, level 0 dimension at 12
+	testCases := []struct {
+		name   string
+		offset int
+		value  uint32
+		want   error
+	}{
+		{"old version", 4, 1, params.ErrInvalidHeader},
+		{"unknown level", 8, 5000, params.ErrInvalidHeader},
+		{"huge dimension", 12, 1 << 30, params.ErrParamsMismatch},
+		{"zero dimension", 12, 0, params.ErrInvalidHeader},
+	}
+	for _, tc := range testCases {
+		forged := append([]byte(nil), data...)
+		if tc.offset == 4 {
+			binary.LittleEndian.PutUint16(forged[tc.offset:], uint16(tc.value))
+		} else {
+			binary.LittleEndian.PutUint32(forged[tc.offset:], tc.value)
+		}
+		var restored tlwe.TLWELv0
+		if err := restored.UnmarshalBinary(forged); !errors.Is(err, tc.want) {
+			t.Errorf("%s: UnmarshalBinary got %v, want %v", tc.name, err, tc.want)
+		}
+	}
+}
+
+func TestTLWELv0Variance(t *testing.T) {
+	sk := key.NewSecretKey()
+	alpha := params.GetTLWELv0().ALPHA
+	fresh := alpha * alpha
+
+	a := tlwe.NewTLWELv0().EncryptBool(true, alpha, sk.KeyLv0)
+	b := tlwe.NewTLWELv0().EncryptBool(false, alpha, sk.KeyLv0)
+	sum := tlwe.NewTLWELv0()
+	a.AddAssign(b, sum)
+
+	testCases := []struct {
+		name string
+		got  float64
+		want float64
+	}{
+		{"EncryptBool", a.Variance, fresh},
+		{"Add", a.Add(b).Variance, 2 * fresh},
+		{"AddAssign", sum.Variance, 2 * fresh},
+		{"Sub", a.Sub(b).Variance, 2 * fresh},
+		{"Neg", a.Neg().Variance, fresh},
+		{"AddMul", a.AddMul(b, 2).Variance, 5 * fresh},
+		{"SubMul negative", a.SubMul(b, params.Torus(0xFFFFFFFD)).Variance, 10 * fresh},
+		{"trivial", tlwe.NewTLWELv0().Variance, 0},
+	}
+	for _, tc := range testCases {
+		if tc.got != tc.want {
+			t.Errorf("%s: variance %g, want %g", tc.name, tc.got, tc.want)
+		}
+	}
+}
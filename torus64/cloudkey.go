@@ -0,0 +1,60 @@
+package torus64
+
+import (
+	"sync"
+
+	"github.com/thedonutfactory/go-tfhe/csprng"
+	"github.com/thedonutfactory/go-tfhe/params"
+	"github.com/thedonutfactory/go-tfhe/poly"
+)
+
+// CloudKey contains the public evaluation keys on the 64-bit torus
+type CloudKey struct {
+	DecompositionOffset params.Torus64
+	KeySwitchingKey     []*TLWELv0
+	BootstrappingKey    []*TRGSWLv1FFT
+	Params              params.Parameters // Parameter set the key was generated for
+}
+
+// NewCloudKey generates a new cloud key from a secret key.
+// The cloud key uses the secret key's parameter set. It panics if the
+// parameter set fails params.Validate64: bootstrapping would silently give
+// wrong results with it.
+func NewCloudKey(secretKey *SecretKey) *CloudKey {
+	p := secretKey.Params
+	if err := p.Validate64(); err != nil {
+		panic(err)
+	}
+	return &CloudKey{
+		DecompositionOffset: DecompositionOffset(p),
+		KeySwitchingKey:     genKeySwitchingKey(p, secretKey),
+		BootstrappingKey:    genBootstrappingKey(p, secretKey),
+		Params:              p,
+	}
+}
+
+// genBootstrappingKey generates the bootstrapping key (parallelized)
+func genBootstrappingKey(p params.Parameters, secretKey *SecretKey) []*TRGSWLv1FFT {
+	lv0N := p.TLWELv0.N
+	result := make([]*TRGSWLv1FFT, lv0N)
+
+	var wg sync.WaitGroup
+	for i := 0; i < lv0N; i++ {
+		wg.Add(1)
+		go func(idx int, src csprng.Source) {
+			defer wg.Done()
+			polyEval := poly.NewEvaluator(p.TRGSWLv1.N)
+			trgswCipher := NewTRGSWLv1WithParams(p).EncryptTorusWithSource(
+				secretKey.KeyLv0[idx],
+				p.BSKAlpha(),
+				secretKey.KeyLv1,
+				polyEval,
+				src,
+			)
+			result[idx] = NewTRGSWLv1FFT(trgswCipher, polyEval)
+		}(i, csprng.Fork())
+	}
+	wg.Wait()
+
+	return result
+}
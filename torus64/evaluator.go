@@ -0,0 +1,136 @@
+package torus64
+
+import (
+	"github.com/thedonutfactory/go-tfhe/params"
+	"github.com/thedonutfactory/go-tfhe/poly"
+)
+
+// Evaluator performs bootstrapping on the 64-bit torus. It holds the
+// buffers of one evaluation, so use one evaluator per goroutine.
+type Evaluator struct {
+	// PolyEvaluator for polynomial operations
+	PolyEvaluator *poly.Evaluator
+
+	// Params is the parameter set the evaluator operates on
+	Params params.Parameters
+
+	decomposed        []poly.Poly64      // Decomposed accumulator, 2*L digit polynomials
+	decomposedFourier []poly.FourierPoly // Transformed digits
+	fourierA          poly.FourierPoly64 // External product accumulator of A
+	fourierB          poly.FourierPoly64 // External product accumulator of B
+	accumulator       *TRLWELv1          // Blind rotation accumulator
+	rotated           *TRLWELv1          // Rotated accumulator of a CMux
+	product           *TRLWELv1          // External product result
+	extracted         *TLWELv1           // Sample extracted from the accumulator
+}
+
+// NewEvaluatorWithParams creates a new evaluator for parameter set p
+func NewEvaluatorWithParams(p params.Parameters) *Evaluator {
+	n := p.TRGSWLv1.N
+	l := p.TRGSWLv1.L
+
+	e := &Evaluator{
+		PolyEvaluator:     poly.NewEvaluator(n),
+		Params:            p,
+		decomposed:        make([]poly.Poly64, 2*l),
+		decomposedFourier: make([]poly.FourierPoly, 2*l),
+		fourierA:          poly.NewFourierPoly64(n),
+		fourierB:          poly.NewFourierPoly64(n),
+		accumulator:       NewTRLWELv1WithParams(p),
+		rotated:           NewTRLWELv1WithParams(p),
+		product:           NewTRLWELv1WithParams(p),
+		extracted:         NewTLWELv1WithParams(p),
+	}
+	for i := range e.decomposed {
+		e.decomposed[i] = poly.NewPoly64(n)
+		e.decomposedFourier[i] = poly.NewFourierPoly(n)
+	}
+	return e
+}
+
+// ExternalProductAssign computes the external product of a TRGSW and a TRLWE
+// ciphertext and writes it to ctOut
+func (e *Evaluator) ExternalProductAssign(ctFourierGGSW *TRGSWLv1FFT, ctIn *TRLWELv1, decompositionOffset params.Torus64, ctOut *TRLWELv1) {
+	l := e.Params.TRGSWLv1.L
+	bgbit := int(e.Params.TRGSWLv1.BGBIT)
+
+	DecomposePolyAssign(ctIn.A, bgbit, l, decompositionOffset, e.decomposed[:l])
+	DecomposePolyAssign(ctIn.B, bgbit, l, decompositionOffset, e.decomposed[l:])
+
+	e.fourierA.Clear()
+	e.fourierB.Clear()
+	for i := 0; i < 2*l; i++ {
+		e.PolyEvaluator.SmallPoly64ToFourierPolyAssign(e.decomposed[i], e.decomposedFourier[i])
+		e.PolyEvaluator.MulAddFourierPoly64Assign(e.decomposedFourier[i], ctFourierGGSW.TRLWEFFT[i].A, e.fourierA)
+		e.PolyEvaluator.MulAddFourierPoly64Assign(e.decomposedFourier[i], ctFourierGGSW.TRLWEFFT[i].B, e.fourierB)
+	}
+
+	e.PolyEvaluator.ToPoly64AssignUnsafe(e.fourierA, poly.Poly64{Coeffs: ctOut.A})
+	e.PolyEvaluator.ToPoly64AssignUnsafe(e.fourierB, poly.Poly64{Coeffs: ctOut.B})
+}
+
+// CMuxAssign computes ctOut = ct0 + ctCond * (ct1 - ct0). ctOut may be ct0.
+func (e *Evaluator) CMuxAssign(ctCond *TRGSWLv1FFT, ct0, ct1 *TRLWELv1, decompositionOffset params.Torus64, ctOut *TRLWELv1) {
+	for i := range ct0.A {
+		e.product.A[i] = ct1.A[i] - ct0.A[i]
+		e.product.B[i] = ct1.B[i] - ct0.B[i]
+	}
+	e.ExternalProductAssign(ctCond, e.product, decompositionOffset, e.product)
+	for i := range ct0.A {
+		ctOut.A[i] = ct0.A[i] + e.product.A[i]
+		ctOut.B[i] = ct0.B[i] + e.product.B[i]
+	}
+}
+
+// BlindRotateAssign rotates testvec by X^-phase of ctIn, rounded to Z_2N,
+// with one CMux per level 0 coefficient, and writes it to ctOut
+func (e *Evaluator) BlindRotateAssign(ctIn *TLWELv0, testvec *TRLWELv1, bsk []*TRGSWLv1FFT, decompositionOffset params.Torus64, ctOut *TRLWELv1) {
+	n := e.Params.TRGSWLv1.N
+	nBit := e.Params.TRGSWLv1.NBIT
+	acc := e.accumulator
+
+	bTilda := 2*n - modSwitch(ctIn.B(), nBit)
+	poly.PolyMulWithXKInPlace64(testvec.A, bTilda, acc.A)
+	poly.PolyMulWithXKInPlace64(testvec.B, bTilda, acc.B)
+
+	for i := 0; i < e.Params.TLWELv0.N; i++ {
+		aTilda := modSwitch(ctIn.P[i], nBit)
+		if aTilda == 0 {
+			continue
+		}
+		poly.PolyMulWithXKInPlace64(acc.A, aTilda, e.rotated.A)
+		poly.PolyMulWithXKInPlace64(acc.B, aTilda, e.rotated.B)
+		e.CMuxAssign(bsk[i], acc, e.rotated, decompositionOffset, acc)
+	}
+
+	copy(ctOut.A, acc.A)
+	copy(ctOut.B, acc.B)
+}
+
+// modSwitch rounds x from the torus to Z_2N, with N = 2^nBit
+func modSwitch(x params.Torus64, nBit int) int {
+	shift := 64 - (nBit + 1)
+	return int((x + params.Torus64(1)<<(shift-1)) >> shift)
+}
+
+// BootstrapLUTAssign evaluates the lookup table testvec (see GenLookUpTable)
+// on ctIn: blind rotation, sample extraction of the constant term and key
+// switching back to the level 0 key. ctOut may be ctIn.
+func (e *Evaluator) BootstrapLUTAssign(ctIn *TLWELv0, testvec *TRLWELv1, bsk []*TRGSWLv1FFT, ksk []*TLWELv0, decompositionOffset params.Torus64, ctOut *TLWELv0) {
+	e.BlindRotateAssign(ctIn, testvec, bsk, decompositionOffset, e.accumulator)
+	SampleExtractIndexAssign(e.accumulator, 0, e.extracted)
+	IdentityKeySwitchingAssign(e.extracted, ksk, ctOut)
+}
+
+// BootstrapLUT evaluates the lookup table testvec on ctIn (see BootstrapLUTAssign)
+func (e *Evaluator) BootstrapLUT(ctIn *TLWELv0, testvec *TRLWELv1, bsk []*TRGSWLv1FFT, ksk []*TLWELv0, decompositionOffset params.Torus64) *TLWELv0 {
+	result := NewTLWELv0WithParams(e.Params)
+	e.BootstrapLUTAssign(ctIn, testvec, bsk, ksk, decompositionOffset, result)
+	return result
+}
+
+// BootstrapFunc performs programmable bootstrapping with a function f on
+// the message space [0, messageModulus)
+func (e *Evaluator) BootstrapFunc(ctIn *TLWELv0, f func(int) int, messageModulus int, bsk []*TRGSWLv1FFT, ksk []*TLWELv0, decompositionOffset params.Torus64) *TLWELv0 {
+	return e.BootstrapLUT(ctIn, GenLookUpTable(e.Params, f, messageModulus), bsk, ksk, decompositionOffset)
+}
@@ -0,0 +1,80 @@
+package torus64
+
+import (
+	"sync"
+
+	"github.com/thedonutfactory/go-tfhe/csprng"
+	"github.com/thedonutfactory/go-tfhe/params"
+)
+
+// genKeySwitchingKey generates the key switching key (parallelized). Entry
+// base*IKS_T*i + base*j + k encrypts k * KeyLv1[i] * 2^-(j+1)*BASEBIT under
+// the level 0 key.
+func genKeySwitchingKey(prm params.Parameters, secretKey *SecretKey) []*TLWELv0 {
+	basebit := prm.TRGSWLv1.BASEBIT
+	iksT := prm.TRGSWLv1.IKS_T
+	base := 1 << basebit
+	n := prm.TRGSWLv1.N
+
+	result := make([]*TLWELv0, base*iksT*n)
+	for i := range result {
+		result[i] = NewTLWELv0WithParams(prm)
+	}
+
+	// Fork one randomness source per row up front so that seeded
+	// (deterministic) sources give reproducible keys despite the parallelism
+	var wg sync.WaitGroup
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		go func(iIdx int, src csprng.Source) {
+			defer wg.Done()
+			for j := 0; j < iksT; j++ {
+				for k := 1; k < base; k++ {
+					mu := params.Torus64(k) * secretKey.KeyLv1[iIdx] << (64 - (j+1)*basebit)
+					idx := (base * iksT * iIdx) + (base * j) + k
+					result[idx].EncryptTorusWithSource(mu, prm.KSKAlpha(), secretKey.KeyLv0, src)
+				}
+			}
+		}(i, csprng.Fork())
+	}
+	wg.Wait()
+
+	return result
+}
+
+// IdentityKeySwitchingAssign switches src from the level 1 key to the level
+// 0 key and writes to output. Each mask coefficient is rounded to
+// IKS_T*BASEBIT bits and decomposed into IKS_T digits of BASEBIT bits.
+// The parameter set is taken from src.Params.
+func IdentityKeySwitchingAssign(src *TLWELv1, keySwitchingKey []*TLWELv0, output *TLWELv0) {
+	n := src.Params.TRGSWLv1.N
+	basebit := src.Params.TRGSWLv1.BASEBIT
+	base := 1 << basebit
+	iksT := src.Params.TRGSWLv1.IKS_T
+	tlweLv0N := src.Params.TLWELv0.N
+
+	for i := range output.P {
+		output.P[i] = 0
+	}
+	output.P[tlweLv0N] = src.P[len(src.P)-1]
+
+	// Round to IKS_T*BASEBIT bits (nothing to round when they fill the torus)
+	var precOffset params.Torus64
+	if bits := basebit * iksT; bits < 64 {
+		precOffset = params.Torus64(1) << (63 - bits)
+	}
+	mask := params.Torus64(base - 1)
+
+	for i := 0; i < n; i++ {
+		aBar := src.P[i] + precOffset
+		for j := 0; j < iksT; j++ {
+			k := (aBar >> (64 - (j+1)*basebit)) & mask
+			if k != 0 {
+				idx := (base * iksT * i) + (base * j) + int(k)
+				for x := range output.P {
+					output.P[x] -= keySwitchingKey[idx].P[x]
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,46 @@
+package torus64
+
+import (
+	"github.com/thedonutfactory/go-tfhe/params"
+)
+
+// GenLookUpTable generates the test vector evaluating f on messages in
+// [0, messageModulus) encoded with EncryptLWEMessage, with the layout of
+// lut.Generator: each message owns N/messageModulus coefficients, rotated by
+// half a box so that the noise is rounded to the nearest message, and the
+// coefficients that wrap around are negated. Tables always have N
+// coefficients: params.Validate64 rejects parameter sets with extended
+// tables (LookUpTableSize > N).
+func GenLookUpTable(p params.Parameters, f func(int) int, messageModulus int) *TRLWELv1 {
+	n := p.TRGSWLv1.N
+	scale := messageScale(messageModulus)
+
+	lutRaw := make([]params.Torus64, n)
+	for x := 0; x < messageModulus; x++ {
+		start := divRound(x*n, messageModulus)
+		end := divRound((x+1)*n, messageModulus)
+
+		y := f(x) % messageModulus
+		if y < 0 {
+			y += messageModulus
+		}
+		for i := start; i < end; i++ {
+			lutRaw[i] = params.Torus64(y) * scale
+		}
+	}
+
+	offset := divRound(n, 2*messageModulus)
+	testvec := NewTRLWELv1WithParams(p)
+	for i := 0; i < n; i++ {
+		testvec.B[i] = lutRaw[(i+offset)%n]
+	}
+	for i := n - offset; i < n; i++ {
+		testvec.B[i] = -testvec.B[i]
+	}
+	return testvec
+}
+
+// divRound performs integer division with rounding
+func divRound(a, b int) int {
+	return (a + b/2) / b
+}
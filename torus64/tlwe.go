@@ -0,0 +1,150 @@
+package torus64
+
+import (
+	"math/rand"
+
+	"github.com/thedonutfactory/go-tfhe/csprng"
+	"github.com/thedonutfactory/go-tfhe/params"
+	"github.com/thedonutfactory/go-tfhe/utils"
+)
+
+// TLWELv0 represents a Level 0 TLWE ciphertext on the 64-bit torus
+type TLWELv0 struct {
+	P      []params.Torus64  // Length is N+1, where last element is b
+	Params params.Parameters // Parameter set the ciphertext belongs to
+}
+
+// NewTLWELv0WithParams creates a new TLWE Level 0 ciphertext for parameter set p
+func NewTLWELv0WithParams(p params.Parameters) *TLWELv0 {
+	return &TLWELv0{
+		P:      make([]params.Torus64, p.TLWELv0.N+1),
+		Params: p,
+	}
+}
+
+// B returns the b component of the TLWE ciphertext
+func (t *TLWELv0) B() params.Torus64 {
+	return t.P[len(t.P)-1]
+}
+
+// SetB sets the b component of the TLWE ciphertext
+func (t *TLWELv0) SetB(val params.Torus64) {
+	t.P[len(t.P)-1] = val
+}
+
+// EncryptTorus encrypts a torus value with TLWE Level 0
+func (t *TLWELv0) EncryptTorus(mu params.Torus64, alpha float64, key []params.Torus64) *TLWELv0 {
+	return t.EncryptTorusWithSource(mu, alpha, key, csprng.Fork())
+}
+
+// EncryptTorusWithSource encrypts a torus value with TLWE Level 0 drawing
+// the mask and noise from src
+func (t *TLWELv0) EncryptTorusWithSource(mu params.Torus64, alpha float64, key []params.Torus64, src csprng.Source) *TLWELv0 {
+	encryptLWE(t.P, mu, alpha, key, csprng.Rand(src))
+	return t
+}
+
+// EncryptLWEMessage encrypts an integer message with the encoding of
+// tlwe.EncryptLWEMessage: message * 2^63 / messageModulus, which leaves a
+// padding bit for programmable bootstrapping
+func (t *TLWELv0) EncryptLWEMessage(message int, messageModulus int, alpha float64, key []params.Torus64) *TLWELv0 {
+	message %= messageModulus
+	if message < 0 {
+		message += messageModulus
+	}
+	return t.EncryptTorus(params.Torus64(message)*messageScale(messageModulus), alpha, key)
+}
+
+// DecryptLWEMessage decrypts an integer message encrypted with EncryptLWEMessage
+func (t *TLWELv0) DecryptLWEMessage(messageModulus int, key []params.Torus64) int {
+	scale := messageScale(messageModulus)
+	decoded := (t.Phase(key) + scale/2) / scale
+	return int(decoded % params.Torus64(messageModulus))
+}
+
+// Phase returns b - <a, key>: the encrypted value plus the noise
+func (t *TLWELv0) Phase(key []params.Torus64) params.Torus64 {
+	return phase(t.P, key)
+}
+
+// Add adds two TLWE Level 0 ciphertexts
+func (t *TLWELv0) Add(other *TLWELv0) *TLWELv0 {
+	result := NewTLWELv0WithParams(t.Params)
+	for i := range result.P {
+		result.P[i] = t.P[i] + other.P[i]
+	}
+	return result
+}
+
+// Sub subtracts two TLWE Level 0 ciphertexts
+func (t *TLWELv0) Sub(other *TLWELv0) *TLWELv0 {
+	result := NewTLWELv0WithParams(t.Params)
+	for i := range result.P {
+		result.P[i] = t.P[i] - other.P[i]
+	}
+	return result
+}
+
+// TLWELv1 represents a Level 1 TLWE ciphertext on the 64-bit torus
+type TLWELv1 struct {
+	P      []params.Torus64  // Length is N+1, where last element is b
+	Params params.Parameters // Parameter set the ciphertext belongs to
+}
+
+// NewTLWELv1WithParams creates a new TLWE Level 1 ciphertext for parameter set p
+func NewTLWELv1WithParams(p params.Parameters) *TLWELv1 {
+	return &TLWELv1{
+		P:      make([]params.Torus64, p.TLWELv1.N+1),
+		Params: p,
+	}
+}
+
+// SetB sets the b component of the TLWE Level 1 ciphertext
+func (t *TLWELv1) SetB(val params.Torus64) {
+	t.P[len(t.P)-1] = val
+}
+
+// EncryptTorus encrypts a torus value with TLWE Level 1
+func (t *TLWELv1) EncryptTorus(mu params.Torus64, alpha float64, key []params.Torus64) *TLWELv1 {
+	return t.EncryptTorusWithSource(mu, alpha, key, csprng.Fork())
+}
+
+// EncryptTorusWithSource encrypts a torus value with TLWE Level 1 drawing
+// the mask and noise from src
+func (t *TLWELv1) EncryptTorusWithSource(mu params.Torus64, alpha float64, key []params.Torus64, src csprng.Source) *TLWELv1 {
+	encryptLWE(t.P, mu, alpha, key, csprng.Rand(src))
+	return t
+}
+
+// Phase returns b - <a, key>: the encrypted value plus the noise
+func (t *TLWELv1) Phase(key []params.Torus64) params.Torus64 {
+	return phase(t.P, key)
+}
+
+// messageScale returns the encoding scale of messages: 2^63 / messageModulus
+func messageScale(messageModulus int) params.Torus64 {
+	return params.Torus64(1<<63) / params.Torus64(messageModulus)
+}
+
+// encryptLWE fills p with a uniform mask and b = <a, key> + mu + e
+func encryptLWE(p []params.Torus64, mu params.Torus64, alpha float64, key []params.Torus64, rng *rand.Rand) {
+	n := len(p) - 1
+
+	var innerProduct params.Torus64
+	for i := 0; i < n; i++ {
+		a := params.Torus64(rng.Uint64())
+		innerProduct += key[i] * a
+		p[i] = a
+	}
+	p[n] = innerProduct + utils.GaussianTorus64(mu, alpha, rng)
+}
+
+// phase returns b - <a, key> of the LWE ciphertext p
+func phase(p []params.Torus64, key []params.Torus64) params.Torus64 {
+	n := len(p) - 1
+	var innerProduct params.Torus64
+	for i := 0; i < n; i++ {
+		innerProduct += p[i] * key[i]
+	}
+	return p[n] - innerProduct
+}
@@ -0,0 +1,64 @@
+// Package torus64 implements TFHE on the 64-bit torus: TLWE, TRLWE and TRGSW
+// ciphertexts, gadget decomposition, key switching and programmable
+// bootstrapping over params.Torus64.
+//
+// The 32-bit torus of the other packages rounds away noise below 2^-32, so
+// parameter sets with a smaller noise, such as the level 1 noise of the Uint
+// profiles (down to 2^-55), lose both their security and the precision their
+// decomposition was chosen for (see params.EstimateSecurity). On the 64-bit
+// torus those parameter sets behave as designed, and the decompositions may
+// use up to 64 bits (see params.Validate64).
+//
+// Polynomial products go through the float64 FFT of the poly package, which
+// splits 64-bit coefficients into 16-bit limbs (see poly.FourierPoly64), so
+// an external product costs about four times the 32-bit one. Blind rotation
+// uses one CMux per level 0 coefficient, which is correct for block binary
+// keys as well, and lookup tables have N coefficients. NewCloudKey rejects
+// parameter sets that fail params.Validate64.
+package torus64
+
+import (
+	"github.com/thedonutfactory/go-tfhe/csprng"
+	"github.com/thedonutfactory/go-tfhe/key"
+	"github.com/thedonutfactory/go-tfhe/params"
+)
+
+// SecretKey contains the secret keys for both levels as 64-bit torus values
+type SecretKey struct {
+	KeyLv0 []params.Torus64
+	KeyLv1 []params.Torus64
+	Params params.Parameters // Parameter set the key was generated for
+}
+
+// NewSecretKeyWithParams generates a new secret key for parameter set p
+// using the default randomness source
+func NewSecretKeyWithParams(p params.Parameters) *SecretKey {
+	return NewSecretKeyWithSource(p, csprng.Fork())
+}
+
+// NewSecretKeyWithSource generates a new secret key for parameter set p
+// drawing randomness from src. The keys have the distribution of
+// key.NewSecretKeyWithSource.
+func NewSecretKeyWithSource(p params.Parameters, src csprng.Source) *SecretKey {
+	return NewSecretKeyFrom(key.NewSecretKeyWithSource(p, src))
+}
+
+// NewSecretKeyFrom converts a 32-bit secret key. Both keys are binary, so
+// the conversion is exact, but a key should not be used on both tori: the
+// same secret with two noise levels is only as secure as the weaker one.
+func NewSecretKeyFrom(sk *key.SecretKey) *SecretKey {
+	return &SecretKey{
+		KeyLv0: toTorus64(sk.KeyLv0),
+		KeyLv1: toTorus64(sk.KeyLv1),
+		Params: sk.Params,
+	}
+}
+
+// toTorus64 converts the coefficients of a binary key
+func toTorus64(key []params.Torus) []params.Torus64 {
+	result := make([]params.Torus64, len(key))
+	for i, k := range key {
+		result[i] = params.Torus64(k)
+	}
+	return result
+}
@@ -0,0 +1,137 @@
+package torus64_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/thedonutfactory/go-tfhe/params"
+	"github.com/thedonutfactory/go-tfhe/poly"
+	"github.com/thedonutfactory/go-tfhe/torus64"
+)
+
+// phaseError returns the phase minus the expected value, as a fraction of the torus
+func phaseError(phase, want params.Torus64) float64 {
+	return math.Ldexp(float64(int64(phase-want)), -64)
+}
+
+func TestEncryptionNoise(t *testing.T) {
+	// The level 1 noise of Uint5 (2^-55) rounds to zero on the 32-bit torus
+	p := params.GetParameters(params.SecurityUint5)
+	alpha := p.BSKAlpha()
+	sk := torus64.NewSecretKeyWithParams(p)
+	polyEval := poly.NewEvaluator(p.TRGSWLv1.N)
+
+	const samples = 256
+	var lweVar float64
+	for i := 0; i < samples; i++ {
+		mu := params.Torus64(i) << 56
+		lwe := torus64.NewTLWELv1WithParams(p).EncryptTorus(mu, alpha, sk.KeyLv1)
+		e := phaseError(lwe.Phase(sk.KeyLv1), mu)
+		lweVar += e * e
+	}
+
+	// Decrypt every 16th coefficient of TRLWE ciphertexts through sample extraction
+	var rlweVar float64
+	var count int
+	for i := 0; i < 8; i++ {
+		plain := make([]params.Torus64, p.TRGSWLv1.N)
+		for j := range plain {
+			plain[j] = params.Torus64(i)<<59 + params.Torus64(j)<<50
+		}
+		rlwe := torus64.NewTRLWELv1WithParams(p).EncryptTorus(plain, alpha, sk.KeyLv1, polyEval)
+		for j := 0; j < len(plain); j += 16 {
+			e := phaseError(torus64.SampleExtractIndex(rlwe, j).Phase(sk.KeyLv1), plain[j])
+			rlweVar += e * e
+			count++
+		}
+	}
+
+	for _, tc := range []struct {
+		name string
+		std  float64
+	}{
+		{"TLWE", math.Sqrt(lweVar / samples)},
+		{"TRLWE", math.Sqrt(rlweVar / float64(count))},
+	} {
+		if ratio := tc.std / alpha; ratio < 0.8 || ratio > 1.25 {
+			t.Errorf("%s noise %g, want about %g", tc.name, tc.std, alpha)
+		}
+	}
+}
+
+// testBootstrapLUT bootstraps every message of [0, messageModulus) through
+// the lookup table of f a few times each, and checks the results and their
+// noise against params.BootstrapVariance
+func testBootstrapLUT(t *testing.T, p params.Parameters, messageModulus int, f func(int) int) (*torus64.SecretKey, *torus64.CloudKey, *torus64.Evaluator) {
+	t.Helper()
+	if err := p.Validate64(); err != nil {
+		t.Fatal(err)
+	}
+
+	sk := torus64.NewSecretKeyWithParams(p)
+	ck := torus64.NewCloudKey(sk)
+	eval := torus64.NewEvaluatorWithParams(p)
+
+	scale := params.Torus64(1<<63) / params.Torus64(messageModulus)
+	testvec := torus64.GenLookUpTable(p, f, messageModulus)
+
+	const rounds = 6
+	var variance float64
+	for i := 0; i < rounds*messageModulus; i++ {
+		x := i % messageModulus
+		ct := torus64.NewTLWELv0WithParams(p).EncryptLWEMessage(x, messageModulus, p.TLWELv0.ALPHA, sk.KeyLv0)
+
+		result := eval.BootstrapLUT(ct, testvec, ck.BootstrappingKey, ck.KeySwitchingKey, ck.DecompositionOffset)
+		if got := result.DecryptLWEMessage(messageModulus, sk.KeyLv0); got != f(x) {
+			t.Errorf("f(%d) = %d, want %d", x, got, f(x))
+		}
+		e := phaseError(result.Phase(sk.KeyLv0), params.Torus64(f(x))*scale)
+		variance += e * e
+	}
+
+	variance /= float64(rounds * messageModulus)
+	if want := p.BootstrapVariance(); variance > 2*want {
+		t.Errorf("bootstrap noise variance %g exceeds twice the estimate %g", variance, want)
+	}
+	return sk, ck, eval
+}
+
+func TestBootstrapFunc(t *testing.T) {
+	p := params.GetParameters(params.SecurityUint2)
+	const messageModulus = 4
+	f := func(x int) int { return (x*x + 1) % messageModulus }
+	sk, ck, eval := testBootstrapLUT(t, p, messageModulus, f)
+
+	// Bootstrapping twice evaluates the composition
+	ct := torus64.NewTLWELv0WithParams(p).EncryptLWEMessage(2, messageModulus, p.TLWELv0.ALPHA, sk.KeyLv0)
+	ct = eval.BootstrapFunc(ct, f, messageModulus, ck.BootstrappingKey, ck.KeySwitchingKey, ck.DecompositionOffset)
+	ct = eval.BootstrapFunc(ct, f, messageModulus, ck.BootstrappingKey, ck.KeySwitchingKey, ck.DecompositionOffset)
+	if got := ct.DecryptLWEMessage(messageModulus, sk.KeyLv0); got != f(f(2)) {
+		t.Errorf("f(f(2)) = %d, want %d", got, f(f(2)))
+	}
+}
+
+// TestBootstrapUint5 bootstraps 5-bit messages with the bootstrapping key of
+// Uint5, whose 2^-55 noise and 22-bit gadget only work on the 64-bit torus.
+// The Uint5 key switching key takes 3.4 GB on the 64-bit torus, so this test
+// switches keys with 9 digits of 2 bits instead (about 630 MB); the full
+// profile runs with -tags largekeys (uint5_test.go).
+func TestBootstrapUint5(t *testing.T) {
+	p := params.GetParameters(params.SecurityUint5)
+	p.TRGSWLv1.BASEBIT, p.TRGSWLv1.IKS_T = 2, 9
+	const messageModulus = 32
+	testBootstrapLUT(t, p, messageModulus, func(x int) int { return (7*x + 3) % messageModulus })
+}
+
+// TestNewCloudKeyRejectsExtendedLookUpTables checks that parameter sets with
+// lookup tables larger than N, like those of Uint6-Uint8, are rejected
+func TestNewCloudKeyRejectsExtendedLookUpTables(t *testing.T) {
+	p := params.GetParameters(params.SecurityUint2)
+	p.TRGSWLv1.LookUpTableSize = 2 * p.TRGSWLv1.N
+	defer func() {
+		if r := recover(); r == nil {
+			t.Error("NewCloudKey accepted LookUpTableSize = 2N")
+		}
+	}()
+	torus64.NewCloudKey(torus64.NewSecretKeyWithParams(p))
+}
@@ -0,0 +1,108 @@
+package torus64
+
+import (
+	"github.com/thedonutfactory/go-tfhe/csprng"
+	"github.com/thedonutfactory/go-tfhe/params"
+	"github.com/thedonutfactory/go-tfhe/poly"
+)
+
+// TRGSWLv1 represents a Level 1 TRGSW ciphertext on the 64-bit torus
+type TRGSWLv1 struct {
+	TRLWE  []*TRLWELv1
+	Params params.Parameters // Parameter set the ciphertext belongs to
+}
+
+// NewTRGSWLv1WithParams creates a new TRGSW Level 1 ciphertext for parameter set p
+func NewTRGSWLv1WithParams(p params.Parameters) *TRGSWLv1 {
+	trlweArray := make([]*TRLWELv1, 2*p.TRGSWLv1.L)
+	for i := range trlweArray {
+		trlweArray[i] = NewTRLWELv1WithParams(p)
+	}
+	return &TRGSWLv1{
+		TRLWE:  trlweArray,
+		Params: p,
+	}
+}
+
+// EncryptTorusWithSource encrypts an integer m (a key bit) with TRGSW Level 1
+// drawing all randomness from src. Row i adds m * 2^-(i+1)*BGBIT to the
+// mask of the first L rows and to the body of the last L rows.
+func (t *TRGSWLv1) EncryptTorusWithSource(m params.Torus64, alpha float64, key []params.Torus64, polyEval *poly.Evaluator, src csprng.Source) *TRGSWLv1 {
+	l := t.Params.TRGSWLv1.L
+	bgbit := int(t.Params.TRGSWLv1.BGBIT)
+
+	for _, trlwe := range t.TRLWE {
+		trlwe.EncryptTorusWithSource(nil, alpha, key, polyEval, src)
+	}
+	for i := 0; i < l; i++ {
+		g := m * gadget(bgbit, i)
+		t.TRLWE[i].A[0] += g
+		t.TRLWE[i+l].B[0] += g
+	}
+
+	return t
+}
+
+// gadget returns the gadget value of decomposition level i: 2^-(i+1)*bgbit
+func gadget(bgbit, i int) params.Torus64 {
+	return params.Torus64(1) << (64 - (i+1)*bgbit)
+}
+
+// TRGSWLv1FFT represents a TRGSW Level 1 ciphertext in FFT form
+type TRGSWLv1FFT struct {
+	TRLWEFFT []TRLWELv1FFT
+}
+
+// TRLWELv1FFT represents a TRLWE Level 1 ciphertext in FFT form
+type TRLWELv1FFT struct {
+	A poly.FourierPoly64
+	B poly.FourierPoly64
+}
+
+// NewTRGSWLv1FFT creates a new TRGSW Level 1 FFT ciphertext from a regular TRGSW
+func NewTRGSWLv1FFT(trgsw *TRGSWLv1, polyEval *poly.Evaluator) *TRGSWLv1FFT {
+	trlweFFTArray := make([]TRLWELv1FFT, len(trgsw.TRLWE))
+	for i, t := range trgsw.TRLWE {
+		trlweFFTArray[i] = TRLWELv1FFT{
+			A: polyEval.ToFourierPoly64(poly.Poly64{Coeffs: t.A}),
+			B: polyEval.ToFourierPoly64(poly.Poly64{Coeffs: t.B}),
+		}
+	}
+	return &TRGSWLv1FFT{
+		TRLWEFFT: trlweFFTArray,
+	}
+}
+
+// DecompositionOffset returns the decomposition offset of parameter set p on
+// the 64-bit torus: Bg/2 at every level, so that the digits are balanced in
+// [-Bg/2, Bg/2), plus half of the last level, so that the bits below
+// L*BGBIT are rounded rather than truncated
+func DecompositionOffset(p params.Parameters) params.Torus64 {
+	l := p.TRGSWLv1.L
+	bgbit := int(p.TRGSWLv1.BGBIT)
+
+	var offset params.Torus64
+	for i := 0; i < l; i++ {
+		offset += params.Torus64(p.TRGSWLv1.BG/2) * gadget(bgbit, i)
+	}
+	if shift := 64 - l*bgbit; shift > 0 {
+		offset += params.Torus64(1) << (shift - 1)
+	}
+
+	return offset
+}
+
+// DecomposePolyAssign decomposes polynomial p into level digits of bgbit
+// bits, balanced in [-2^(bgbit-1), 2^(bgbit-1)) and stored as signed
+// integers, and writes them to decomposedOut
+func DecomposePolyAssign(p []params.Torus64, bgbit, level int, offset params.Torus64, decomposedOut []poly.Poly64) {
+	mask := params.Torus64(1)<<bgbit - 1
+	halfBG := params.Torus64(1) << (bgbit - 1)
+
+	for j, c := range p {
+		tmp := c + offset
+		for i := 0; i < level; i++ {
+			decomposedOut[i].Coeffs[j] = ((tmp >> (64 - (i+1)*bgbit)) & mask) - halfBG
+		}
+	}
+}
@@ -0,0 +1,75 @@
+package torus64
+
+import (
+	"github.com/thedonutfactory/go-tfhe/csprng"
+	"github.com/thedonutfactory/go-tfhe/params"
+	"github.com/thedonutfactory/go-tfhe/poly"
+	"github.com/thedonutfactory/go-tfhe/utils"
+)
+
+// TRLWELv1 represents a Level 1 TRLWE ciphertext on the 64-bit torus
+type TRLWELv1 struct {
+	A      []params.Torus64
+	B      []params.Torus64
+	Params params.Parameters // Parameter set the ciphertext belongs to
+}
+
+// NewTRLWELv1WithParams creates a new TRLWE Level 1 ciphertext for parameter set p
+func NewTRLWELv1WithParams(p params.Parameters) *TRLWELv1 {
+	n := p.TRLWELv1.N
+	return &TRLWELv1{
+		A:      make([]params.Torus64, n),
+		B:      make([]params.Torus64, n),
+		Params: p,
+	}
+}
+
+// EncryptTorus encrypts a polynomial of torus values with TRLWE Level 1
+func (t *TRLWELv1) EncryptTorus(mu []params.Torus64, alpha float64, key []params.Torus64, polyEval *poly.Evaluator) *TRLWELv1 {
+	return t.EncryptTorusWithSource(mu, alpha, key, polyEval, csprng.Fork())
+}
+
+// EncryptTorusWithSource encrypts a polynomial of torus values with TRLWE
+// Level 1 drawing the mask and noise from src. A nil mu encrypts zero.
+func (t *TRLWELv1) EncryptTorusWithSource(mu []params.Torus64, alpha float64, key []params.Torus64, polyEval *poly.Evaluator, src csprng.Source) *TRLWELv1 {
+	rng := csprng.Rand(src)
+	n := len(t.A)
+
+	for i := 0; i < n; i++ {
+		t.A[i] = params.Torus64(rng.Uint64())
+	}
+
+	// b = a * s + mu + e
+	polyEval.MulPoly64Assign(poly.Poly64{Coeffs: t.A}, poly.Poly64{Coeffs: key}, poly.Poly64{Coeffs: t.B})
+	for i := 0; i < n; i++ {
+		var m params.Torus64
+		if mu != nil {
+			m = mu[i]
+		}
+		t.B[i] += utils.GaussianTorus64(m, alpha, rng)
+	}
+
+	return t
+}
+
+// SampleExtractIndex extracts a TLWE sample from a TRLWE at index k
+func SampleExtractIndex(trlwe *TRLWELv1, k int) *TLWELv1 {
+	result := NewTLWELv1WithParams(trlwe.Params)
+	SampleExtractIndexAssign(trlwe, k, result)
+	return result
+}
+
+// SampleExtractIndexAssign extracts a TLWE sample from a TRLWE at index k
+// and writes it to output
+func SampleExtractIndexAssign(trlwe *TRLWELv1, k int, output *TLWELv1) {
+	n := len(trlwe.A)
+
+	for i := 0; i < n; i++ {
+		if i <= k {
+			output.P[i] = trlwe.A[k-i]
+		} else {
+			output.P[i] = -trlwe.A[n+k-i]
+		}
+	}
+	output.SetB(trlwe.B[k])
+}
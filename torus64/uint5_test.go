@@ -0,0 +1,17 @@
+//go:build largekeys
+
+package torus64_test
+
+import (
+	"testing"
+
+	"github.com/thedonutfactory/go-tfhe/params"
+)
+
+// TestBootstrapUint5Profile bootstraps with the full Uint5 profile, whose
+// key switching key takes about 3.4 GB on the 64-bit torus. Run it with
+// make test-largekeys.
+func TestBootstrapUint5Profile(t *testing.T) {
+	const messageModulus = 32
+	testBootstrapLUT(t, params.GetParameters(params.SecurityUint5), messageModulus, func(x int) int { return (7*x + 3) % messageModulus })
+}
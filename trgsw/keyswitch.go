@@ -6,13 +6,13 @@ import (
 )
 
 // IdentityKeySwitchingAssign performs identity key switching and writes to output
-// Zero-allocation version
+// Zero-allocation version. The parameter set is taken from src.Params.
 func IdentityKeySwitchingAssign(src *tlwe.TLWELv1, keySwitchingKey []*tlwe.TLWELv0, output *tlwe.TLWELv0) {
-	n := params.GetTRGSWLv1().N
-	basebit := params.GetTRGSWLv1().BASEBIT
+	n := src.Params.TRGSWLv1.N
+	basebit := src.Params.TRGSWLv1.BASEBIT
 	base := 1 << basebit
-	iksT := params.GetTRGSWLv1().IKS_T
-	tlweLv0N := params.GetTLWELv0().N
+	iksT := src.Params.TRGSWLv1.IKS_T
+	tlweLv0N := src.Params.TLWELv0.N
 
 	// Clear output
 	for i := 0; i < len(output.P); i++ {
@@ -4,6 +4,7 @@ import (
 	"math"
 	"sync"
 
+	"github.com/thedonutfactory/go-tfhe/csprng"
 	"github.com/thedonutfactory/go-tfhe/params"
 	"github.com/thedonutfactory/go-tfhe/poly"
 	"github.com/thedonutfactory/go-tfhe/tlwe"
@@ -13,26 +14,39 @@ import (
 
 // TRGSWLv1 represents a Level 1 TRGSW ciphertext
 type TRGSWLv1 struct {
-	TRLWE []*trlwe.TRLWELv1
+	TRLWE  []*trlwe.TRLWELv1
+	Params params.Parameters // Parameter set the ciphertext belongs to
 }
 
-// NewTRGSWLv1 creates a new TRGSW Level 1 ciphertext
+// NewTRGSWLv1 creates a new TRGSW Level 1 ciphertext for the current security level
 func NewTRGSWLv1() *TRGSWLv1 {
-	l := params.GetTRGSWLv1().L
+	return NewTRGSWLv1WithParams(params.Current())
+}
+
+// NewTRGSWLv1WithParams creates a new TRGSW Level 1 ciphertext for parameter set p
+func NewTRGSWLv1WithParams(p params.Parameters) *TRGSWLv1 {
+	l := p.TRGSWLv1.L
 	trlweArray := make([]*trlwe.TRLWELv1, l*2)
 	for i := range trlweArray {
-		trlweArray[i] = trlwe.NewTRLWELv1()
+		trlweArray[i] = trlwe.NewTRLWELv1WithParams(p)
 	}
 	return &TRGSWLv1{
-		TRLWE: trlweArray,
+		TRLWE:  trlweArray,
+		Params: p,
 	}
 }
 
 // EncryptTorus encrypts a torus value with TRGSW Level 1
 func (t *TRGSWLv1) EncryptTorus(p params.Torus, alpha float64, key []params.Torus, polyEval *poly.Evaluator) *TRGSWLv1 {
-	l := params.GetTRGSWLv1().L
-	bg := float64(params.GetTRGSWLv1().BG)
-	n := params.GetTRGSWLv1().N
+	return t.EncryptTorusWithSource(p, alpha, key, polyEval, csprng.Fork())
+}
+
+// EncryptTorusWithSource encrypts a torus value with TRGSW Level 1 drawing
+// all randomness from src
+func (t *TRGSWLv1) EncryptTorusWithSource(p params.Torus, alpha float64, key []params.Torus, polyEval *poly.Evaluator, src csprng.Source) *TRGSWLv1 {
+	l := t.Params.TRGSWLv1.L
+	bg := float64(t.Params.TRGSWLv1.BG)
+	n := t.Params.TRGSWLv1.N
 
 	// Calculate p_f64 values
 	pF64 := make([]float64, l)
@@ -44,7 +58,7 @@ func (t *TRGSWLv1) EncryptTorus(p params.Torus, alpha float64, key []params.Toru
 
 	// Encrypt all TRLWE samples
 	for i := range t.TRLWE {
-		t.TRLWE[i] = trlwe.NewTRLWELv1().EncryptF64(plainZero, alpha, key, polyEval)
+		t.TRLWE[i] = trlwe.NewTRLWELv1WithParams(t.Params).EncryptF64WithSource(plainZero, alpha, key, polyEval, src)
 	}
 
 	// Add the gadget decomposition
@@ -81,9 +95,14 @@ func NewTRGSWLv1FFT(trgsw *TRGSWLv1, polyEval *poly.Evaluator) *TRGSWLv1FFT {
 	}
 }
 
-// NewTRGSWLv1FFTDummy creates a dummy TRGSW Level 1 FFT ciphertext
+// NewTRGSWLv1FFTDummy creates a dummy TRGSW Level 1 FFT ciphertext for the current security level
 func NewTRGSWLv1FFTDummy(polyEval *poly.Evaluator) *TRGSWLv1FFT {
-	l := params.GetTRGSWLv1().L
+	return NewTRGSWLv1FFTDummyWithParams(params.Current(), polyEval)
+}
+
+// NewTRGSWLv1FFTDummyWithParams creates a dummy TRGSW Level 1 FFT ciphertext for parameter set p
+func NewTRGSWLv1FFTDummyWithParams(p params.Parameters, polyEval *poly.Evaluator) *TRGSWLv1FFT {
+	l := p.TRGSWLv1.L
 	trlweFFTArray := make([]TRLWELv1FFT, l*2)
 	for i := range trlweFFTArray {
 		trlweFFTArray[i] = TRLWELv1FFT{
@@ -104,9 +123,10 @@ type CloudKeyData interface {
 }
 
 // ExternalProductWithFFT performs external product with FFT optimization
-// This version uses pre-allocated buffers for maximum zero-allocation performance
+// This version uses pre-allocated buffers for maximum zero-allocation performance.
+// The decomposition parameters are taken from trlweIn.Params.
 func ExternalProductWithFFT(trgswFFT *TRGSWLv1FFT, trlweIn *trlwe.TRLWELv1, decompositionOffset params.Torus, polyEval *poly.Evaluator) *trlwe.TRLWELv1 {
-	l := params.GetTRGSWLv1().L
+	l := trlweIn.Params.TRGSWLv1.L
 
 	// Use decomposition buffer pool (zero-allocation)
 	decompositionInPlace(trlweIn, decompositionOffset, polyEval)
@@ -130,16 +150,16 @@ func ExternalProductWithFFT(trgswFFT *TRGSWLv1FFT, trlweIn *trlwe.TRLWELv1, deco
 	polyEval.BufferToPolyAssign("fpAcc", resultA)
 	polyEval.BufferToPolyAssign("fpBcc", resultB)
 
-	return &trlwe.TRLWELv1{A: resultA, B: resultB}
+	return &trlwe.TRLWELv1{A: resultA, B: resultB, Params: trlweIn.Params}
 }
 
 // decompositionInPlace performs gadget decomposition directly into evaluator buffers (zero-allocation)
 func decompositionInPlace(trlweIn *trlwe.TRLWELv1, decompositionOffset params.Torus, polyEval *poly.Evaluator) {
-	l := params.GetTRGSWLv1().L
-	n := params.GetTRGSWLv1().N
+	l := trlweIn.Params.TRGSWLv1.L
+	n := len(trlweIn.A)
 
 	offset := decompositionOffset
-	bgbit := params.GetTRGSWLv1().BGBIT
+	bgbit := trlweIn.Params.TRGSWLv1.BGBIT
 	mask := params.Torus((1 << bgbit) - 1)
 	halfBG := params.Torus(1 << (bgbit - 1))
 
@@ -171,7 +191,7 @@ func decompositionInPlace(trlweIn *trlwe.TRLWELv1, decompositionOffset params.To
 // CMUX performs controlled MUX operation (zero-allocation version using TRLWE pool)
 // if cond == 0 then in1 else in2
 func CMUX(in1, in2 *trlwe.TRLWELv1, cond *TRGSWLv1FFT, decompositionOffset params.Torus, polyEval *poly.Evaluator) *trlwe.TRLWELv1 {
-	n := params.GetTRGSWLv1().N
+	n := len(in1.A)
 
 	// Get TRLWE buffer from pool for difference computation
 	tmpA, tmpB := polyEval.GetTRLWEBuffer()
@@ -179,7 +199,7 @@ func CMUX(in1, in2 *trlwe.TRLWELv1, cond *TRGSWLv1FFT, decompositionOffset param
 		tmpA[i] = in2.A[i] - in1.A[i]
 		tmpB[i] = in2.B[i] - in1.B[i]
 	}
-	tmp := &trlwe.TRLWELv1{A: tmpA, B: tmpB}
+	tmp := &trlwe.TRLWELv1{A: tmpA, B: tmpB, Params: in1.Params}
 
 	// External product (uses internal buffers for zero-alloc in hot path)
 	tmp2 := ExternalProductWithFFT(cond, tmp, decompositionOffset, polyEval)
@@ -193,10 +213,12 @@ func CMUX(in1, in2 *trlwe.TRLWELv1, cond *TRGSWLv1FFT, decompositionOffset param
 	return tmp2
 }
 
-// BlindRotate performs blind rotation for bootstrapping (optimized with buffer pool)
+// BlindRotate performs blind rotation for bootstrapping (optimized with buffer pool).
+// The parameter set is taken from blindRotateTestvec.Params.
 func BlindRotate(src *tlwe.TLWELv0, blindRotateTestvec *trlwe.TRLWELv1, bootstrappingKey []*TRGSWLv1FFT, decompositionOffset params.Torus, polyEval *poly.Evaluator) *trlwe.TRLWELv1 {
-	n := params.GetTRGSWLv1().N
-	nBit := params.GetTRGSWLv1().NBIT
+	p := blindRotateTestvec.Params
+	n := p.TRGSWLv1.N
+	nBit := p.TRGSWLv1.NBIT
 
 	// Reset rotation pool for this operation
 	polyEval.ResetRotationPool()
@@ -206,16 +228,16 @@ func BlindRotate(src *tlwe.TLWELv0, blindRotateTestvec *trlwe.TRLWELv1, bootstra
 	// Initial rotation using buffer pool
 	resultA := polyEval.PolyMulWithXK(blindRotateTestvec.A, bTilda)
 	resultB := polyEval.PolyMulWithXK(blindRotateTestvec.B, bTilda)
-	result := &trlwe.TRLWELv1{A: resultA, B: resultB}
+	result := &trlwe.TRLWELv1{A: resultA, B: resultB, Params: p}
 
-	tlweLv0N := params.GetTLWELv0().N
+	tlweLv0N := p.TLWELv0.N
 	for i := 0; i < tlweLv0N; i++ {
 		aTilda := int((src.P[i] + (1 << (31 - nBit - 1))) >> (32 - nBit - 1))
 
 		// Use buffer pool for rotation
 		res2A := polyEval.PolyMulWithXK(result.A, aTilda)
 		res2B := polyEval.PolyMulWithXK(result.B, aTilda)
-		res2 := &trlwe.TRLWELv1{A: res2A, B: res2B}
+		res2 := &trlwe.TRLWELv1{A: res2A, B: res2B, Params: p}
 
 		result = CMUX(result, res2, bootstrappingKey[i], decompositionOffset, polyEval)
 	}
@@ -223,16 +245,26 @@ func BlindRotate(src *tlwe.TLWELv0, blindRotateTestvec *trlwe.TRLWELv1, bootstra
 	return result
 }
 
-// evaluatorPool is a pool of evaluators for parallel operations
-var evaluatorPool = sync.Pool{
-	New: func() interface{} {
-		return poly.NewEvaluator(params.GetTRGSWLv1().N)
-	},
+// evaluatorPools holds one pool of evaluators per polynomial degree for parallel operations
+var evaluatorPools sync.Map // map[int]*sync.Pool
+
+// evaluatorPool returns the evaluator pool for polynomial degree n
+func evaluatorPool(n int) *sync.Pool {
+	if pool, ok := evaluatorPools.Load(n); ok {
+		return pool.(*sync.Pool)
+	}
+	pool, _ := evaluatorPools.LoadOrStore(n, &sync.Pool{
+		New: func() interface{} {
+			return poly.NewEvaluator(n)
+		},
+	})
+	return pool.(*sync.Pool)
 }
 
 // BatchBlindRotate performs multiple blind rotations in parallel (zero-allocation)
 func BatchBlindRotate(srcs []*tlwe.TLWELv0, blindRotateTestvec *trlwe.TRLWELv1, bootstrappingKey []*TRGSWLv1FFT, decompositionOffset params.Torus) []*trlwe.TRLWELv1 {
 	results := make([]*trlwe.TRLWELv1, len(srcs))
+	pool := evaluatorPool(len(blindRotateTestvec.A))
 	var wg sync.WaitGroup
 
 	for i, src := range srcs {
@@ -240,8 +272,8 @@ func BatchBlindRotate(srcs []*tlwe.TLWELv0, blindRotateTestvec *trlwe.TRLWELv1,
 		go func(idx int, s *tlwe.TLWELv0) {
 			defer wg.Done()
 			// Get evaluator from pool (reuse instead of allocate)
-			polyEval := evaluatorPool.Get().(*poly.Evaluator)
-			defer evaluatorPool.Put(polyEval)
+			polyEval := pool.Get().(*poly.Evaluator)
+			defer pool.Put(polyEval)
 
 			results[idx] = BlindRotate(s, blindRotateTestvec, bootstrappingKey, decompositionOffset, polyEval)
 		}(i, src)
@@ -281,15 +313,16 @@ func polyMulWithXKInPlace(a []params.Torus, k int, result []params.Torus) {
 	}
 }
 
-// IdentityKeySwitching performs identity key switching
+// IdentityKeySwitching performs identity key switching.
+// The parameter set is taken from src.Params.
 func IdentityKeySwitching(src *tlwe.TLWELv1, keySwitchingKey []*tlwe.TLWELv0) *tlwe.TLWELv0 {
-	n := params.GetTRGSWLv1().N
-	basebit := params.GetTRGSWLv1().BASEBIT
+	n := src.Params.TRGSWLv1.N
+	basebit := src.Params.TRGSWLv1.BASEBIT
 	base := 1 << basebit
-	iksT := params.GetTRGSWLv1().IKS_T
+	iksT := src.Params.TRGSWLv1.IKS_T
 
-	result := tlwe.NewTLWELv0()
-	tlweLv0N := params.GetTLWELv0().N
+	result := tlwe.NewTLWELv0WithParams(src.Params)
+	tlweLv0N := src.Params.TLWELv0.N
 	result.P[tlweLv0N] = src.P[len(src.P)-1]
 
 	precOffset := params.Torus(1 << (32 - (1 + basebit*iksT)))
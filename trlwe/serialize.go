@@ -0,0 +1,73 @@
+package trlwe
+
+import (
+	"bytes"
+	"io"
+
+	"github.com/thedonutfactory/go-tfhe/params"
+	"github.com/thedonutfactory/go-tfhe/utils"
+)
+
+// WriteTo writes the ciphertext in binary form to w.
+// The output starts with a params.Header followed by the A and B polynomials.
+func (t *TRLWELv1) WriteTo(w io.Writer) (int64, error) {
+	if t.Params == (params.Parameters{}) {
+		return 0, params.ErrNoParams
+	}
+	n, err := params.NewHeader(params.ObjectTRLWELv1, t.Params).WriteTo(w)
+	if err != nil {
+		return n, err
+	}
+	m, err := utils.WriteTorusVec(w, t.A)
+	n += m
+	if err != nil {
+		return n, err
+	}
+	m, err = utils.WriteTorusVec(w, t.B)
+	return n + m, err
+}
+
+// ReadFrom reads a ciphertext written by WriteTo from r.
+// If t already belongs to a parameter set the blob must use the same set;
+// otherwise t adopts the parameter set recorded in the header.
+func (t *TRLWELv1) ReadFrom(r io.Reader) (int64, error) {
+	var h params.Header
+	n, err := h.ReadFrom(r)
+	if err != nil {
+		return n, err
+	}
+	if err := h.Check(params.ObjectTRLWELv1); err != nil {
+		return n, err
+	}
+	if t.Params != (params.Parameters{}) {
+		if err := h.CheckParams(t.Params); err != nil {
+			return n, err
+		}
+	}
+	t.Params = h.Params
+	t.A = make([]params.Torus, h.Params.TRLWELv1.N)
+	t.B = make([]params.Torus, h.Params.TRLWELv1.N)
+	m, err := utils.ReadTorusVec(r, t.A)
+	n += m
+	if err != nil {
+		return n, err
+	}
+	m, err = utils.ReadTorusVec(r, t.B)
+	return n + m, err
+}
+
+// MarshalBinary implements encoding.BinaryMarshaler
+func (t *TRLWELv1) MarshalBinary() ([]byte, error) {
+	var buf bytes.Buffer
+	buf.Grow(params.HeaderSize + (len(t.A)+len(t.B))*4)
+	if _, err := t.WriteTo(&buf); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// UnmarshalBinary implements encoding.BinaryUnmarshaler
+func (t *TRLWELv1) UnmarshalBinary(data []byte) error {
+	_, err := t.ReadFrom(bytes.NewReader(data))
+	return err
+}
@@ -1,8 +1,7 @@
 package trlwe
 
 import (
-	"math/rand"
-
+	"github.com/thedonutfactory/go-tfhe/csprng"
 	"github.com/thedonutfactory/go-tfhe/params"
 	"github.com/thedonutfactory/go-tfhe/poly"
 	"github.com/thedonutfactory/go-tfhe/tlwe"
@@ -11,23 +10,36 @@ import (
 
 // TRLWELv1 represents a Level 1 TRLWE ciphertext
 type TRLWELv1 struct {
-	A []params.Torus
-	B []params.Torus
+	A      []params.Torus
+	B      []params.Torus
+	Params params.Parameters // Parameter set the ciphertext belongs to
 }
 
-// NewTRLWELv1 creates a new TRLWE Level 1 ciphertext
+// NewTRLWELv1 creates a new TRLWE Level 1 ciphertext for the current security level
 func NewTRLWELv1() *TRLWELv1 {
-	n := params.GetTRLWELv1().N
+	return NewTRLWELv1WithParams(params.Current())
+}
+
+// NewTRLWELv1WithParams creates a new TRLWE Level 1 ciphertext for parameter set p
+func NewTRLWELv1WithParams(p params.Parameters) *TRLWELv1 {
+	n := p.TRLWELv1.N
 	return &TRLWELv1{
-		A: make([]params.Torus, n),
-		B: make([]params.Torus, n),
+		A:      make([]params.Torus, n),
+		B:      make([]params.Torus, n),
+		Params: p,
 	}
 }
 
 // EncryptF64 encrypts a vector of float64 values with TRLWE Level 1
 func (t *TRLWELv1) EncryptF64(p []float64, alpha float64, key []params.Torus, polyEval *poly.Evaluator) *TRLWELv1 {
-	rng := rand.New(rand.NewSource(rand.Int63()))
-	n := params.GetTRLWELv1().N
+	return t.EncryptF64WithSource(p, alpha, key, polyEval, csprng.Fork())
+}
+
+// EncryptF64WithSource encrypts a vector of float64 values with TRLWE Level 1
+// drawing the mask and noise from src
+func (t *TRLWELv1) EncryptF64WithSource(p []float64, alpha float64, key []params.Torus, polyEval *poly.Evaluator, src csprng.Source) *TRLWELv1 {
+	rng := csprng.Rand(src)
+	n := len(t.A)
 
 	// Generate random a
 	for i := 0; i < n; i++ {
@@ -101,19 +113,24 @@ func NewTRLWELv1FFT(trlwe *TRLWELv1, polyEval *poly.Evaluator) *TRLWELv1FFT {
 	}
 }
 
-// NewTRLWELv1FFTDummy creates a dummy TRLWE Level 1 FFT ciphertext
+// NewTRLWELv1FFTDummy creates a dummy TRLWE Level 1 FFT ciphertext for the current security level
 func NewTRLWELv1FFTDummy() *TRLWELv1FFT {
+	return NewTRLWELv1FFTDummyWithParams(params.Current())
+}
+
+// NewTRLWELv1FFTDummyWithParams creates a dummy TRLWE Level 1 FFT ciphertext for parameter set p
+func NewTRLWELv1FFTDummyWithParams(p params.Parameters) *TRLWELv1FFT {
 	// FourierPoly needs 2*N for interleaved real/imaginary layout
 	return &TRLWELv1FFT{
-		A: make([]float64, 2*params.GetTRLWELv1().N),
-		B: make([]float64, 2*params.GetTRLWELv1().N),
+		A: make([]float64, 2*p.TRLWELv1.N),
+		B: make([]float64, 2*p.TRLWELv1.N),
 	}
 }
 
 // SampleExtractIndex extracts a TLWE sample from a TRLWE at index k
 func SampleExtractIndex(trlwe *TRLWELv1, k int) *tlwe.TLWELv1 {
-	n := params.GetTRLWELv1().N
-	result := tlwe.NewTLWELv1()
+	n := len(trlwe.A)
+	result := &tlwe.TLWELv1{P: make([]params.Torus, n+1), Params: trlwe.Params}
 
 	for i := 0; i < n; i++ {
 		if i <= k {
@@ -131,9 +148,9 @@ func SampleExtractIndex(trlwe *TRLWELv1, k int) *tlwe.TLWELv1 {
 // NOTE: This should NOT be used when TRLWE.N != TLWELv0.N
 // For Uint5 params, use proper key switching from TLWELv1 instead
 func SampleExtractIndex2(trlwe *TRLWELv1, k int) *tlwe.TLWELv0 {
-	n := params.GetTLWELv0().N
+	n := trlwe.Params.TLWELv0.N
 	trlweN := len(trlwe.A)
-	result := tlwe.NewTLWELv0()
+	result := tlwe.NewTLWELv0WithParams(trlwe.Params)
 
 	// If sizes don't match, we can't directly extract
 	// This function is only correct when trlweN == n
@@ -8,7 +8,7 @@ import (
 // SampleExtractIndexAssign extracts a TLWE sample from TRLWE at index k and writes to output
 // Zero-allocation version
 func SampleExtractIndexAssign(trlwe *TRLWELv1, k int, output *tlwe.TLWELv1) {
-	n := params.GetTRLWELv1().N
+	n := len(trlwe.A)
 
 	for i := 0; i < n; i++ {
 		if i <= k {
@@ -0,0 +1,102 @@
+package utils
+
+import (
+	"encoding/binary"
+	"io"
+	"math"
+
+	"github.com/thedonutfactory/go-tfhe/params"
+)
+
+// binaryChunk is the number of elements encoded per write/read call.
+// Keys contain tens of millions of coefficients, so we stream them through a
+// small scratch buffer instead of going through encoding/binary reflection.
+const binaryChunk = 1024
+
+// WriteTorusVec writes v to w as little-endian 32-bit words
+func WriteTorusVec(w io.Writer, v []params.Torus) (int64, error) {
+	var buf [binaryChunk * 4]byte
+	var written int64
+	for len(v) > 0 {
+		n := len(v)
+		if n > binaryChunk {
+			n = binaryChunk
+		}
+		for i := 0; i < n; i++ {
+			binary.LittleEndian.PutUint32(buf[i*4:], uint32(v[i]))
+		}
+		m, err := w.Write(buf[:n*4])
+		written += int64(m)
+		if err != nil {
+			return written, err
+		}
+		v = v[n:]
+	}
+	return written, nil
+}
+
+// ReadTorusVec fills v with little-endian 32-bit words read from r
+func ReadTorusVec(r io.Reader, v []params.Torus) (int64, error) {
+	var buf [binaryChunk * 4]byte
+	var read int64
+	for len(v) > 0 {
+		n := len(v)
+		if n > binaryChunk {
+			n = binaryChunk
+		}
+		m, err := io.ReadFull(r, buf[:n*4])
+		read += int64(m)
+		if err != nil {
+			return read, err
+		}
+		for i := 0; i < n; i++ {
+			v[i] = params.Torus(binary.LittleEndian.Uint32(buf[i*4:]))
+		}
+		v = v[n:]
+	}
+	return read, nil
+}
+
+// WriteF64Vec writes v to w as little-endian IEEE-754 doubles
+func WriteF64Vec(w io.Writer, v []float64) (int64, error) {
+	var buf [binaryChunk * 8]byte
+	var written int64
+	for len(v) > 0 {
+		n := len(v)
+		if n > binaryChunk {
+			n = binaryChunk
+		}
+		for i := 0; i < n; i++ {
+			binary.LittleEndian.PutUint64(buf[i*8:], math.Float64bits(v[i]))
+		}
+		m, err := w.Write(buf[:n*8])
+		written += int64(m)
+		if err != nil {
+			return written, err
+		}
+		v = v[n:]
+	}
+	return written, nil
+}
+
+// ReadF64Vec fills v with little-endian IEEE-754 doubles read from r
+func ReadF64Vec(r io.Reader, v []float64) (int64, error) {
+	var buf [binaryChunk * 8]byte
+	var read int64
+	for len(v) > 0 {
+		n := len(v)
+		if n > binaryChunk {
+			n = binaryChunk
+		}
+		m, err := io.ReadFull(r, buf[:n*8])
+		read += int64(m)
+		if err != nil {
+			return read, err
+		}
+		for i := 0; i < n; i++ {
+			v[i] = math.Float64frombits(binary.LittleEndian.Uint64(buf[i*8:]))
+		}
+		v = v[n:]
+	}
+	return read, nil
+}
@@ -47,3 +47,22 @@ func GaussianF64Vec(mu []float64, stddev float64, rng *rand.Rand) []params.Torus
 	}
 	return result
 }
+
+// F64ToTorus64 converts a float64 to a 64-bit Torus value. The lowest bit is
+// always 0, which is far below the 53-bit precision of d.
+func F64ToTorus64(d float64) params.Torus64 {
+	torus := math.Ldexp(math.Mod(d, 1.0), 63)
+	return params.Torus64(int64(torus)) << 1
+}
+
+// Torus64ToF64 converts a 64-bit Torus value to a float64 in range [0, 1)
+func Torus64ToF64(t params.Torus64) float64 {
+	return math.Ldexp(float64(t), -64)
+}
+
+// GaussianTorus64 samples from a Gaussian distribution and adds to mu on the
+// 64-bit torus
+func GaussianTorus64(mu params.Torus64, stddev float64, rng *rand.Rand) params.Torus64 {
+	sample := rng.NormFloat64() * stddev
+	return mu + F64ToTorus64(sample)
+}
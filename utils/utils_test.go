@@ -44,3 +44,27 @@ func TestF64ToTorusVec(t *testing.T) {
 		}
 	}
 }
+
+func TestF64ToTorus64(t *testing.T) {
+	testCases := []struct {
+		input    float64
+		expected params.Torus64
+	}{
+		{0.0, 0},
+		{0.125, 1 << 61},
+		{-0.125, 1<<64 - 1<<61},
+		{0.5, 1 << 63},
+		{0x1p-55, 1 << 9}, // Below the resolution of the 32-bit torus
+		{-0x1p-55, 1<<64 - 1<<9},
+	}
+
+	for _, tc := range testCases {
+		result := utils.F64ToTorus64(tc.input)
+		if result != tc.expected {
+			t.Errorf("F64ToTorus64(%g) = 0x%016x, expected 0x%016x", tc.input, uint64(result), uint64(tc.expected))
+		}
+		if tc.input >= 0 && utils.Torus64ToF64(result) != tc.input {
+			t.Errorf("Torus64ToF64(0x%016x) = %g, expected %g", uint64(result), utils.Torus64ToF64(result), tc.input)
+		}
+	}
+}